@@ -0,0 +1,301 @@
+// Package config reads the settings of the server. Each setting has a default and is overridden, in order,
+// by an optional file of KEY=VALUE lines, by the environment variable KEY and by the flag -key (ex: POSTGRES_HOST
+// and -postgres-host).
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// DefaultFile is read when neither -config nor CONFIG_FILE names a file, if it exists
+const DefaultFile = ".env"
+
+// Sources of a setting, from the weakest to the strongest
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// SSLModes supported by the database driver
+var SSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+type Config struct {
+	ListenAddr string
+	Database   Database
+
+	RouteTimeout     time.Duration
+	RouteTimeouts    map[string]time.Duration
+	WebhookInterval  time.Duration
+	WebhookTimeout   time.Duration
+	EventsHeartbeat  time.Duration
+	EmailGracePeriod time.Duration
+
+	MaxBatchSize           int
+	MaxCommonFriendsEmails int
+	UserImportChunkSize    int
+
+	EmailGmailRules bool
+	WebhooksEnabled bool
+	EventsEnabled   bool
+
+	settings []setting
+	sources  map[string]string
+}
+
+type Database struct {
+	Host            string
+	Port            int
+	User            string
+	Password        string
+	Name            string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// dsnValueReplacer escapes a value of the connection string before it is quoted
+var dsnValueReplacer = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// DSN is the key=value connection string of the database. The values are quoted, so a password may hold
+// spaces and the host may be the directory of a Unix socket (ex: /var/run/postgresql).
+func (_self Database) DSN() string {
+	values := [][2]string{
+		{"host", _self.Host},
+		{"port", strconv.Itoa(_self.Port)},
+		{"user", _self.User},
+		{"password", _self.Password},
+		{"dbname", _self.Name},
+		{"sslmode", _self.SSLMode},
+	}
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = value[0] + "='" + dsnValueReplacer.Replace(value[1]) + "'"
+	}
+	return strings.Join(items, " ")
+}
+
+type setting struct {
+	key    string
+	secret bool
+	value  flag.Value
+}
+
+// Load registers a flag per setting on flags, parses args and fills the settings from their sources.
+// The file is the one of -config, then of CONFIG_FILE, then DefaultFile when it exists.
+func Load(flags *flag.FlagSet, args []string, lookupEnv func(key string) (string, bool)) (*Config, error) {
+	config := &Config{
+		RouteTimeouts: map[string]time.Duration{},
+		sources:       map[string]string{},
+	}
+	config.register(flags)
+	for i := range config.settings {
+		config.settings[i].value = flags.Lookup(FlagName(config.settings[i].key)).Value
+	}
+	file := flags.String("config", "", "file of KEY=VALUE settings, "+DefaultFile+" when it exists")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	flags.Visit(func(f *flag.Flag) {
+		config.sources[f.Name] = SourceFlag
+	})
+
+	values, err := readFile(*file, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range config.settings {
+		name := FlagName(setting.key)
+		if config.sources[name] == SourceFlag {
+			continue
+		}
+		value, source := values[setting.key], SourceFile
+		if envValue, ok := lookupEnv(setting.key); ok && envValue != "" {
+			value, source = envValue, SourceEnv
+		}
+		if value == "" {
+			config.sources[name] = SourceDefault
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return nil, fmt.Errorf("%s is not valid: %v", setting.key, err)
+		}
+		config.sources[name] = source
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// register declares every setting with its default
+func (_self *Config) register(flags *flag.FlagSet) {
+	add := func(key string, secret bool) string {
+		_self.settings = append(_self.settings, setting{key: key, secret: secret})
+		return FlagName(key)
+	}
+
+	flags.StringVar(&_self.ListenAddr, add("LISTEN_ADDR", false), ":8080", "address the server listens on")
+
+	flags.StringVar(&_self.Database.Host, add("POSTGRES_HOST", false), "localhost", "database host")
+	flags.IntVar(&_self.Database.Port, add("POSTGRES_PORT", false), 5432, "database port")
+	flags.StringVar(&_self.Database.User, add("POSTGRES_USER", false), "postgres", "database user")
+	flags.StringVar(&_self.Database.Password, add("POSTGRES_PASSWORD", true), "", "database password")
+	flags.StringVar(&_self.Database.Name, add("POSTGRES_DBNAME", false), "FriendManagement", "database name")
+	flags.StringVar(&_self.Database.SSLMode, add("POSTGRES_SSLMODE", false), "disable", "database sslmode: "+strings.Join(SSLModes, ", "))
+	flags.IntVar(&_self.Database.MaxOpenConns, add("POSTGRES_MAX_OPEN_CONNS", false), 0, "most open database connections, 0 for no limit")
+	flags.IntVar(&_self.Database.MaxIdleConns, add("POSTGRES_MAX_IDLE_CONNS", false), 2, "most idle database connections")
+	flags.DurationVar(&_self.Database.ConnMaxLifetime, add("POSTGRES_CONN_MAX_LIFETIME", false), 0, "longest reuse of a database connection, 0 for no limit")
+
+	flags.DurationVar(&_self.RouteTimeout, add("ROUTE_TIMEOUT", false), 10*time.Second, "request timeout, 0s for none")
+	flags.Var(routeTimeoutsValue{&_self.RouteTimeouts}, add("ROUTE_TIMEOUTS", false), "request timeouts of route groups (ex: /friend=5s,/feed=2s)")
+	flags.DurationVar(&_self.WebhookInterval, add("WEBHOOK_INTERVAL", false), 5*time.Second, "how often the due webhooks are delivered")
+	flags.DurationVar(&_self.WebhookTimeout, add("WEBHOOK_TIMEOUT", false), 10*time.Second, "timeout of a webhook delivery")
+	flags.DurationVar(&_self.EventsHeartbeat, add("EVENTS_HEARTBEAT", false), 15*time.Second, "ping interval of an idle event stream")
+	flags.DurationVar(&_self.EmailGracePeriod, add("EMAIL_GRACE_PERIOD", false), 0, "how long a changed email address still finds its user")
+
+	flags.IntVar(&_self.MaxBatchSize, add("MAX_BATCH_SIZE", false), 1000, "most items of a batch request")
+	flags.IntVar(&_self.MaxCommonFriendsEmails, add("MAX_COMMON_FRIENDS_EMAILS", false), 20, "most email addresses of a common friends request")
+	flags.IntVar(&_self.UserImportChunkSize, add("USER_IMPORT_CHUNK_SIZE", false), 1000, "email addresses inserted per statement by a user import")
+
+	flags.BoolVar(&_self.EmailGmailRules, add("EMAIL_GMAIL_RULES", false), false, "normalize Gmail addresses")
+	flags.BoolVar(&_self.WebhooksEnabled, add("WEBHOOKS_ENABLED", false), true, "deliver the queued webhooks from this server")
+	flags.BoolVar(&_self.EventsEnabled, add("EVENTS_ENABLED", false), true, "serve the event stream of /events/stream")
+}
+
+// Validate checks the settings which their type does not
+func (_self *Config) Validate() error {
+	if _, _, err := net.SplitHostPort(_self.ListenAddr); err != nil {
+		return errors.New("LISTEN_ADDR is not valid. (ex: \":8080\")")
+	}
+	if _self.Database.Host == "" || _self.Database.User == "" || _self.Database.Name == "" {
+		return errors.New("POSTGRES_HOST, POSTGRES_USER and POSTGRES_DBNAME are required")
+	}
+	if _self.Database.Port <= 0 || _self.Database.Port > 65535 {
+		return errors.New("POSTGRES_PORT must be between 1 and 65535")
+	}
+	if !contains(SSLModes, _self.Database.SSLMode) {
+		return fmt.Errorf("POSTGRES_SSLMODE must be one of %s", strings.Join(SSLModes, ", "))
+	}
+	if _self.Database.MaxOpenConns < 0 || _self.Database.MaxIdleConns < 0 || _self.Database.ConnMaxLifetime < 0 {
+		return errors.New("POSTGRES_MAX_OPEN_CONNS, POSTGRES_MAX_IDLE_CONNS and POSTGRES_CONN_MAX_LIFETIME must not be negative")
+	}
+	if _self.RouteTimeout < 0 || _self.EmailGracePeriod < 0 {
+		return errors.New("ROUTE_TIMEOUT and EMAIL_GRACE_PERIOD must not be negative")
+	}
+	if _self.WebhookInterval <= 0 || _self.WebhookTimeout <= 0 || _self.EventsHeartbeat <= 0 {
+		return errors.New("WEBHOOK_INTERVAL, WEBHOOK_TIMEOUT and EVENTS_HEARTBEAT must be positive")
+	}
+	if _self.MaxBatchSize <= 0 || _self.UserImportChunkSize <= 0 {
+		return errors.New("MAX_BATCH_SIZE and USER_IMPORT_CHUNK_SIZE must be positive")
+	}
+	if _self.MaxCommonFriendsEmails < 2 {
+		return errors.New("MAX_COMMON_FRIENDS_EMAILS must be at least 2")
+	}
+	return nil
+}
+
+// Summary lists every setting with its source, one per line, the secrets are redacted
+func (_self *Config) Summary() string {
+	lines := make([]string, 0, len(_self.settings))
+	for _, setting := range _self.settings {
+		value := setting.value.String()
+		if setting.secret && value != "" {
+			value = "[redacted]"
+		}
+		lines = append(lines, fmt.Sprintf("%s=%s (%s)", setting.key, value, _self.sources[FlagName(setting.key)]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FlagName is the flag of the setting key, ex: "postgres-host" for POSTGRES_HOST
+func FlagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// ParseRouteTimeouts reads overrides written as "/friend=5s,/feed=2s"
+func ParseRouteTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "/") {
+			return nil, fmt.Errorf("route timeout %q is not valid. (ex: \"/friend=5s\")", item)
+		}
+		timeout, err := time.ParseDuration(parts[1])
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("route timeout %q is not valid. (ex: \"/friend=5s\")", item)
+		}
+		timeouts[parts[0]] = timeout
+	}
+	return timeouts, nil
+}
+
+// routeTimeoutsValue is the flag.Value of ROUTE_TIMEOUTS
+type routeTimeoutsValue struct {
+	timeouts *map[string]time.Duration
+}
+
+func (_self routeTimeoutsValue) String() string {
+	if _self.timeouts == nil {
+		return ""
+	}
+	items := make([]string, 0, len(*_self.timeouts))
+	for prefix, timeout := range *_self.timeouts {
+		items = append(items, prefix+"="+timeout.String())
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+func (_self routeTimeoutsValue) Set(value string) error {
+	timeouts, err := ParseRouteTimeouts(value)
+	if err != nil {
+		return err
+	}
+	*_self.timeouts = timeouts
+	return nil
+}
+
+// readFile returns the settings of the file named by path or CONFIG_FILE, or of DefaultFile when it exists
+func readFile(path string, lookupEnv func(key string) (string, bool)) (map[string]string, error) {
+	if path == "" {
+		path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); err != nil {
+			return map[string]string{}, nil
+		}
+		path = DefaultFile
+	}
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	return values, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings.env")
+	require.NoError(t, ioutil.WriteFile(file, []byte("POSTGRES_HOST=filehost\nPOSTGRES_PORT=5433\nPOSTGRES_PASSWORD=secret\nUNKNOWN=1\n"), 0600))
+
+	testCases := []struct {
+		name            string
+		args            []string
+		env             map[string]string
+		expectedErr     error
+		expectedDSN     string
+		expectedSummary map[int]string
+	}{
+		{
+			name:        "Defaults",
+			expectedDSN: "host='localhost' port='5432' user='postgres' password='' dbname='FriendManagement' sslmode='disable'",
+			expectedSummary: map[int]string{
+				0: "LISTEN_ADDR=:8080 (default)",
+				4: "POSTGRES_PASSWORD= (default)",
+			},
+		},
+		{
+			name:        "Flags override the environment which overrides the file",
+			args:        []string{"-config", file, "-postgres-host", "flaghost", "-listen-addr=:9090"},
+			env:         map[string]string{"POSTGRES_HOST": "envhost", "POSTGRES_PORT": "5434", "POSTGRES_SSLMODE": "require"},
+			expectedDSN: "host='flaghost' port='5434' user='postgres' password='secret' dbname='FriendManagement' sslmode='require'",
+			expectedSummary: map[int]string{
+				0: "LISTEN_ADDR=:9090 (flag)",
+				1: "POSTGRES_HOST=flaghost (flag)",
+				2: "POSTGRES_PORT=5434 (env)",
+				4: "POSTGRES_PASSWORD=[redacted] (file)",
+				6: "POSTGRES_SSLMODE=require (env)",
+			},
+		},
+		{
+			name:        "File is named by CONFIG_FILE",
+			env:         map[string]string{"CONFIG_FILE": file},
+			expectedDSN: "host='filehost' port='5433' user='postgres' password='secret' dbname='FriendManagement' sslmode='disable'",
+		},
+		{
+			name:        "Host is a Unix socket directory and the password is quoted",
+			env:         map[string]string{"POSTGRES_HOST": "/var/run/postgresql", "POSTGRES_PASSWORD": `it's a \secret`},
+			expectedDSN: `host='/var/run/postgresql' port='5432' user='postgres' password='it\'s a \\secret' dbname='FriendManagement' sslmode='disable'`,
+		},
+		{
+			name:        "File does not exist",
+			args:        []string{"-config", filepath.Join(t.TempDir(), "missing.env")},
+			expectedErr: errors.New("config file"),
+		},
+		{
+			name:        "Value does not parse",
+			env:         map[string]string{"POSTGRES_PORT": "five"},
+			expectedErr: errors.New("POSTGRES_PORT is not valid: parse error"),
+		},
+		{
+			name:        "Route timeouts are not valid",
+			env:         map[string]string{"ROUTE_TIMEOUTS": "/friend"},
+			expectedErr: errors.New("ROUTE_TIMEOUTS is not valid: route timeout \"/friend\" is not valid. (ex: \"/friend=5s\")"),
+		},
+		{
+			name:        "SSL mode is not supported",
+			args:        []string{"-postgres-sslmode", "prefer"},
+			expectedErr: errors.New("POSTGRES_SSLMODE must be one of disable, require, verify-ca, verify-full"),
+		},
+		{
+			name:        "Limit is not positive",
+			env:         map[string]string{"MAX_BATCH_SIZE": "0"},
+			expectedErr: errors.New("MAX_BATCH_SIZE and USER_IMPORT_CHUNK_SIZE must be positive"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := testCase.env[key]
+				return value, ok
+			}
+
+			// When
+			result, err := Load(flags, testCase.args, lookupEnv)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedDSN, result.Database.DSN())
+			lines := strings.Split(result.Summary(), "\n")
+			for i, line := range testCase.expectedSummary {
+				require.Equal(t, line, lines[i])
+			}
+		})
+	}
+}
+
+func TestLoad_Settings(t *testing.T) {
+	// Given
+	env := map[string]string{
+		"ROUTE_TIMEOUT":      "3s",
+		"ROUTE_TIMEOUTS":     "/feed=1s,/events=0s",
+		"EMAIL_GMAIL_RULES":  "true",
+		"EVENTS_ENABLED":     "false",
+		"EMAIL_GRACE_PERIOD": "720h",
+	}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	// When
+	result, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-max-batch-size=10"}, lookupEnv)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, 3*time.Second, result.RouteTimeout)
+	require.Equal(t, map[string]time.Duration{"/feed": time.Second, "/events": 0}, result.RouteTimeouts)
+	require.True(t, result.EmailGmailRules)
+	require.True(t, result.WebhooksEnabled)
+	require.False(t, result.EventsEnabled)
+	require.Equal(t, 720*time.Hour, result.EmailGracePeriod)
+	require.Equal(t, 10, result.MaxBatchSize)
+	require.Equal(t, 1000, result.UserImportChunkSize)
+	require.Contains(t, result.Summary(), "ROUTE_TIMEOUTS=/events=0s,/feed=1s (env)")
+	require.Contains(t, result.Summary(), "WEBHOOKS_ENABLED=true (default)")
+	require.Contains(t, result.Summary(), "EVENTS_ENABLED=false (env)")
+}
+
+func TestParseRouteTimeouts(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedResult map[string]time.Duration
+		expectedErr    error
+	}{
+		{
+			name:           "Empty value",
+			input:          "",
+			expectedResult: map[string]time.Duration{},
+		},
+		{
+			name:  "Several routes",
+			input: "/friend=5s, /feed=250ms,/webhook=0s",
+			expectedResult: map[string]time.Duration{
+				"/friend":  5 * time.Second,
+				"/feed":    250 * time.Millisecond,
+				"/webhook": 0,
+			},
+		},
+		{
+			name:        "Missing duration",
+			input:       "/friend",
+			expectedErr: errors.New("route timeout \"/friend\" is not valid. (ex: \"/friend=5s\")"),
+		},
+		{
+			name:        "Duration is not valid",
+			input:       "/friend=soon",
+			expectedErr: errors.New("route timeout \"/friend=soon\" is not valid. (ex: \"/friend=5s\")"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			result, err := ParseRouteTimeouts(testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
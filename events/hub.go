@@ -0,0 +1,113 @@
+package events
+
+import (
+	"sync"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+)
+
+const (
+	DefaultBufferSize     = 1024
+	DefaultSubscriberSize = 64
+)
+
+type IEventHub interface {
+	Publish(email string, eventType string, data model.EventData)
+	Subscribe(email string, lastEventID int64) ([]model.Event, <-chan model.Event, func())
+}
+
+// EventHub fans events out to the subscribers of each email in process.
+// The last events are kept in a bounded buffer so a subscriber can resume after a reconnect.
+type EventHub struct {
+	mutex       sync.Mutex
+	lastID      int64
+	buffer      []model.Event
+	next        int
+	full        bool
+	subscribers map[string]map[chan model.Event]bool
+}
+
+func NewEventHub(bufferSize int) *EventHub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &EventHub{
+		buffer:      make([]model.Event, bufferSize),
+		subscribers: make(map[string]map[chan model.Event]bool),
+	}
+}
+
+func (_self *EventHub) Publish(email string, eventType string, data model.EventData) {
+	_self.mutex.Lock()
+	defer _self.mutex.Unlock()
+
+	_self.lastID++
+	event := model.Event{
+		ID:    _self.lastID,
+		Type:  eventType,
+		Email: email,
+		Data:  data,
+	}
+	_self.buffer[_self.next] = event
+	_self.next = (_self.next + 1) % len(_self.buffer)
+	if _self.next == 0 {
+		_self.full = true
+	}
+
+	for subscriber := range _self.subscribers[email] {
+		select {
+		case subscriber <- event:
+		default:
+			//Drop subscribers which cannot keep up, they resume from the buffer with Last-Event-ID
+			_self.remove(email, subscriber)
+		}
+	}
+}
+
+// Subscribe returns the buffered events of email after lastEventID, a channel of the events published from now on
+// and a function to stop the subscription. The channel is closed when the subscriber falls too far behind.
+func (_self *EventHub) Subscribe(email string, lastEventID int64) ([]model.Event, <-chan model.Event, func()) {
+	_self.mutex.Lock()
+	defer _self.mutex.Unlock()
+
+	replay := make([]model.Event, 0)
+	if lastEventID > 0 {
+		for _, event := range _self.buffered() {
+			if event.ID > lastEventID && event.Email == email {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	subscriber := make(chan model.Event, DefaultSubscriberSize)
+	if _self.subscribers[email] == nil {
+		_self.subscribers[email] = make(map[chan model.Event]bool)
+	}
+	_self.subscribers[email][subscriber] = true
+
+	unsubscribe := func() {
+		_self.mutex.Lock()
+		defer _self.mutex.Unlock()
+		_self.remove(email, subscriber)
+	}
+	return replay, subscriber, unsubscribe
+}
+
+// buffered returns the buffered events oldest first
+func (_self *EventHub) buffered() []model.Event {
+	if !_self.full {
+		return _self.buffer[:_self.next]
+	}
+	return append(append([]model.Event{}, _self.buffer[_self.next:]...), _self.buffer[:_self.next]...)
+}
+
+func (_self *EventHub) remove(email string, subscriber chan model.Event) {
+	if !_self.subscribers[email][subscriber] {
+		return
+	}
+	delete(_self.subscribers[email], subscriber)
+	if len(_self.subscribers[email]) == 0 {
+		delete(_self.subscribers, email)
+	}
+	close(subscriber)
+}
@@ -0,0 +1,109 @@
+package events
+
+import (
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHub_PublishSubscribe(t *testing.T) {
+	// Given
+	hub := NewEventHub(10)
+	replay, stream, unsubscribe := hub.Subscribe("abc@xyz.com", 0)
+	defer unsubscribe()
+
+	// When
+	hub.Publish("xyz@abc.com", model.EventFriendAdded, model.EventData{Email: "abc@xyz.com"})
+	hub.Publish("abc@xyz.com", model.EventFriendAdded, model.EventData{Email: "xyz@abc.com"})
+
+	// Then
+	require.Empty(t, replay)
+	require.Equal(t, model.Event{
+		ID:    2,
+		Type:  model.EventFriendAdded,
+		Email: "abc@xyz.com",
+		Data:  model.EventData{Email: "xyz@abc.com"},
+	}, <-stream)
+	require.Empty(t, stream)
+}
+
+func TestEventHub_Subscribe(t *testing.T) {
+	testCases := []struct {
+		name        string
+		bufferSize  int
+		published   int
+		lastEventID int64
+		expectedIDs []int64
+	}{
+		{
+			name:        "No Last-Event-ID, nothing is replayed",
+			bufferSize:  10,
+			published:   3,
+			lastEventID: 0,
+			expectedIDs: []int64{},
+		},
+		{
+			name:        "Replay events after Last-Event-ID",
+			bufferSize:  10,
+			published:   3,
+			lastEventID: 1,
+			expectedIDs: []int64{3, 5},
+		},
+		{
+			name:        "Replay only what is left in the buffer",
+			bufferSize:  4,
+			published:   5,
+			lastEventID: 1,
+			expectedIDs: []int64{7, 9},
+		},
+		{
+			name:        "Last-Event-ID is up to date",
+			bufferSize:  10,
+			published:   3,
+			lastEventID: 5,
+			expectedIDs: []int64{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			hub := NewEventHub(testCase.bufferSize)
+			for i := 0; i < testCase.published; i++ {
+				hub.Publish("abc@xyz.com", model.EventSubscriberAdded, model.EventData{Email: "xyz@abc.com"})
+				hub.Publish("xyz@abc.com", model.EventSubscriberAdded, model.EventData{Email: "abc@xyz.com"})
+			}
+
+			// When
+			replay, _, unsubscribe := hub.Subscribe("abc@xyz.com", testCase.lastEventID)
+			defer unsubscribe()
+
+			// Then
+			replayIDs := make([]int64, 0)
+			for _, event := range replay {
+				require.Equal(t, "abc@xyz.com", event.Email)
+				replayIDs = append(replayIDs, event.ID)
+			}
+			require.Equal(t, testCase.expectedIDs, replayIDs)
+		})
+	}
+}
+
+func TestEventHub_SlowSubscriber(t *testing.T) {
+	// Given
+	hub := NewEventHub(DefaultBufferSize)
+	_, stream, unsubscribe := hub.Subscribe("abc@xyz.com", 0)
+	defer unsubscribe()
+
+	// When
+	for i := 0; i <= DefaultSubscriberSize; i++ {
+		hub.Publish("abc@xyz.com", model.EventBlocked, model.EventData{Email: "xyz@abc.com"})
+	}
+
+	// Then
+	received := 0
+	for range stream {
+		received++
+	}
+	require.Equal(t, DefaultSubscriberSize, received)
+}
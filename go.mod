@@ -1,6 +1,6 @@
 module S3_FriendManagement_ThinhNguyen
 
-go 1.15
+go 1.16
 
 require (
 	github.com/go-chi/chi v4.1.2+incompatible
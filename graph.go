@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"S3_FriendManagement_ThinhNguyen/graphio"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+// runGraphCommand writes the graph to exportPath or rebuilds an empty database from importPath.
+// CSV files go into a directory, or into a zip archive when the path ends with ".zip".
+func runGraphCommand(db *sql.DB, exportPath string, importPath string, format string) error {
+	request := model.GraphExportRequest{Format: format}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	service := services.GraphService{
+		IGraphRepo: repositories.GraphRepo{
+			Db: db,
+		},
+	}
+	ctx := context.Background()
+
+	if exportPath != "" {
+		counts, err := writeGraphFile(exportPath, request.Format, func(writer model.GraphWriter) error {
+			return service.ExportGraph(ctx, writer)
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("exported %d users, %d friend connections, %d subscriptions and %d blocks to %s",
+			counts.users, counts.edges[model.GraphRelationFriends], counts.edges[model.GraphRelationSubscriptions],
+			counts.edges[model.GraphRelationBlocks], exportPath)
+		return nil
+	}
+
+	graph, err := readGraphFile(importPath, request.Format)
+	if err != nil {
+		return err
+	}
+	if err := service.ImportGraph(ctx, graph); err != nil {
+		return err
+	}
+	log.Printf("imported %d users, %d friend connections, %d subscriptions and %d blocks from %s",
+		len(graph.Users), len(graph.Friends), len(graph.Subscriptions), len(graph.Blocks), importPath)
+	return nil
+}
+
+// writeGraphFile creates the file, or the directory of CSV files, and streams the export of export into it
+func writeGraphFile(path string, format string, export func(writer model.GraphWriter) error) (*countingGraphWriter, error) {
+	if format == model.GraphFormatCSV && filepath.Ext(path) != ".zip" {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, err
+		}
+		var file *os.File
+		writer := &countingGraphWriter{GraphWriter: graphio.NewCSVWriter(func(name string) (io.Writer, error) {
+			//Each file is fully written before the next one is created
+			if file != nil {
+				if err := file.Close(); err != nil {
+					return nil, err
+				}
+			}
+			var err error
+			file, err = os.Create(filepath.Join(path, name))
+			return file, err
+		})}
+		err := export(writer)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return writer, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	writer := &countingGraphWriter{GraphWriter: graphio.NewWriter(file, format)}
+	if err := export(writer); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, file.Close()
+}
+
+// countingGraphWriter counts the users and the edges of each relation passed to the GraphWriter
+type countingGraphWriter struct {
+	model.GraphWriter
+	users    int
+	relation string
+	edges    map[string]int
+}
+
+func (_self *countingGraphWriter) User(user model.GraphUser) error {
+	_self.users++
+	return _self.GraphWriter.User(user)
+}
+
+func (_self *countingGraphWriter) Relation(name string) error {
+	_self.relation = name
+	return _self.GraphWriter.Relation(name)
+}
+
+func (_self *countingGraphWriter) Edge(edge model.GraphEdge) error {
+	if _self.edges == nil {
+		_self.edges = make(map[string]int)
+	}
+	_self.edges[_self.relation]++
+	return _self.GraphWriter.Edge(edge)
+}
+
+func readGraphFile(path string, format string) (*model.Graph, error) {
+	if format == model.GraphFormatCSV && filepath.Ext(path) != ".zip" {
+		return graphio.ReadCSV(func(name string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(path, name))
+		})
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch format {
+	case model.GraphFormatCSV:
+		info, err := file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return graphio.ReadCSVZip(file, info.Size())
+	case model.GraphFormatGraphML:
+		return graphio.ReadGraphML(file)
+	}
+	return graphio.ReadJSON(file)
+}
@@ -0,0 +1,224 @@
+package graphio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+)
+
+// Files written by WriteCSV, one per relation
+const (
+	UsersFile         = "users.csv"
+	FriendsFile       = "friends.csv"
+	SubscriptionsFile = "subscriptions.csv"
+	BlocksFile        = "blocks.csv"
+)
+
+var (
+	usersHeader   = []string{"id", "email", "display_name", "avatar_url", "bio", "status", "created_at"}
+	friendsHeader = []string{"source", "target", "created_at"}
+	edgesHeader   = []string{"source", "target"}
+)
+
+// relationFiles are the CSV files of the relations
+var relationFiles = map[string]string{
+	model.GraphRelationFriends:       FriendsFile,
+	model.GraphRelationSubscriptions: SubscriptionsFile,
+	model.GraphRelationBlocks:        BlocksFile,
+}
+
+// WriteCSV writes one CSV file per relation, each one into the writer create returns for its name
+func WriteCSV(create func(name string) (io.Writer, error), graph *model.Graph) error {
+	return WriteGraph(NewCSVWriter(create), graph)
+}
+
+// csvWriter writes the users and then each relation into a file of its own, a file is complete
+// before the next one is created
+type csvWriter struct {
+	create   func(name string) (io.Writer, error)
+	writer   *csv.Writer
+	relation string
+}
+
+// NewCSVWriter returns a GraphWriter writing one CSV file per relation into the writer create returns for its name
+func NewCSVWriter(create func(name string) (io.Writer, error)) model.GraphWriter {
+	return &csvWriter{create: create}
+}
+
+func (_self *csvWriter) Begin(exportedAt time.Time) error {
+	return _self.open(UsersFile, usersHeader)
+}
+
+func (_self *csvWriter) User(user model.GraphUser) error {
+	return _self.writer.Write([]string{strconv.Itoa(user.ID), user.Email, user.DisplayName, user.AvatarURL, user.Bio, user.Status, formatTime(user.CreatedAt)})
+}
+
+func (_self *csvWriter) Relation(name string) error {
+	_self.relation = name
+	if name == model.GraphRelationFriends {
+		return _self.open(relationFiles[name], friendsHeader)
+	}
+	return _self.open(relationFiles[name], edgesHeader)
+}
+
+func (_self *csvWriter) Edge(edge model.GraphEdge) error {
+	record := []string{strconv.Itoa(edge.Source), strconv.Itoa(edge.Target)}
+	if _self.relation == model.GraphRelationFriends {
+		createdAt := ""
+		if edge.CreatedAt != nil {
+			createdAt = formatTime(*edge.CreatedAt)
+		}
+		record = append(record, createdAt)
+	}
+	return _self.writer.Write(record)
+}
+
+func (_self *csvWriter) End() error {
+	return _self.flush()
+}
+
+// open finishes the current file and starts the next one with its header
+func (_self *csvWriter) open(name string, header []string) error {
+	if err := _self.flush(); err != nil {
+		return err
+	}
+	w, err := _self.create(name)
+	if err != nil {
+		return err
+	}
+	_self.writer = csv.NewWriter(w)
+	return _self.writer.Write(header)
+}
+
+func (_self *csvWriter) flush() error {
+	if _self.writer == nil {
+		return nil
+	}
+	_self.writer.Flush()
+	return _self.writer.Error()
+}
+
+// ReadCSV reads the files written by WriteCSV, each one from the reader open returns for its name
+func ReadCSV(open func(name string) (io.ReadCloser, error)) (*model.Graph, error) {
+	graph := &model.Graph{}
+
+	users, err := readCSVFile(open, UsersFile, usersHeader)
+	if err != nil {
+		return nil, err
+	}
+	for i, record := range users {
+		user := model.GraphUser{
+			Email:       record[1],
+			DisplayName: record[2],
+			AvatarURL:   record[3],
+			Bio:         record[4],
+			Status:      record[5],
+		}
+		if user.ID, err = strconv.Atoi(record[0]); err != nil {
+			return nil, fmt.Errorf("%s line %d: id %q is not a number", UsersFile, i+2, record[0])
+		}
+		if user.CreatedAt, err = parseTime(record[6]); err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", UsersFile, i+2, err)
+		}
+		graph.Users = append(graph.Users, user)
+	}
+
+	friends, err := readCSVFile(open, FriendsFile, friendsHeader)
+	if err != nil {
+		return nil, err
+	}
+	if graph.Friends, err = parseEdges(FriendsFile, friends); err != nil {
+		return nil, err
+	}
+	for i, record := range friends {
+		if record[2] == "" {
+			continue
+		}
+		createdAt, err := parseTime(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", FriendsFile, i+2, err)
+		}
+		graph.Friends[i].CreatedAt = &createdAt
+	}
+
+	subscriptions, err := readCSVFile(open, SubscriptionsFile, edgesHeader)
+	if err != nil {
+		return nil, err
+	}
+	if graph.Subscriptions, err = parseEdges(SubscriptionsFile, subscriptions); err != nil {
+		return nil, err
+	}
+
+	blocks, err := readCSVFile(open, BlocksFile, edgesHeader)
+	if err != nil {
+		return nil, err
+	}
+	if graph.Blocks, err = parseEdges(BlocksFile, blocks); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// readCSVFile returns the records of a file after checking its header
+func readCSVFile(open func(name string) (io.ReadCloser, error), name string, header []string) ([][]string, error) {
+	r, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(header)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: header is missing", name)
+	}
+	for i, column := range header {
+		if records[0][i] != column {
+			return nil, fmt.Errorf("%s: column %d must be %q", name, i+1, column)
+		}
+	}
+	return records[1:], nil
+}
+
+func parseEdges(name string, records [][]string) ([]model.GraphEdge, error) {
+	edges := make([]model.GraphEdge, len(records))
+	for i, record := range records {
+		source, err := strconv.Atoi(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: source %q is not a number", name, i+2, record[0])
+		}
+		target, err := strconv.Atoi(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: target %q is not a number", name, i+2, record[1])
+		}
+		edges[i] = model.GraphEdge{Source: source, Target: target}
+	}
+	return edges, nil
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+// parseTime reads a time written by formatTime, an empty one is the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time %q is not valid. (ex: %q)", value, "2021-01-07T00:00:00Z")
+	}
+	return parsed, nil
+}
@@ -0,0 +1,177 @@
+// Package graphio writes and reads the friend graph as JSON, as CSV files and as GraphML.
+package graphio
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+)
+
+// ContentTypes of the graph formats, CSV files are sent as one zip archive
+var ContentTypes = map[string]string{
+	model.GraphFormatJSON:    "application/json",
+	model.GraphFormatCSV:     "application/zip",
+	model.GraphFormatGraphML: "application/graphml+xml",
+}
+
+// FileNames of the graph formats, CSV files are sent as one zip archive
+var FileNames = map[string]string{
+	model.GraphFormatJSON:    "graph.json",
+	model.GraphFormatCSV:     "graph.zip",
+	model.GraphFormatGraphML: "graph.graphml",
+}
+
+// NewWriter returns the GraphWriter of format writing to w, the CSV files as one zip archive closed by End
+func NewWriter(w io.Writer, format string) model.GraphWriter {
+	switch format {
+	case model.GraphFormatCSV:
+		archive := zip.NewWriter(w)
+		return &zipCSVWriter{csvWriter: &csvWriter{create: archive.Create}, archive: archive}
+	case model.GraphFormatGraphML:
+		return NewGraphMLWriter(w)
+	}
+	return NewJSONWriter(w)
+}
+
+// Write writes the graph in format to w, the CSV files as one zip archive
+func Write(w io.Writer, format string, graph *model.Graph) error {
+	return WriteGraph(NewWriter(w, format), graph)
+}
+
+// WriteGraph hands a graph held in memory to writer, in the order of an export
+func WriteGraph(writer model.GraphWriter, graph *model.Graph) error {
+	if err := writer.Begin(graph.ExportedAt); err != nil {
+		return err
+	}
+	for _, user := range graph.Users {
+		if err := writer.User(user); err != nil {
+			return err
+		}
+	}
+	relations := []struct {
+		name  string
+		edges []model.GraphEdge
+	}{
+		{model.GraphRelationFriends, graph.Friends},
+		{model.GraphRelationSubscriptions, graph.Subscriptions},
+		{model.GraphRelationBlocks, graph.Blocks},
+	}
+	for _, relation := range relations {
+		if err := writer.Relation(relation.name); err != nil {
+			return err
+		}
+		for _, edge := range relation.edges {
+			if err := writer.Edge(edge); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.End()
+}
+
+// zipCSVWriter writes the CSV files into a zip archive, one after the other
+type zipCSVWriter struct {
+	*csvWriter
+	archive *zip.Writer
+}
+
+func (_self *zipCSVWriter) End() error {
+	if err := _self.csvWriter.End(); err != nil {
+		return err
+	}
+	return _self.archive.Close()
+}
+
+func WriteJSON(w io.Writer, graph *model.Graph) error {
+	return WriteGraph(NewJSONWriter(w), graph)
+}
+
+// jsonWriter writes the same document as encoding a model.Graph, one row at a time
+type jsonWriter struct {
+	w     io.Writer
+	first bool
+}
+
+func NewJSONWriter(w io.Writer) model.GraphWriter {
+	return &jsonWriter{w: w}
+}
+
+func (_self *jsonWriter) Begin(exportedAt time.Time) error {
+	data, err := json.Marshal(exportedAt)
+	if err != nil {
+		return err
+	}
+	_self.first = true
+	return _self.write(`{"exported_at":`, string(data), `,"users":[`)
+}
+
+func (_self *jsonWriter) User(user model.GraphUser) error {
+	return _self.item(user)
+}
+
+func (_self *jsonWriter) Relation(name string) error {
+	data, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	_self.first = true
+	return _self.write(`],`, string(data), `:[`)
+}
+
+func (_self *jsonWriter) Edge(edge model.GraphEdge) error {
+	return _self.item(edge)
+}
+
+func (_self *jsonWriter) End() error {
+	return _self.write("]}\n")
+}
+
+// item writes value as the next element of the current array
+func (_self *jsonWriter) item(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if _self.first {
+		separator, _self.first = "", false
+	}
+	return _self.write(separator, string(data))
+}
+
+func (_self *jsonWriter) write(parts ...string) error {
+	for _, part := range parts {
+		if _, err := io.WriteString(_self.w, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ReadJSON(r io.Reader) (*model.Graph, error) {
+	graph := &model.Graph{}
+	if err := json.NewDecoder(r).Decode(graph); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// ReadCSVZip reads the CSV files of a zip archive written by Write
+func ReadCSVZip(r io.ReaderAt, size int64) (*model.Graph, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return ReadCSV(func(name string) (io.ReadCloser, error) {
+		for _, file := range archive.File {
+			if file.Name == name {
+				return file.Open()
+			}
+		}
+		return nil, fmt.Errorf("%s is missing from the archive", name)
+	})
+}
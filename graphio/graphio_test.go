@@ -0,0 +1,171 @@
+package graphio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() *model.Graph {
+	createdAt := time.Date(2021, 2, 3, 4, 5, 6, 7000, time.UTC)
+	return &model.Graph{
+		Users: []model.GraphUser{
+			{ID: 3, Email: "abc@xyz.com", DisplayName: "Andy, \"the first\"", Bio: "line one\nline two", Status: model.UserStatusActive, CreatedAt: createdAt},
+			{ID: 8, Email: "xyz@abc.com", AvatarURL: "https://example.com/a.png", Status: model.UserStatusAway},
+		},
+		Friends:       []model.GraphEdge{{Source: 3, Target: 8, CreatedAt: &createdAt}, {Source: 8, Target: 3}},
+		Subscriptions: []model.GraphEdge{{Source: 8, Target: 3}},
+		Blocks:        []model.GraphEdge{},
+	}
+}
+
+func TestWriteAndRead(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(w io.Writer, graph *model.Graph) error
+		read  func(data []byte) (*model.Graph, error)
+	}{
+		{
+			name: "JSON",
+			write: func(w io.Writer, graph *model.Graph) error {
+				return Write(w, model.GraphFormatJSON, graph)
+			},
+			read: func(data []byte) (*model.Graph, error) {
+				return ReadJSON(bytes.NewReader(data))
+			},
+		},
+		{
+			name: "CSV in a zip archive",
+			write: func(w io.Writer, graph *model.Graph) error {
+				return Write(w, model.GraphFormatCSV, graph)
+			},
+			read: func(data []byte) (*model.Graph, error) {
+				return ReadCSVZip(bytes.NewReader(data), int64(len(data)))
+			},
+		},
+		{
+			name: "GraphML",
+			write: func(w io.Writer, graph *model.Graph) error {
+				return Write(w, model.GraphFormatGraphML, graph)
+			},
+			read: func(data []byte) (*model.Graph, error) {
+				return ReadGraphML(bytes.NewReader(data))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			graph := testGraph()
+			buffer := &bytes.Buffer{}
+			require.NoError(t, testCase.write(buffer, graph))
+
+			//When
+			result, err := testCase.read(buffer.Bytes())
+
+			//Then
+			require.NoError(t, err)
+			require.Equal(t, graph.Users, result.Users)
+			require.Equal(t, graph.Friends, result.Friends)
+			require.Equal(t, graph.Subscriptions, result.Subscriptions)
+			require.Len(t, result.Blocks, 0)
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	//Given
+	files := map[string]*bytes.Buffer{}
+	create := func(name string) (io.Writer, error) {
+		files[name] = &bytes.Buffer{}
+		return files[name], nil
+	}
+
+	//When
+	err := WriteCSV(create, testGraph())
+
+	//Then
+	require.NoError(t, err)
+	require.Equal(t, "id,email,display_name,avatar_url,bio,status,created_at\n"+
+		"3,abc@xyz.com,\"Andy, \"\"the first\"\"\",,\"line one\nline two\",active,2021-02-03T04:05:06.000007Z\n"+
+		"8,xyz@abc.com,,https://example.com/a.png,,away,\n", files[UsersFile].String())
+	require.Equal(t, "source,target,created_at\n3,8,2021-02-03T04:05:06.000007Z\n8,3,\n", files[FriendsFile].String())
+	require.Equal(t, "source,target\n8,3\n", files[SubscriptionsFile].String())
+	require.Equal(t, "source,target\n", files[BlocksFile].String())
+}
+
+func TestReadCSV(t *testing.T) {
+	validFiles := map[string]string{
+		UsersFile:         "id,email,display_name,avatar_url,bio,status,created_at\n1,abc@xyz.com,,,,,\n",
+		FriendsFile:       "source,target,created_at\n",
+		SubscriptionsFile: "source,target\n",
+		BlocksFile:        "source,target\n",
+	}
+	testCases := []struct {
+		name        string
+		files       map[string]string
+		expectedErr error
+	}{
+		{
+			name:        "File is missing",
+			files:       map[string]string{UsersFile: validFiles[UsersFile]},
+			expectedErr: errors.New("friends.csv is missing"),
+		},
+		{
+			name: "Header is not valid",
+			files: map[string]string{
+				UsersFile: "id,mail,display_name,avatar_url,bio,status,created_at\n",
+			},
+			expectedErr: errors.New("users.csv: column 2 must be \"email\""),
+		},
+		{
+			name: "ID is not a number",
+			files: map[string]string{
+				UsersFile: "id,email,display_name,avatar_url,bio,status,created_at\none,abc@xyz.com,,,,,\n",
+			},
+			expectedErr: errors.New("users.csv line 2: id \"one\" is not a number"),
+		},
+		{
+			name: "Time is not valid",
+			files: map[string]string{
+				UsersFile:   validFiles[UsersFile],
+				FriendsFile: "source,target,created_at\n1,2,yesterday\n",
+			},
+			expectedErr: errors.New("friends.csv line 2: time \"yesterday\" is not valid. (ex: \"2021-01-07T00:00:00Z\")"),
+		},
+		{
+			name:  "Read success",
+			files: validFiles,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			open := func(name string) (io.ReadCloser, error) {
+				content, ok := testCase.files[name]
+				if !ok {
+					return nil, errors.New(name + " is missing")
+				}
+				return ioutil.NopCloser(strings.NewReader(content)), nil
+			}
+
+			//When
+			result, err := ReadCSV(open)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []model.GraphUser{{ID: 1, Email: "abc@xyz.com"}}, result.Users)
+		})
+	}
+}
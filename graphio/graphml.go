@@ -0,0 +1,262 @@
+package graphio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+// Relations of the GraphML edges, kept in their "relation" data
+const (
+	relationFriend       = "friend"
+	relationSubscription = "subscription"
+	relationBlock        = "block"
+)
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID       string        `xml:"id,attr"`
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr,omitempty"`
+	Data     []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLKeys declares the data of the nodes and edges so Gephi shows them as columns
+var graphMLKeys = []graphMLKey{
+	{ID: "email", For: "node", Name: "email", Type: "string"},
+	{ID: "display_name", For: "node", Name: "display_name", Type: "string"},
+	{ID: "avatar_url", For: "node", Name: "avatar_url", Type: "string"},
+	{ID: "bio", For: "node", Name: "bio", Type: "string"},
+	{ID: "status", For: "node", Name: "status", Type: "string"},
+	{ID: "created_at", For: "all", Name: "created_at", Type: "string"},
+	{ID: "relation", For: "edge", Name: "relation", Type: "string"},
+}
+
+// WriteGraphML writes the users as nodes and every relation as edges of one directed graph,
+// friend connections are undirected edges
+func WriteGraphML(w io.Writer, graph *model.Graph) error {
+	return WriteGraph(NewGraphMLWriter(w), graph)
+}
+
+// edgeRelations are the "relation" data of the edges of each relation
+var edgeRelations = map[string]string{
+	model.GraphRelationFriends:       relationFriend,
+	model.GraphRelationSubscriptions: relationSubscription,
+	model.GraphRelationBlocks:        relationBlock,
+}
+
+// graphMLWriter encodes the document element by element, the nodes and edges are never held together
+type graphMLWriter struct {
+	w        io.Writer
+	encoder  *xml.Encoder
+	relation string
+	edges    int
+}
+
+// NewGraphMLWriter returns a GraphWriter writing the document of WriteGraphML to w
+func NewGraphMLWriter(w io.Writer) model.GraphWriter {
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return &graphMLWriter{w: w, encoder: encoder}
+}
+
+func (_self *graphMLWriter) Begin(exportedAt time.Time) error {
+	if _, err := io.WriteString(_self.w, xml.Header); err != nil {
+		return err
+	}
+	err := _self.encoder.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "graphml"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: graphMLNamespace}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range graphMLKeys {
+		if err := _self.encoder.EncodeElement(key, xml.StartElement{Name: xml.Name{Local: "key"}}); err != nil {
+			return err
+		}
+	}
+	return _self.encoder.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "graph"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "id"}, Value: "friends"},
+			{Name: xml.Name{Local: "edgedefault"}, Value: "directed"},
+		},
+	})
+}
+
+func (_self *graphMLWriter) User(user model.GraphUser) error {
+	node := graphMLNode{
+		ID: nodeID(user.ID),
+		Data: []graphMLData{
+			{Key: "email", Value: user.Email},
+			{Key: "display_name", Value: user.DisplayName},
+			{Key: "avatar_url", Value: user.AvatarURL},
+			{Key: "bio", Value: user.Bio},
+			{Key: "status", Value: user.Status},
+			{Key: "created_at", Value: formatTime(user.CreatedAt)},
+		},
+	}
+	return _self.encoder.EncodeElement(node, xml.StartElement{Name: xml.Name{Local: "node"}})
+}
+
+func (_self *graphMLWriter) Relation(name string) error {
+	_self.relation = edgeRelations[name]
+	return nil
+}
+
+func (_self *graphMLWriter) Edge(edge model.GraphEdge) error {
+	_self.edges++
+	graphEdge := graphMLEdge{
+		ID:     "e" + strconv.Itoa(_self.edges),
+		Source: nodeID(edge.Source),
+		Target: nodeID(edge.Target),
+		Data:   []graphMLData{{Key: "relation", Value: _self.relation}},
+	}
+	if _self.relation == relationFriend {
+		graphEdge.Directed = "false"
+	}
+	if edge.CreatedAt != nil {
+		graphEdge.Data = append(graphEdge.Data, graphMLData{Key: "created_at", Value: formatTime(*edge.CreatedAt)})
+	}
+	return _self.encoder.EncodeElement(graphEdge, xml.StartElement{Name: xml.Name{Local: "edge"}})
+}
+
+func (_self *graphMLWriter) End() error {
+	if err := _self.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "graph"}}); err != nil {
+		return err
+	}
+	if err := _self.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "graphml"}}); err != nil {
+		return err
+	}
+	if err := _self.encoder.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(_self.w, "\n")
+	return err
+}
+
+// ReadGraphML reads a graph written by WriteGraphML
+func ReadGraphML(r io.Reader) (*model.Graph, error) {
+	document := graphML{}
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	graph := &model.Graph{}
+	for _, node := range document.Graph.Nodes {
+		ID, err := parseNodeID(node.ID)
+		if err != nil {
+			return nil, err
+		}
+		user := model.GraphUser{ID: ID}
+		for _, data := range node.Data {
+			switch data.Key {
+			case "email":
+				user.Email = data.Value
+			case "display_name":
+				user.DisplayName = data.Value
+			case "avatar_url":
+				user.AvatarURL = data.Value
+			case "bio":
+				user.Bio = data.Value
+			case "status":
+				user.Status = data.Value
+			case "created_at":
+				if user.CreatedAt, err = parseTime(data.Value); err != nil {
+					return nil, fmt.Errorf("node %q: %v", node.ID, err)
+				}
+			}
+		}
+		graph.Users = append(graph.Users, user)
+	}
+
+	for _, edge := range document.Graph.Edges {
+		source, err := parseNodeID(edge.Source)
+		if err != nil {
+			return nil, err
+		}
+		target, err := parseNodeID(edge.Target)
+		if err != nil {
+			return nil, err
+		}
+		graphEdge := model.GraphEdge{Source: source, Target: target}
+		relation := ""
+		for _, data := range edge.Data {
+			switch data.Key {
+			case "relation":
+				relation = data.Value
+			case "created_at":
+				createdAt, err := parseTime(data.Value)
+				if err != nil {
+					return nil, fmt.Errorf("edge %q: %v", edge.ID, err)
+				}
+				graphEdge.CreatedAt = &createdAt
+			}
+		}
+
+		switch relation {
+		case relationFriend:
+			graph.Friends = append(graph.Friends, graphEdge)
+		case relationSubscription:
+			graphEdge.CreatedAt = nil
+			graph.Subscriptions = append(graph.Subscriptions, graphEdge)
+		case relationBlock:
+			graphEdge.CreatedAt = nil
+			graph.Blocks = append(graph.Blocks, graphEdge)
+		default:
+			return nil, fmt.Errorf("edge %q: relation must be %q, %q or %q", edge.ID, relationFriend, relationSubscription, relationBlock)
+		}
+	}
+	return graph, nil
+}
+
+func nodeID(userID int) string {
+	return "n" + strconv.Itoa(userID)
+}
+
+func parseNodeID(ID string) (int, error) {
+	userID, err := strconv.Atoi(strings.TrimPrefix(ID, "n"))
+	if err != nil || !strings.HasPrefix(ID, "n") {
+		return 0, fmt.Errorf("node id %q is not valid. (ex: \"n1\")", ID)
+	}
+	return userID, nil
+}
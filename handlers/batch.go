@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+// batchResults holds the outcome of each item of a batch request, every item succeeds until it fails
+type batchResults []model.BatchItemResult
+
+func newBatchResults(size int) batchResults {
+	results := make(batchResults, size)
+	for i := range results {
+		results[i] = model.BatchItemResult{
+			Index:  i,
+			Status: http.StatusOK,
+		}
+	}
+	return results
+}
+
+func (_self batchResults) fail(index int, status int, err error) {
+	_self[index].Status = status
+	_self[index].Error = err.Error()
+}
+
+func (_self batchResults) response() model.BatchResponse {
+	created := 0
+	for _, result := range _self {
+		if result.Status == http.StatusOK {
+			created++
+		}
+	}
+	return model.BatchResponse{
+		Success: true,
+		Results: _self,
+		Created: created,
+	}
+}
+
+// batchPair is a valid item of a batch with the position it had in the request
+type batchPair struct {
+	index    int
+	emails   [2]string
+	firstID  int
+	secondID int
+}
+
+// resolveBatchPairs looks up the UserIDs of all the pairs with one query.
+// The pairs with an unknown email fail with missingFirst or missingSecond, the others are returned with their UserIDs.
+func resolveBatchPairs(ctx context.Context, userService services.IUserService, pairs []batchPair, results batchResults, missingFirst error, missingSecond error) ([]batchPair, error) {
+	if len(pairs) == 0 {
+		return pairs, nil
+	}
+
+	emails := make([]string, 0, 2*len(pairs))
+	for _, pair := range pairs {
+		emails = append(emails, pair.emails[0], pair.emails[1])
+	}
+	IDMap, err := userService.GetUserIDMapByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]batchPair, 0, len(pairs))
+	for _, pair := range pairs {
+		pair.firstID, pair.secondID = IDMap[pair.emails[0]], IDMap[pair.emails[1]]
+		if pair.firstID == 0 {
+			results.fail(pair.index, http.StatusBadRequest, missingFirst)
+			continue
+		}
+		if pair.secondID == 0 {
+			results.fail(pair.index, http.StatusBadRequest, missingSecond)
+			continue
+		}
+		resolved = append(resolved, pair)
+	}
+	return resolved, nil
+}
+
+// applyBatchErrors records the outcome of each created pair and returns the pairs which were created
+func applyBatchErrors(pairs []batchPair, createErrs []error, results batchResults) []batchPair {
+	created := make([]batchPair, 0, len(pairs))
+	for i, pair := range pairs {
+		if createErrs[i] != nil {
+			results.fail(pair.index, serviceErrorStatus(createErrs[i]), createErrs[i])
+			continue
+		}
+		created = append(created, pair)
+	}
+	return created
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 
+	"S3_FriendManagement_ThinhNguyen/events"
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/services"
 )
@@ -12,9 +14,121 @@ import (
 type BlockHandler struct {
 	IUserService     services.IUserService
 	IBlockingService services.IBlockingService
+	IEventHub        events.IEventHub
 }
 
 func (_self BlockHandler) CreateBlocking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	blockingRequest := model.BlockingRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&blockingRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if err := blockingRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Validate and get UserID by email
+	userIDList, statusCode, err := _self.createBlockingValidation(ctx, blockingRequest)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Create block services input model
+	blockingServiceInput := &model.BlockingServiceInput{
+		Requestor: userIDList[0],
+		Target:    userIDList[1],
+	}
+
+	//Call services
+	if err := _self.IBlockingService.CreateBlocking(ctx, blockingServiceInput); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	//Notify the requestor, the blocked user is not told
+	publishEvent(_self.IEventHub, blockingRequest.Requestor, model.EventBlocked, model.EventData{Email: blockingRequest.Target})
+
+	//Response
+	json.NewEncoder(w).Encode(model.SuccessResponse{
+		Success: true,
+	})
+	return
+}
+
+// CreateBlockings creates many blocks at once.
+// Each block gets the status and error its own POST /block would have answered.
+func (_self BlockHandler) CreateBlockings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	batchRequest := model.BlockingBatchRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&batchRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if err := batchRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate each block, the invalid ones are only reported
+	results := newBatchResults(len(batchRequest.Blocks))
+	pairs := make([]batchPair, 0, len(batchRequest.Blocks))
+	for i, blockingRequest := range batchRequest.Blocks {
+		if err := blockingRequest.Validate(); err != nil {
+			results.fail(i, http.StatusBadRequest, err)
+			continue
+		}
+		pairs = append(pairs, batchPair{index: i, emails: [2]string{blockingRequest.Requestor, blockingRequest.Target}})
+	}
+
+	//Get the UserIDs of every email at once
+	pairs, err := resolveBatchPairs(ctx, _self.IUserService, pairs, results,
+		errors.New("the requestor does not exist"), errors.New("the target does not exist"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(pairs) > 0 {
+		//Create block services input models
+		blockingServiceInputs := make([]model.BlockingServiceInput, len(pairs))
+		for i, pair := range pairs {
+			blockingServiceInputs[i] = model.BlockingServiceInput{
+				Requestor: pair.firstID,
+				Target:    pair.secondID,
+			}
+		}
+
+		//Call services
+		createErrs, err := _self.IBlockingService.CreateBlockings(ctx, blockingServiceInputs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		//Notify the requestors, the blocked users are not told
+		for _, pair := range applyBatchErrors(pairs, createErrs, results) {
+			publishEvent(_self.IEventHub, pair.emails[0], model.EventBlocked, model.EventData{Email: pair.emails[1]})
+		}
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(results.response())
+	return
+}
+
+func (_self BlockHandler) DeleteBlocking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	//Decode request body
 	blockingRequest := model.BlockingRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&blockingRequest); err != nil {
@@ -28,7 +142,7 @@ func (_self BlockHandler) CreateBlocking(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	// Validate and get UserID by email
-	userIDList, statusCode, err := _self.createBlockingValidation(blockingRequest)
+	userIDList, statusCode, err := _self.deleteBlockingValidation(ctx, blockingRequest)
 	if err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
@@ -41,7 +155,7 @@ func (_self BlockHandler) CreateBlocking(w http.ResponseWriter, r *http.Request)
 	}
 
 	//Call services
-	if err := _self.IBlockingService.CreateBlocking(blockingServiceInput); err != nil {
+	if err := _self.IBlockingService.DeleteBlocking(ctx, blockingServiceInput); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -53,9 +167,49 @@ func (_self BlockHandler) CreateBlocking(w http.ResponseWriter, r *http.Request)
 	return
 }
 
-func (_self BlockHandler) createBlockingValidation(blockingRequest model.BlockingRequest) ([]int, int, error) {
+func (_self BlockHandler) GetBlockingList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameter
+	blockingListRequest := model.BlockingListRequest{
+		Email: r.URL.Query().Get("email"),
+	}
+
+	// Validate request
+	if err := blockingListRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Get UserID by email
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, blockingListRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if userID == 0 {
+		http.Error(w, "email does not exist", http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	targets, err := _self.IBlockingService.GetBlockingList(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.BlockingListResponse{
+		Success: true,
+		Targets: targets,
+		Count:   len(targets),
+	})
+}
+
+func (_self BlockHandler) createBlockingValidation(ctx context.Context, blockingRequest model.BlockingRequest) ([]int, int, error) {
 	// Get user id of the requestor
-	requestorUserID, err := _self.IUserService.GetUserIDByEmail(blockingRequest.Requestor)
+	requestorUserID, err := _self.IUserService.GetUserIDByEmail(ctx, blockingRequest.Requestor)
 
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
@@ -65,7 +219,7 @@ func (_self BlockHandler) createBlockingValidation(blockingRequest model.Blockin
 	}
 
 	// Get user id of the target
-	targetUserID, err := _self.IUserService.GetUserIDByEmail(blockingRequest.Target)
+	targetUserID, err := _self.IUserService.GetUserIDByEmail(ctx, blockingRequest.Target)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -74,7 +228,7 @@ func (_self BlockHandler) createBlockingValidation(blockingRequest model.Blockin
 	}
 
 	//Check blocked
-	blocked, err := _self.IBlockingService.IsExistedBlocking(requestorUserID, targetUserID)
+	blocked, err := _self.IBlockingService.IsExistedBlocking(ctx, requestorUserID, targetUserID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -83,3 +237,33 @@ func (_self BlockHandler) createBlockingValidation(blockingRequest model.Blockin
 	}
 	return []int{requestorUserID, targetUserID}, 0, nil
 }
+
+func (_self BlockHandler) deleteBlockingValidation(ctx context.Context, blockingRequest model.BlockingRequest) ([]int, int, error) {
+	// Get user id of the requestor
+	requestorUserID, err := _self.IUserService.GetUserIDByEmail(ctx, blockingRequest.Requestor)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if requestorUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("the requestor does not exist")
+	}
+
+	// Get user id of the target
+	targetUserID, err := _self.IUserService.GetUserIDByEmail(ctx, blockingRequest.Target)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if targetUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("the target does not exist")
+	}
+
+	//Check blocked
+	blocked, err := _self.IBlockingService.IsExistedBlocking(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !blocked {
+		return nil, http.StatusNotFound, errors.New("target's email has not been blocked by requestor's email")
+	}
+	return []int{requestorUserID, targetUserID}, 0, nil
+}
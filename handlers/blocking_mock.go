@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockBlockingService struct {
 	mock.Mock
 }
 
-func (_self mockBlockingService) CreateBlocking(input *model.BlockingServiceInput) error {
+func (_self mockBlockingService) CreateBlocking(ctx context.Context, input *model.BlockingServiceInput) error {
 	args := _self.Called(input)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,17 @@ func (_self mockBlockingService) CreateBlocking(input *model.BlockingServiceInpu
 	return r
 }
 
-func (_self mockBlockingService) IsExistedBlocking(requestorID int, targetID int) (bool, error) {
+func (_self mockBlockingService) CreateBlockings(ctx context.Context, inputs []model.BlockingServiceInput) ([]error, error) {
+	args := _self.Called(inputs)
+	r0 := args.Get(0).([]error)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockBlockingService) IsExistedBlocking(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	args := _self.Called(requestorID, targetID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -27,3 +39,22 @@ func (_self mockBlockingService) IsExistedBlocking(requestorID int, targetID int
 	}
 	return r0, r1
 }
+
+func (_self mockBlockingService) DeleteBlocking(ctx context.Context, input *model.BlockingServiceInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockBlockingService) GetBlockingList(ctx context.Context, userID int) ([]string, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
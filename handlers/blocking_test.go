@@ -225,6 +225,37 @@ func TestBlockHandler_CreateBlocking(t *testing.T) {
 				err: errors.New("create blocking failed with error"),
 			},
 		},
+		{
+			name: "Blocking created by a concurrent request",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "target's email have already been blocked by requestor's email\n",
+			expectedStatus:       http.StatusPreconditionFailed,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlockedEachOther{
+				input:  []int{10, 11},
+				result: false,
+				err:    nil,
+			},
+			mockCreateBlockingService: mockCreateBlockingService{
+				input: &model.BlockingServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: model.ErrBlockingExisted,
+			},
+		},
 		{
 			name: "Create success",
 			requestBody: map[string]interface{}{
@@ -301,3 +332,449 @@ func TestBlockHandler_CreateBlocking(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockHandler_CreateBlockings(t *testing.T) {
+	type mockGetUserIDMapByEmails struct {
+		input  []string
+		result map[string]int
+		err    error
+	}
+	type mockCreateBlockings struct {
+		input  []model.BlockingServiceInput
+		result []error
+		err    error
+	}
+	testCases := []struct {
+		name                     string
+		requestBody              interface{}
+		expectedResponseBody     string
+		expectedStatus           int
+		mockGetUserIDMapByEmails mockGetUserIDMapByEmails
+		mockCreateBlockings      mockCreateBlockings
+	}{
+		{
+			name: "Body no data",
+			requestBody: map[string]interface{}{
+				"": "",
+			},
+			expectedResponseBody: "\"blocks\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Get user IDs failed with error",
+			requestBody: map[string]interface{}{
+				"blocks": []map[string]string{
+					{"requestor": "abc@xyz.com", "target": "xyz@abc.com"},
+				},
+			},
+			expectedResponseBody: "get user IDs failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input:  []string{"abc@xyz.com", "xyz@abc.com"},
+				result: nil,
+				err:    errors.New("get user IDs failed with error"),
+			},
+		},
+		{
+			name: "Each block gets its own result",
+			requestBody: map[string]interface{}{
+				"blocks": []map[string]string{
+					{"requestor": "abc@xyz.com", "target": "xyz@abc.com"},
+					{"requestor": "abc@xyz.com", "target": "new@abc.com"},
+					{"requestor": "abc@xyz.com", "target": "xyz@abc.com"},
+				},
+			},
+			expectedResponseBody: "{\"success\":true,\"results\":[" +
+				"{\"index\":0,\"status\":200}," +
+				"{\"index\":1,\"status\":400,\"error\":\"the target does not exist\"}," +
+				"{\"index\":2,\"status\":412,\"error\":\"target's email have already been blocked by requestor's email\"}" +
+				"],\"created\":1}\n",
+			expectedStatus: http.StatusOK,
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input: []string{
+					"abc@xyz.com", "xyz@abc.com",
+					"abc@xyz.com", "new@abc.com",
+					"abc@xyz.com", "xyz@abc.com",
+				},
+				result: map[string]int{"abc@xyz.com": 1, "xyz@abc.com": 2},
+			},
+			mockCreateBlockings: mockCreateBlockings{
+				input: []model.BlockingServiceInput{
+					{Requestor: 1, Target: 2},
+					{Requestor: 1, Target: 2},
+				},
+				result: []error{nil, model.ErrBlockingExisted},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockBlockingService := new(mockBlockingService)
+
+			if testCase.mockGetUserIDMapByEmails.input != nil {
+				mockUserService.On("GetUserIDMapByEmails", testCase.mockGetUserIDMapByEmails.input).
+					Return(testCase.mockGetUserIDMapByEmails.result, testCase.mockGetUserIDMapByEmails.err)
+			}
+			if testCase.mockCreateBlockings.input != nil {
+				mockBlockingService.On("CreateBlockings", testCase.mockCreateBlockings.input).
+					Return(testCase.mockCreateBlockings.result, testCase.mockCreateBlockings.err)
+			}
+
+			handlers := BlockHandler{
+				IUserService:     mockUserService,
+				IBlockingService: mockBlockingService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/block/batch", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.CreateBlockings)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestBlockHandler_DeleteBlocking(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockIsBlockedEachOther struct {
+		input  []int
+		result bool
+		err    error
+	}
+	type mockDeleteBlockingService struct {
+		input *model.BlockingServiceInput
+		err   error
+	}
+	testCases := []struct {
+		name                      string
+		requestBody               interface{}
+		expectedResponseBody      string
+		expectedStatus            int
+		mockGetRequestorUserID    mockGetUserIDByEmail
+		mockGetTargetUserID       mockGetUserIDByEmail
+		mockIsBlocked             mockIsBlockedEachOther
+		mockDeleteBlockingService mockDeleteBlockingService
+	}{
+		{
+			name: "Body no data",
+			requestBody: map[string]interface{}{
+				"": "",
+			},
+			expectedResponseBody: "\"requestor\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Target email is invalid",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "abc",
+			},
+			expectedResponseBody: "\"target\" is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Requestor userID is not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "the requestor does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name: "Check exist blocking failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlockedEachOther{
+				input:  []int{10, 11},
+				result: false,
+				err:    errors.New("failed with error"),
+			},
+		},
+		{
+			name: "Blocking does not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "target's email has not been blocked by requestor's email\n",
+			expectedStatus:       http.StatusNotFound,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlockedEachOther{
+				input:  []int{10, 11},
+				result: false,
+				err:    nil,
+			},
+		},
+		{
+			name: "Delete failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "delete blocking failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlockedEachOther{
+				input:  []int{10, 11},
+				result: true,
+				err:    nil,
+			},
+			mockDeleteBlockingService: mockDeleteBlockingService{
+				input: &model.BlockingServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: errors.New("delete blocking failed with error"),
+			},
+		},
+		{
+			name: "Delete success",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlockedEachOther{
+				input:  []int{10, 11},
+				result: true,
+				err:    nil,
+			},
+			mockDeleteBlockingService: mockDeleteBlockingService{
+				input: &model.BlockingServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockBlockingService := new(mockBlockingService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetRequestorUserID.input).
+				Return(testCase.mockGetRequestorUserID.result, testCase.mockGetRequestorUserID.err)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetTargetUserID.input).
+				Return(testCase.mockGetTargetUserID.result, testCase.mockGetTargetUserID.err)
+
+			if testCase.mockIsBlocked.input != nil {
+				mockBlockingService.On("IsExistedBlocking", testCase.mockIsBlocked.input[0], testCase.mockIsBlocked.input[1]).
+					Return(testCase.mockIsBlocked.result, testCase.mockIsBlocked.err)
+			}
+			mockBlockingService.On("DeleteBlocking", testCase.mockDeleteBlockingService.input).
+				Return(testCase.mockDeleteBlockingService.err)
+
+			handlers := BlockHandler{
+				IUserService:     mockUserService,
+				IBlockingService: mockBlockingService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodDelete, "/block", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.DeleteBlocking)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestBlockHandler_GetBlockingList(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetBlockingList struct {
+		input  int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserID        mockGetUserIDByEmail
+		mockGetBlockingList  mockGetBlockingList
+	}{
+		{
+			name:                 "Email is required",
+			query:                "",
+			expectedResponseBody: "\"email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Email format is invalid",
+			query:                "?email=abc",
+			expectedResponseBody: "\"email\" format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Get userID failed with error",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "get userID failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    errors.New("get userID failed with error"),
+			},
+		},
+		{
+			name:                 "Email does not exist",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name:                 "Get blocking list failed with error",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "get blocking list failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetBlockingList: mockGetBlockingList{
+				input:  10,
+				result: nil,
+				err:    errors.New("get blocking list failed with error"),
+			},
+		},
+		{
+			name:                 "Get blocking list success",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "{\"success\":true,\"targets\":[\"xyz@abc.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetBlockingList: mockGetBlockingList{
+				input:  10,
+				result: []string{"xyz@abc.com"},
+				err:    nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockBlockingService := new(mockBlockingService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+			mockBlockingService.On("GetBlockingList", testCase.mockGetBlockingList.input).
+				Return(testCase.mockGetBlockingList.result, testCase.mockGetBlockingList.err)
+
+			handlers := BlockHandler{
+				IUserService:     mockUserService,
+				IBlockingService: mockBlockingService,
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/block/list"+testCase.query, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetBlockingList)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Emails which used to pass validation and end up inside SQL built with fmt.Sprintf.
+// None of them may reach a service: the mocks have no expectations and panic if called.
+var injectedEmails = []string{
+	"a@b.co') or ('1'='1",
+	"abc@xyz.com' or '1'='1",
+	"abc@xyz.com'); drop table useremails; --",
+	"' union select email from useremails --abc@xyz.com",
+	"abc@xyz.com\nxyz@abc.com",
+}
+
+func TestHandlers_InjectedEmails(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		method               string
+		target               func(email string) string
+		requestBody          func(email string) interface{}
+		handler              http.HandlerFunc
+		expectedResponseBody string
+	}{
+		{
+			name:   "Create user",
+			method: http.MethodPost,
+			target: func(email string) string { return "/user" },
+			requestBody: func(email string) interface{} {
+				return map[string]interface{}{"email": email}
+			},
+			handler:              (&UserHandler{IUserService: new(mockUserService)}).CreateUser,
+			expectedResponseBody: "\"email\"'s format is not valid. (ex: \"andy@abc.xyz\")\n",
+		},
+		{
+			name:   "Create friend connection",
+			method: http.MethodPost,
+			target: func(email string) string { return "/friend" },
+			requestBody: func(email string) interface{} {
+				return map[string]interface{}{"friends": []string{"abc@xyz.com", email}}
+			},
+			handler:              FriendHandler{IUserService: new(mockUserService), IFriendServices: new(mockFriendService)}.CreateFriend,
+			expectedResponseBody: "second \"email\" is not valid. (ex: \"andy@abc.xyz\")\n",
+		},
+		{
+			name:   "Get common friends",
+			method: http.MethodGet,
+			target: func(email string) string {
+				return "/friend/common-friends?" + url.Values{"friends": {"abc@xyz.com", email}}.Encode()
+			},
+			handler: FriendHandler{IUserService: new(mockUserService), IFriendServices: new(mockFriendService)}.GetCommonFriendListByEmails,
+		},
+		{
+			name:   "Get friend list",
+			method: http.MethodGet,
+			target: func(email string) string {
+				return "/friend/friends?" + url.Values{"email": {email}}.Encode()
+			},
+			handler:              FriendHandler{IUserService: new(mockUserService), IFriendServices: new(mockFriendService)}.GetFriendListByEmail,
+			expectedResponseBody: "\"email\" format is not valid. (ex: \"andy@abc.xyz\")\n",
+		},
+		{
+			name:   "Subscribe",
+			method: http.MethodPost,
+			target: func(email string) string { return "/subscription" },
+			requestBody: func(email string) interface{} {
+				return map[string]interface{}{"requestor": email, "target": "abc@xyz.com"}
+			},
+			handler:              SubscriptionHandler{IUserService: new(mockUserService), ISubscriptionService: new(mockSubscriptionService)}.CreateSubscription,
+			expectedResponseBody: "\"requestor\" is not valid. (ex: \"andy@abc.xyz\")\n",
+		},
+		{
+			name:   "Block",
+			method: http.MethodPost,
+			target: func(email string) string { return "/block" },
+			requestBody: func(email string) interface{} {
+				return map[string]interface{}{"requestor": "abc@xyz.com", "target": email}
+			},
+			handler:              BlockHandler{IUserService: new(mockUserService), IBlockingService: new(mockBlockingService)}.CreateBlocking,
+			expectedResponseBody: "\"target\" is not valid. (ex: \"andy@abc.xyz\")\n",
+		},
+		{
+			name:   "Post an update",
+			method: http.MethodPost,
+			target: func(email string) string { return "/update" },
+			requestBody: func(email string) interface{} {
+				return map[string]interface{}{"sender": email, "text": "hello"}
+			},
+			handler:              UpdateHandler{IUserService: new(mockUserService), IUpdateService: new(mockUpdateService)}.CreateUpdate,
+			expectedResponseBody: "\"sender\" is not valid. (ex: \"andy@abc.xyz\")\n",
+		},
+	}
+	for _, testCase := range testCases {
+		for _, email := range injectedEmails {
+			t.Run(testCase.name+" "+email, func(t *testing.T) {
+				// Given
+				var requestBody []byte
+				if testCase.requestBody != nil {
+					body, err := json.Marshal(testCase.requestBody(email))
+					require.NoError(t, err)
+					requestBody = body
+				}
+				req, err := http.NewRequest(testCase.method, testCase.target(email), bytes.NewBuffer(requestBody))
+				require.NoError(t, err)
+
+				// When
+				responseRecorder := httptest.NewRecorder()
+				testCase.handler.ServeHTTP(responseRecorder, req)
+
+				// Then
+				require.Equal(t, http.StatusBadRequest, responseRecorder.Code)
+				expectedResponseBody := testCase.expectedResponseBody
+				if expectedResponseBody == "" {
+					expectedResponseBody = fmt.Sprintf("\"email\" %q is not valid. (ex: \"andy@abc.xyz\")\n", email)
+				}
+				require.Equal(t, expectedResponseBody, responseRecorder.Body.String())
+			})
+		}
+	}
+}
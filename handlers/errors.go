@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+)
+
+// serviceErrorStatus maps the errors of an insert which lost a race against another request
+// to the status the checks before the insert would have answered, anything else is a server error
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrUserExisted), errors.Is(err, model.ErrFriendExisted), errors.Is(err, model.ErrSubscriptionExisted),
+		errors.Is(err, model.ErrFriendRequestSent):
+		return http.StatusAlreadyReported
+	case errors.Is(err, model.ErrBlockedEachOther), errors.Is(err, model.ErrBlockingExisted):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, model.ErrFriendRequestClosed), errors.Is(err, model.ErrUserNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/events"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+// EventsHeartbeatInterval is how often a comment line is sent on an idle stream so proxies keep the connection open
+var EventsHeartbeatInterval = 15 * time.Second
+
+type EventsHandler struct {
+	IUserService services.IUserService
+	IEventHub    events.IEventHub
+}
+
+func (_self EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameters
+	streamRequest := model.EventStreamRequest{
+		Email: r.URL.Query().Get("email"),
+	}
+
+	//Validation
+	if err := streamRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Resume after the last event the client has seen
+	var lastEventID int64
+	if value := r.Header.Get("Last-Event-ID"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 0 {
+			http.Error(w, "\"Last-Event-ID\" must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		lastEventID = id
+	}
+
+	//Check existed email
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, streamRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if userID == 0 {
+		http.Error(w, "email does not exist", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	//Subscribe before writing anything so no event is missed between the replay and the live events
+	replay, stream, unsubscribe := _self.IEventHub.Subscribe(streamRequest.Email, lastEventID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range replay {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(EventsHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, open := <-stream:
+			if !open {
+				//The hub dropped this subscriber, the client reconnects with Last-Event-ID
+				return
+			}
+			writeEvent(w, event)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event model.Event) {
+	data, _ := json.Marshal(event.Data)
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+// publishEvent pushes an event to the stream of email when the handler has an event hub
+func publishEvent(hub events.IEventHub, email string, eventType string, data model.EventData) {
+	if hub == nil {
+		return
+	}
+	hub.Publish(email, eventType, data)
+}
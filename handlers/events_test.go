@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/events"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsHandler_StreamEvents(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		query                string
+		lastEventID          string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserID        mockGetUserIDByEmail
+	}{
+		{
+			name:                 "Email is required",
+			query:                "",
+			expectedResponseBody: "\"email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Email is not valid",
+			query:                "email=abc",
+			expectedResponseBody: "\"email\" format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Last-Event-ID is not an integer",
+			query:                "email=abc@xyz.com",
+			lastEventID:          "abc",
+			expectedResponseBody: "\"Last-Event-ID\" must be a non-negative integer\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Get user ID failed with error",
+			query:                "email=abc@xyz.com",
+			expectedResponseBody: "get user ID failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input: "abc@xyz.com",
+				err:   errors.New("get user ID failed with error"),
+			},
+		},
+		{
+			name:                 "Email does not exist",
+			query:                "email=abc@xyz.com",
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+			},
+		},
+		{
+			name:                 "Stream without Last-Event-ID",
+			query:                "email=abc@xyz.com",
+			expectedResponseBody: "",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+		},
+		{
+			name:        "Stream replays events after Last-Event-ID",
+			query:       "email=abc@xyz.com",
+			lastEventID: "1",
+			expectedResponseBody: "id: 3\nevent: subscriber.added\ndata: {\"email\":\"xyz@abc.com\"}\n\n" +
+				"id: 4\nevent: update.received\ndata: {\"email\":\"xyz@abc.com\",\"update_id\":5,\"text\":\"hello\"}\n\n",
+			expectedStatus: http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+
+			hub := events.NewEventHub(10)
+			hub.Publish("abc@xyz.com", model.EventFriendAdded, model.EventData{Email: "xyz@abc.com"})
+			hub.Publish("xyz@abc.com", model.EventFriendAdded, model.EventData{Email: "abc@xyz.com"})
+			hub.Publish("abc@xyz.com", model.EventSubscriberAdded, model.EventData{Email: "xyz@abc.com"})
+			hub.Publish("abc@xyz.com", model.EventUpdateReceived, model.EventData{Email: "xyz@abc.com", UpdateID: 5, Text: "hello"})
+
+			handlers := EventsHandler{
+				IUserService: mockUserService,
+				IEventHub:    hub,
+			}
+
+			//The client is already gone, so the stream ends right after the replay
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/events/stream?"+testCase.query, nil)
+			require.NoError(t, err)
+			if testCase.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", testCase.lastEventID)
+			}
+
+			// When
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.StreamEvents)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+			if testCase.expectedStatus == http.StatusOK {
+				require.Equal(t, "text/event-stream", responseRecorder.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 
+	"S3_FriendManagement_ThinhNguyen/events"
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/services"
 )
@@ -12,9 +16,12 @@ import (
 type FriendHandler struct {
 	IUserService    services.IUserService
 	IFriendServices services.IFriendService
+	IEventHub       events.IEventHub
 }
 
 func (_self FriendHandler) CreateFriend(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	// Decode request body
 	friendRequest := model.FriendConnectionRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&friendRequest); err != nil {
@@ -29,7 +36,7 @@ func (_self FriendHandler) CreateFriend(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Validate before creating friend
-	IDs, statusCode, err := _self.CreateFriendValidation(friendRequest)
+	IDs, statusCode, err := _self.CreateFriendValidation(ctx, friendRequest)
 	if err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
@@ -42,11 +49,128 @@ func (_self FriendHandler) CreateFriend(w http.ResponseWriter, r *http.Request)
 	}
 
 	//Call services to create friend connection
-	if err := _self.IFriendServices.CreateFriend(friendsInputModel); err != nil {
+	if err := _self.IFriendServices.CreateFriend(ctx, friendsInputModel); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	//Notify both users
+	publishEvent(_self.IEventHub, friendRequest.Friends[0], model.EventFriendAdded, model.EventData{Email: friendRequest.Friends[1]})
+	publishEvent(_self.IEventHub, friendRequest.Friends[1], model.EventFriendAdded, model.EventData{Email: friendRequest.Friends[0]})
+
+	//Response
+	json.NewEncoder(w).Encode(model.SuccessResponse{
+		Success: true,
+	})
+	return
+}
+
+// CreateFriends creates many friend connections at once.
+// Each pair gets the status and error its own POST /friend would have answered.
+func (_self FriendHandler) CreateFriends(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Decode request body
+	batchRequest := model.FriendBatchRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&batchRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validation
+	if err := batchRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate each pair, the invalid ones are only reported
+	results := newBatchResults(len(batchRequest.Friends))
+	pairs := make([]batchPair, 0, len(batchRequest.Friends))
+	for i, friends := range batchRequest.Friends {
+		friendRequest := model.FriendConnectionRequest{Friends: friends}
+		if err := friendRequest.Validate(); err != nil {
+			results.fail(i, http.StatusBadRequest, err)
+			continue
+		}
+		pairs = append(pairs, batchPair{index: i, emails: [2]string{friendRequest.Friends[0], friendRequest.Friends[1]}})
+	}
+
+	//Get the UserIDs of every email at once
+	pairs, err := resolveBatchPairs(ctx, _self.IUserService, pairs, results,
+		errors.New("the first email does not exist"), errors.New("the second email does not exist"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(pairs) > 0 {
+		//Model UserIDs services input
+		friendsInputModels := make([]model.FriendsServiceInput, len(pairs))
+		for i, pair := range pairs {
+			friendsInputModels[i] = model.FriendsServiceInput{
+				FirstID:  pair.firstID,
+				SecondID: pair.secondID,
+			}
+		}
+
+		//Call services to create the friend connections
+		createErrs, err := _self.IFriendServices.CreateFriends(ctx, friendsInputModels)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		//Notify both users of each new connection
+		for _, pair := range applyBatchErrors(pairs, createErrs, results) {
+			publishEvent(_self.IEventHub, pair.emails[0], model.EventFriendAdded, model.EventData{Email: pair.emails[1]})
+			publishEvent(_self.IEventHub, pair.emails[1], model.EventFriendAdded, model.EventData{Email: pair.emails[0]})
+		}
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(results.response())
+	return
+}
+
+func (_self FriendHandler) DeleteFriend(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Decode request body
+	friendRequest := model.FriendConnectionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&friendRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validation
+	if err := friendRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate before deleting friend
+	IDs, statusCode, err := _self.DeleteFriendValidation(ctx, friendRequest)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Model UserIDs services input
+	friendsInputModel := &model.FriendsServiceInput{
+		FirstID:  IDs[0],
+		SecondID: IDs[1],
+	}
+
+	//Call services to delete friend connection
+	if err := _self.IFriendServices.DeleteFriend(ctx, friendsInputModel); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	//Notify both users
+	publishEvent(_self.IEventHub, friendRequest.Friends[0], model.EventFriendRemoved, model.EventData{Email: friendRequest.Friends[1]})
+	publishEvent(_self.IEventHub, friendRequest.Friends[1], model.EventFriendRemoved, model.EventData{Email: friendRequest.Friends[0]})
+
 	//Response
 	json.NewEncoder(w).Encode(model.SuccessResponse{
 		Success: true,
@@ -55,9 +179,24 @@ func (_self FriendHandler) CreateFriend(w http.ResponseWriter, r *http.Request)
 }
 
 func (_self FriendHandler) GetFriendListByEmail(w http.ResponseWriter, r *http.Request) {
-	//Decode request body
+	ctx := r.Context()
+
+	//Decode query parameters or request body
 	friendRequest := model.FriendGetFriendListRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&friendRequest); err != nil {
+	if err := decodeRequest(r, &friendRequest, []string{"email", "cursor", "sort", "expand", "limit"}, func(query url.Values) error {
+		friendRequest.Email = query.Get("email")
+		friendRequest.Cursor = query.Get("cursor")
+		friendRequest.Sort = query.Get("sort")
+		friendRequest.Expand = query.Get("expand")
+		if limit := query.Get("limit"); limit != "" {
+			value, err := strconv.Atoi(limit)
+			if err != nil {
+				return errors.New("\"limit\" must be an integer")
+			}
+			friendRequest.Limit = value
+		}
+		return nil
+	}); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -69,87 +208,246 @@ func (_self FriendHandler) GetFriendListByEmail(w http.ResponseWriter, r *http.R
 	}
 
 	//Check existed email and get ID by email
-	userID, statusCode, err := _self.GetFriendListValidation(friendRequest.Email)
+	userID, statusCode, err := _self.GetFriendListValidation(ctx, friendRequest.Email)
 	if err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
 	}
 
+	//Model services input
+	listInput := &model.FriendListServiceInput{
+		UserID: userID,
+		Limit:  friendRequest.LimitOrDefault(),
+		Sort:   friendRequest.SortOrDefault(),
+		Cursor: friendRequest.DecodedCursor,
+	}
+
 	//Call services
-	friendList, err := _self.IFriendServices.GetFriendListByID(userID)
+	page, err := _self.IFriendServices.GetFriendListPageByID(ctx, listInput)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	//Response
-
+	if friendRequest.Expand == model.ExpandProfile {
+		profiles, err := _self.IUserService.GetUserProfilesByEmails(ctx, page.Friends)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(model.FriendProfilesResponse{
+			Success:    true,
+			Friends:    profiles,
+			Count:      page.Total,
+			NextCursor: page.NextCursor,
+		})
+		return
+	}
 	json.NewEncoder(w).Encode(model.FriendsResponse{
-		Success: true,
-		Friends: friendList,
-		Count:   len(friendList),
+		Success:    true,
+		Friends:    page.Friends,
+		Count:      page.Total,
+		NextCursor: page.NextCursor,
 	})
 }
 
-func (_self FriendHandler) GetCommonFriendListByEmails(w http.ResponseWriter, r *http.Request) {
-	//Decode request body
-	friendRequest := model.FriendGetCommonFriendsRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&friendRequest); err != nil {
+func (_self FriendHandler) GetFriendSuggestions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameters
+	suggestionsRequest := model.FriendSuggestionsRequest{
+		Email: r.URL.Query().Get("email"),
+		Limit: model.DefaultFriendSuggestionsLimit,
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "\"limit\" must be an integer", http.StatusBadRequest)
+			return
+		}
+		suggestionsRequest.Limit = parsedLimit
+	}
+
+	//Validation
+	if err := suggestionsRequest.Validate(); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	//Check existed email and get ID by email
+	userID, statusCode, err := _self.GetFriendListValidation(ctx, suggestionsRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Call services
+	suggestions, err := _self.IFriendServices.GetFriendSuggestions(ctx, userID, suggestionsRequest.Limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.FriendSuggestionsResponse{
+		Success:     true,
+		Suggestions: suggestions,
+		Count:       len(suggestions),
+	})
+}
+
+func (_self FriendHandler) GetFriendPath(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameters
+	pathRequest := model.FriendPathRequest{
+		From:     r.URL.Query().Get("from"),
+		To:       r.URL.Query().Get("to"),
+		MaxDepth: model.DefaultFriendPathDepth,
+	}
+	if maxDepth := r.URL.Query().Get("maxDepth"); maxDepth != "" {
+		parsedMaxDepth, err := strconv.Atoi(maxDepth)
+		if err != nil {
+			http.Error(w, "\"maxDepth\" must be an integer", http.StatusBadRequest)
+			return
+		}
+		pathRequest.MaxDepth = parsedMaxDepth
+	}
+
 	//Validation
-	if err := friendRequest.Validate(); err != nil {
+	if err := pathRequest.Validate(); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	//Check Existed email and get IDList
-	userIDList, statusCode, err := _self.GetCommonFriendListValidation(friendRequest.Friends)
+	//Check existed emails and get IDs
+	userIDList, statusCode, err := _self.GetFriendPathValidation(ctx, pathRequest)
 	if err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
 	}
 
 	//Call services
-	friendList, err := _self.IFriendServices.GetCommonFriendListByID(userIDList)
+	path, err := _self.IFriendServices.GetFriendPath(ctx, userIDList[0], userIDList[1], pathRequest.MaxDepth)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	//Response
-	json.NewEncoder(w).Encode(model.FriendsResponse{
+	response := model.FriendPathResponse{
 		Success: true,
-		Friends: friendList,
-		Count:   len(friendList),
-	})
+		Found:   len(path) > 0,
+		Path:    path,
+	}
+	if response.Found {
+		response.Degrees = len(path) - 1
+	}
+	json.NewEncoder(w).Encode(response)
 }
 
-func (_self FriendHandler) GetCommonFriendListValidation(friends []string) ([]int, int, error) {
-	//check first email
-	firstUserID, err := _self.IUserService.GetUserIDByEmail(friends[0])
+func (_self FriendHandler) GetFriendPathValidation(ctx context.Context, pathRequest model.FriendPathRequest) ([]int, int, error) {
+	fromUserID, err := _self.IUserService.GetUserIDByEmail(ctx, pathRequest.From)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
-	if firstUserID == 0 {
-		return nil, http.StatusBadRequest, errors.New("first email does not exist")
+	if fromUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("\"from\" email does not exist")
 	}
 
-	secondUserID, err := _self.IUserService.GetUserIDByEmail(friends[1])
+	toUserID, err := _self.IUserService.GetUserIDByEmail(ctx, pathRequest.To)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
-	if secondUserID == 0 {
-		return nil, http.StatusBadRequest, errors.New("second email does not exist")
+	if toUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("\"to\" email does not exist")
 	}
-	return []int{firstUserID, secondUserID}, 0, nil
+	return []int{fromUserID, toUserID}, 0, nil
+}
+
+func (_self FriendHandler) GetCommonFriendListByEmails(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode query parameters or request body
+	friendRequest := model.FriendGetCommonFriendsRequest{}
+	if err := decodeRequest(r, &friendRequest, []string{"friends", "expand"}, func(query url.Values) error {
+		friendRequest.Friends = query["friends"]
+		friendRequest.Expand = query.Get("expand")
+		return nil
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validation
+	if err := friendRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Check Existed email and get IDList
+	userIDList, unknownEmails, statusCode, err := _self.GetCommonFriendListValidation(ctx, friendRequest.Friends)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Unknown emails have no friends, so nothing can be in common
+	friendList := []string{}
+	if len(unknownEmails) == 0 {
+		//Call services
+		friendList, err = _self.IFriendServices.GetCommonFriendListByID(ctx, userIDList)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	//Response
+	if friendRequest.Expand == model.ExpandProfile {
+		profiles, err := _self.IUserService.GetUserProfilesByEmails(ctx, friendList)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(model.CommonFriendProfilesResponse{
+			Success:       true,
+			Friends:       profiles,
+			Count:         len(profiles),
+			UnknownEmails: unknownEmails,
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(model.CommonFriendsResponse{
+		Success:       true,
+		Friends:       friendList,
+		Count:         len(friendList),
+		UnknownEmails: unknownEmails,
+	})
+}
+
+// GetCommonFriendListValidation returns the UserIDs of the existing emails and the emails which do not exist
+func (_self FriendHandler) GetCommonFriendListValidation(ctx context.Context, friends []string) ([]int, []string, int, error) {
+	userIDList := make([]int, 0, len(friends))
+	unknownEmails := make([]string, 0)
+	for _, email := range friends {
+		userID, err := _self.IUserService.GetUserIDByEmail(ctx, email)
+		if err != nil {
+			return nil, nil, http.StatusInternalServerError, err
+		}
+		if userID == 0 {
+			unknownEmails = append(unknownEmails, email)
+			continue
+		}
+		userIDList = append(userIDList, userID)
+	}
+	return userIDList, unknownEmails, 0, nil
 }
 
-func (_self FriendHandler) CreateFriendValidation(friendConnectionRequest model.FriendConnectionRequest) ([]int, int, error) {
+func (_self FriendHandler) CreateFriendValidation(ctx context.Context, friendConnectionRequest model.FriendConnectionRequest) ([]int, int, error) {
 	//Check first email valid
-	firstUserID, err := _self.IUserService.GetUserIDByEmail(friendConnectionRequest.Friends[0])
+	firstUserID, err := _self.IUserService.GetUserIDByEmail(ctx, friendConnectionRequest.Friends[0])
 
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
@@ -159,7 +457,7 @@ func (_self FriendHandler) CreateFriendValidation(friendConnectionRequest model.
 	}
 
 	//Check first email valid
-	secondUserID, err := _self.IUserService.GetUserIDByEmail(friendConnectionRequest.Friends[1])
+	secondUserID, err := _self.IUserService.GetUserIDByEmail(ctx, friendConnectionRequest.Friends[1])
 
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
@@ -169,7 +467,7 @@ func (_self FriendHandler) CreateFriendValidation(friendConnectionRequest model.
 	}
 
 	// Check friend connection exists
-	existed, err := _self.IFriendServices.IsExistedFriend(firstUserID, secondUserID)
+	existed, err := _self.IFriendServices.IsExistedFriend(ctx, firstUserID, secondUserID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -178,7 +476,7 @@ func (_self FriendHandler) CreateFriendValidation(friendConnectionRequest model.
 	}
 
 	//check blocking between 2 emails
-	blocked, err := _self.IFriendServices.IsBlockedByOtherEmail(firstUserID, secondUserID)
+	blocked, err := _self.IFriendServices.IsBlockedByOtherEmail(ctx, firstUserID, secondUserID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -189,9 +487,40 @@ func (_self FriendHandler) CreateFriendValidation(friendConnectionRequest model.
 	return []int{firstUserID, secondUserID}, 0, nil
 }
 
-func (_self FriendHandler) GetFriendListValidation(email string) (int, int, error) {
+func (_self FriendHandler) DeleteFriendValidation(ctx context.Context, friendConnectionRequest model.FriendConnectionRequest) ([]int, int, error) {
 	//Check first email valid
-	userID, err := _self.IUserService.GetUserIDByEmail(email)
+	firstUserID, err := _self.IUserService.GetUserIDByEmail(ctx, friendConnectionRequest.Friends[0])
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if firstUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("the first email does not exist")
+	}
+
+	//Check second email valid
+	secondUserID, err := _self.IUserService.GetUserIDByEmail(ctx, friendConnectionRequest.Friends[1])
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if secondUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("the second email does not exist")
+	}
+
+	// Check friend connection exists
+	existed, err := _self.IFriendServices.IsExistedFriend(ctx, firstUserID, secondUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !existed {
+		return nil, http.StatusNotFound, errors.New("friend connection does not exist")
+	}
+
+	return []int{firstUserID, secondUserID}, 0, nil
+}
+
+func (_self FriendHandler) GetFriendListValidation(ctx context.Context, email string) (int, int, error) {
+	//Check first email valid
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, email)
 
 	if err != nil {
 		return 0, http.StatusInternalServerError, err
@@ -204,9 +533,15 @@ func (_self FriendHandler) GetFriendListValidation(email string) (int, int, erro
 }
 
 func (_self FriendHandler) GetEmailsReceiveUpdate(w http.ResponseWriter, r *http.Request) {
-	//decode request body
+	ctx := r.Context()
+
+	//Decode query parameters or request body
 	emailReceiveUpdateRequest := model.EmailReceiveUpdateRequest{}
-	if err := json.NewDecoder(r.Body).Decode(&emailReceiveUpdateRequest); err != nil {
+	if err := decodeRequest(r, &emailReceiveUpdateRequest, []string{"sender", "text"}, func(query url.Values) error {
+		emailReceiveUpdateRequest.Sender = query.Get("sender")
+		emailReceiveUpdateRequest.Text = query.Get("text")
+		return nil
+	}); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -218,14 +553,14 @@ func (_self FriendHandler) GetEmailsReceiveUpdate(w http.ResponseWriter, r *http
 	}
 
 	// Check existed email and get userID
-	senderID, statusCode, err := _self.GetEmailsReceiveUpdateValidation(emailReceiveUpdateRequest.Sender)
+	senderID, statusCode, err := _self.GetEmailsReceiveUpdateValidation(ctx, emailReceiveUpdateRequest.Sender)
 	if err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
 	}
 
 	//Call services
-	recipientList, err := _self.IFriendServices.GetEmailsReceiveUpdate(senderID, emailReceiveUpdateRequest.Text)
+	recipientList, err := _self.IFriendServices.GetEmailsReceiveUpdate(ctx, senderID, emailReceiveUpdateRequest.Text)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -240,8 +575,8 @@ func (_self FriendHandler) GetEmailsReceiveUpdate(w http.ResponseWriter, r *http
 
 }
 
-func (_self FriendHandler) GetEmailsReceiveUpdateValidation(email string) (int, int, error) {
-	userID, err := _self.IUserService.GetUserIDByEmail(email)
+func (_self FriendHandler) GetEmailsReceiveUpdateValidation(ctx context.Context, email string) (int, int, error) {
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, email)
 	if err != nil {
 		return 0, http.StatusInternalServerError, err
 	}
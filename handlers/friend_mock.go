@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockFriendService struct {
 	mock.Mock
 }
 
-func (_self mockFriendService) CreateFriend(model *model.FriendsServiceInput) error {
+func (_self mockFriendService) CreateFriend(ctx context.Context, model *model.FriendsServiceInput) error {
 	args := _self.Called(model)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,26 @@ func (_self mockFriendService) CreateFriend(model *model.FriendsServiceInput) er
 	return r
 }
 
-func (_self mockFriendService) IsBlockedByOtherEmail(firstUserID int, secondUserID int) (bool, error) {
+func (_self mockFriendService) CreateFriends(ctx context.Context, inputs []model.FriendsServiceInput) ([]error, error) {
+	args := _self.Called(inputs)
+	r0 := args.Get(0).([]error)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendService) DeleteFriend(ctx context.Context, model *model.FriendsServiceInput) error {
+	args := _self.Called(model)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendService) IsBlockedByOtherEmail(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
 	args := _self.Called(firstUserID, secondUserID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -28,7 +49,7 @@ func (_self mockFriendService) IsBlockedByOtherEmail(firstUserID int, secondUser
 	return r0, r1
 }
 
-func (_self mockFriendService) IsExistedFriend(firstUserID int, secondUserID int) (bool, error) {
+func (_self mockFriendService) IsExistedFriend(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
 	args := _self.Called(firstUserID, secondUserID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -38,7 +59,7 @@ func (_self mockFriendService) IsExistedFriend(firstUserID int, secondUserID int
 	return r0, r1
 }
 
-func (_self mockFriendService) GetFriendListByID(userID int) ([]string, error) {
+func (_self mockFriendService) GetFriendListByID(ctx context.Context, userID int) ([]string, error) {
 	args := _self.Called(userID)
 	r0 := args.Get(0).([]string)
 	var r1 error
@@ -48,7 +69,37 @@ func (_self mockFriendService) GetFriendListByID(userID int) ([]string, error) {
 	return r0, r1
 }
 
-func (_self mockFriendService) GetCommonFriendListByID(userIDList []int) ([]string, error) {
+func (_self mockFriendService) GetFriendListPageByID(ctx context.Context, input *model.FriendListServiceInput) (*model.FriendListPage, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(*model.FriendListPage)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendService) GetFriendSuggestions(ctx context.Context, userID int, limit int) ([]model.FriendSuggestion, error) {
+	args := _self.Called(userID, limit)
+	r0 := args.Get(0).([]model.FriendSuggestion)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendService) GetFriendPath(ctx context.Context, fromID int, toID int, maxDepth int) ([]string, error) {
+	args := _self.Called(fromID, toID, maxDepth)
+	r0 := args.Get(0).([]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendService) GetCommonFriendListByID(ctx context.Context, userIDList []int) ([]string, error) {
 	args := _self.Called(userIDList)
 	r0 := args.Get(0).([]string)
 	var r1 error
@@ -58,7 +109,7 @@ func (_self mockFriendService) GetCommonFriendListByID(userIDList []int) ([]stri
 	return r0, r1
 }
 
-func (_self mockFriendService) GetEmailsReceiveUpdate(userID int, text string) ([]string, error) {
+func (_self mockFriendService) GetEmailsReceiveUpdate(ctx context.Context, userID int, text string) ([]string, error) {
 	args := _self.Called(userID, text)
 	r0 := args.Get(0).([]string)
 	var r1 error
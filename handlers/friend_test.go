@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"S3_FriendManagement_ThinhNguyen/events"
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/require"
 )
@@ -307,6 +309,44 @@ func TestFriendHandler_CreateFriend(t *testing.T) {
 				err: errors.New("create failed with error"),
 			},
 		},
+		{
+			name: "Friend connection created by a concurrent request",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"xyz@abc.com",
+					"abc@xyz.com",
+				},
+			},
+			expectedResponseBody: "friend connection existed\n",
+			expectedStatus:       http.StatusAlreadyReported,
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetSecondUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsExistedFriend: mockIsExistedFriend{
+				input:  []int{10, 11},
+				result: false,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlockedEachOther{
+				input:  []int{10, 11},
+				result: false,
+				err:    nil,
+			},
+			mockCreateFriendService: mockCreateFriendService{
+				input: &model.FriendsServiceInput{
+					FirstID:  10,
+					SecondID: 11,
+				},
+				err: model.ErrFriendExisted,
+			},
+		},
 		{
 			name: "Create friend connection success",
 			requestBody: map[string]interface{}{
@@ -368,9 +408,15 @@ func TestFriendHandler_CreateFriend(t *testing.T) {
 					Return(testCase.mockIsBlocked.result, testCase.mockIsBlocked.err)
 			}
 
+			hub := events.NewEventHub(10)
+			_, firstStream, unsubscribeFirst := hub.Subscribe(testCase.mockGetFirstUserID.input, 0)
+			defer unsubscribeFirst()
+			_, secondStream, unsubscribeSecond := hub.Subscribe(testCase.mockGetSecondUserID.input, 0)
+			defer unsubscribeSecond()
 			handlers := FriendHandler{
 				IUserService:    mockUserService,
 				IFriendServices: mockFriendService,
+				IEventHub:       hub,
 			}
 
 			requestBody, err := json.Marshal(testCase.requestBody)
@@ -391,107 +437,130 @@ func TestFriendHandler_CreateFriend(t *testing.T) {
 			//Then
 			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
 			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+
+			//Both users are notified only when the friend connection is created
+			expectedEvents := 0
+			if testCase.expectedStatus == http.StatusOK {
+				expectedEvents = 1
+			}
+			require.Len(t, firstStream, expectedEvents)
+			require.Len(t, secondStream, expectedEvents)
 		})
 	}
 }
 
-func TestFriendHandler_GetFriendListByEmail(t *testing.T) {
-	type mockGetUserIDByEmail struct {
-		input  string
-		result int
+func TestFriendHandler_CreateFriends(t *testing.T) {
+	type mockGetUserIDMapByEmails struct {
+		input  []string
+		result map[string]int
 		err    error
 	}
-	type mockGetFriendsList struct {
-		input  int
-		result []string
+	type mockCreateFriends struct {
+		input  []model.FriendsServiceInput
+		result []error
 		err    error
 	}
 	testCases := []struct {
-		name                 string
-		requestBody          interface{}
-		expectedResponseBody string
-		expectedStatus       int
-		mockGetUserIDByEmail mockGetUserIDByEmail
-		mockGetFriendList    mockGetFriendsList
+		name                     string
+		requestBody              interface{}
+		expectedResponseBody     string
+		expectedStatus           int
+		expectedEvents           int
+		mockGetUserIDMapByEmails mockGetUserIDMapByEmails
+		mockCreateFriends        mockCreateFriends
 	}{
 		{
-			name: "Validate request body failed",
-			requestBody: map[string]interface{}{
-				"email": "",
-			},
-			expectedResponseBody: "\"Email\" is required\n",
-			expectedStatus:       http.StatusBadRequest,
-		},
-		{
-			name: "Email request format is not valid",
+			name: "Body no data",
 			requestBody: map[string]interface{}{
-				"email": "abc",
+				"": "",
 			},
-			expectedResponseBody: "\"email\" format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedResponseBody: "\"friends\" is required\n",
 			expectedStatus:       http.StatusBadRequest,
 		},
 		{
-			name: "Get UserID failed with error",
+			name: "Get user IDs failed with error",
 			requestBody: map[string]interface{}{
-				"email": "abc@xyz.com",
+				"friends": [][]string{
+					{"abc@xyz.com", "xyz@abc.com"},
+				},
 			},
-			expectedResponseBody: "get UserID failed with error\n",
+			expectedResponseBody: "get user IDs failed with error\n",
 			expectedStatus:       http.StatusInternalServerError,
-			mockGetUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@xyz.com",
-				result: 0,
-				err:    errors.New("get UserID failed with error"),
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input:  []string{"abc@xyz.com", "xyz@abc.com"},
+				result: nil,
+				err:    errors.New("get user IDs failed with error"),
 			},
 		},
 		{
-			name: "Get UserID return not exist",
+			name: "Create friends failed with error",
 			requestBody: map[string]interface{}{
-				"email": "abc@xyz.com",
+				"friends": [][]string{
+					{"abc@xyz.com", "xyz@abc.com"},
+				},
 			},
-			expectedResponseBody: "email does not exist\n",
-			expectedStatus:       http.StatusBadRequest,
-			mockGetUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@xyz.com",
-				result: 0,
-				err:    nil,
+			expectedResponseBody: "create friends failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input:  []string{"abc@xyz.com", "xyz@abc.com"},
+				result: map[string]int{"abc@xyz.com": 1, "xyz@abc.com": 2},
+			},
+			mockCreateFriends: mockCreateFriends{
+				input:  []model.FriendsServiceInput{{FirstID: 1, SecondID: 2}},
+				result: nil,
+				err:    errors.New("create friends failed with error"),
 			},
 		},
 		{
-			name: "Get friend list failed with error",
+			name: "No valid pair",
 			requestBody: map[string]interface{}{
-				"email": "abc@xyz.com",
-			},
-			expectedResponseBody: "get friend list failed with error\n",
-			expectedStatus:       http.StatusInternalServerError,
-			mockGetUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@xyz.com",
-				result: 1,
-				err:    nil,
-			},
-			mockGetFriendList: mockGetFriendsList{
-				input:  1,
-				result: nil,
-				err:    errors.New("get friend list failed with error"),
+				"friends": [][]string{
+					{"abc@xyz.com"},
+					{"abc@xyz.com", "abc@xyz.com"},
+				},
 			},
+			expectedResponseBody: "{\"success\":true,\"results\":[" +
+				"{\"index\":0,\"status\":400,\"error\":\"needs exactly two email addresses\"}," +
+				"{\"index\":1,\"status\":400,\"error\":\"two email addresses must be different\"}" +
+				"],\"created\":0}\n",
+			expectedStatus: http.StatusOK,
 		},
 		{
-			name: "Success request",
+			name: "Each pair gets its own result",
 			requestBody: map[string]interface{}{
-				"email": "abc@xyz.com",
+				"friends": [][]string{
+					{"abc@xyz.com", "xyz@abc.com"},
+					{"abc"},
+					{"ABC@xyz.com", "new@abc.com"},
+					{"abc@xyz.com", "blocked@abc.com"},
+					{"xyz@abc.com", "abc@xyz.com"},
+				},
 			},
-			expectedResponseBody: "{\"success\":true,\"friends\":[\"xyz@gmail.com\"],\"count\":1}\n",
-			expectedStatus:       http.StatusOK,
-			mockGetUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@xyz.com",
-				result: 1,
-				err:    nil,
+			expectedResponseBody: "{\"success\":true,\"results\":[" +
+				"{\"index\":0,\"status\":200}," +
+				"{\"index\":1,\"status\":400,\"error\":\"needs exactly two email addresses\"}," +
+				"{\"index\":2,\"status\":400,\"error\":\"the second email does not exist\"}," +
+				"{\"index\":3,\"status\":412,\"error\":\"emails blocked each other\"}," +
+				"{\"index\":4,\"status\":208,\"error\":\"friend connection existed\"}" +
+				"],\"created\":1}\n",
+			expectedStatus: http.StatusOK,
+			expectedEvents: 1,
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input: []string{
+					"abc@xyz.com", "xyz@abc.com",
+					"abc@xyz.com", "new@abc.com",
+					"abc@xyz.com", "blocked@abc.com",
+					"xyz@abc.com", "abc@xyz.com",
+				},
+				result: map[string]int{"abc@xyz.com": 1, "xyz@abc.com": 2, "blocked@abc.com": 3},
 			},
-			mockGetFriendList: mockGetFriendsList{
-				input: 1,
-				result: []string{
-					"xyz@gmail.com",
+			mockCreateFriends: mockCreateFriends{
+				input: []model.FriendsServiceInput{
+					{FirstID: 1, SecondID: 2},
+					{FirstID: 1, SecondID: 3},
+					{FirstID: 2, SecondID: 1},
 				},
-				err: nil,
+				result: []error{nil, model.ErrBlockedEachOther, model.ErrFriendExisted},
 			},
 		},
 	}
@@ -501,64 +570,80 @@ func TestFriendHandler_GetFriendListByEmail(t *testing.T) {
 			mockUserService := new(mockUserService)
 			mockFriendService := new(mockFriendService)
 
-			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserIDByEmail.input).
-				Return(testCase.mockGetUserIDByEmail.result, testCase.mockGetUserIDByEmail.err)
-
-			mockFriendService.On("GetFriendListByID", testCase.mockGetFriendList.input).
-				Return(testCase.mockGetFriendList.result, testCase.mockGetFriendList.err)
+			if testCase.mockGetUserIDMapByEmails.input != nil {
+				mockUserService.On("GetUserIDMapByEmails", testCase.mockGetUserIDMapByEmails.input).
+					Return(testCase.mockGetUserIDMapByEmails.result, testCase.mockGetUserIDMapByEmails.err)
+			}
+			if testCase.mockCreateFriends.input != nil {
+				mockFriendService.On("CreateFriends", testCase.mockCreateFriends.input).
+					Return(testCase.mockCreateFriends.result, testCase.mockCreateFriends.err)
+			}
 
+			hub := events.NewEventHub(10)
+			_, stream, unsubscribe := hub.Subscribe("abc@xyz.com", 0)
+			defer unsubscribe()
 			handlers := FriendHandler{
 				IUserService:    mockUserService,
 				IFriendServices: mockFriendService,
+				IEventHub:       hub,
 			}
+
 			requestBody, err := json.Marshal(testCase.requestBody)
 			if err != nil {
 				t.Error(err)
 			}
 
 			//When
-			req, err := http.NewRequest(http.MethodGet, "/friends", bytes.NewBuffer(requestBody))
+			req, err := http.NewRequest(http.MethodPost, "/friend/batch", bytes.NewBuffer(requestBody))
 			if err != nil {
 				t.Error(err)
 			}
 
 			responseRecorder := httptest.NewRecorder()
-			handler := http.HandlerFunc(handlers.GetFriendListByEmail)
+			handler := http.HandlerFunc(handlers.CreateFriends)
 			handler.ServeHTTP(responseRecorder, req)
 
 			//Then
 			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
 			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+
+			//Only the created connections are notified
+			require.Len(t, stream, testCase.expectedEvents)
 		})
 	}
 }
 
-func TestFriendHandler_GetCommonFriendListByEmails(t *testing.T) {
+func TestFriendHandler_DeleteFriend(t *testing.T) {
 	type mockGetUserIDByEmail struct {
 		input  string
 		result int
 		err    error
 	}
-	type mockGetCommonFriendList struct {
+	type mockIsExistedFriend struct {
 		input  []int
-		result []string
+		result bool
 		err    error
 	}
+	type mockDeleteFriendService struct {
+		input *model.FriendsServiceInput
+		err   error
+	}
 	testCases := []struct {
-		name                       string
-		requestBody                interface{}
-		expectedResponseBody       string
-		expectedStatus             int
-		mockGetFirstUserIDByEmail  mockGetUserIDByEmail
-		mockGetSecondUserIDByEmail mockGetUserIDByEmail
-		mockGetCommonFriendList    mockGetCommonFriendList
+		name                    string
+		requestBody             interface{}
+		expectedResponseBody    string
+		expectedStatus          int
+		mockGetFirstUserID      mockGetUserIDByEmail
+		mockGetSecondUserID     mockGetUserIDByEmail
+		mockIsExistedFriend     mockIsExistedFriend
+		mockDeleteFriendService mockDeleteFriendService
 	}{
 		{
-			name: "Validate request body failed",
+			name: "Decode failed",
 			requestBody: map[string]interface{}{
-				"friends": "././",
+				"friends": "abc",
 			},
-			expectedResponseBody: "json: cannot unmarshal string into Go struct field FriendGetCommonFriendsRequest.friends of type []string\n",
+			expectedResponseBody: "json: cannot unmarshal string into Go struct field FriendConnectionRequest.friends of type []string\n",
 			expectedStatus:       http.StatusBadRequest,
 		},
 		{
@@ -570,167 +655,879 @@ func TestFriendHandler_GetCommonFriendListByEmails(t *testing.T) {
 			expectedStatus:       http.StatusBadRequest,
 		},
 		{
-			name: "Not enough email",
-			requestBody: map[string]interface{}{
-				"friends": []string{
-					"abc",
-				},
-			},
-			expectedResponseBody: "needs exactly two email addresses\n",
-			expectedStatus:       http.StatusBadRequest,
-		},
-		{
-			name: "First email format invalid",
-			requestBody: map[string]interface{}{
-				"friends": []string{
-					"abc",
-					"xyz",
-				},
-			},
-			expectedResponseBody: "first \"email\" is not valid. (ex: \"andy@abc.xyz\")\n",
-			expectedStatus:       http.StatusBadRequest,
-		},
-		{
-			name: "Second email format invalid",
+			name: "Two email address must be different",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"xyz",
+					"xyz@abc.com",
+					"xyz@abc.com",
 				},
 			},
-			expectedResponseBody: "second \"email\" is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedResponseBody: "two email addresses must be different\n",
 			expectedStatus:       http.StatusBadRequest,
 		},
 		{
-			name: "Get UserID by email failed with error",
+			name: "Get UserID failed with error",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"xyz@gmail.com",
+					"xyz@abc.com",
+					"abc@xyz.com",
 				},
 			},
-			expectedResponseBody: "get userID failed with error\n",
+			expectedResponseBody: "get UserID failed with error\n",
 			expectedStatus:       http.StatusInternalServerError,
-			mockGetFirstUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
 				result: 0,
-				err:    errors.New("get userID failed with error"),
+				err:    errors.New("get UserID failed with error"),
 			},
 		},
 		{
-			name: "First email is not exist",
+			name: "First email address's UserID is not exist",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"xyz@gmail.com",
+					"xyz@abc.com",
+					"abc@xyz.com",
 				},
 			},
-			expectedResponseBody: "first email does not exist\n",
+			expectedResponseBody: "the first email does not exist\n",
 			expectedStatus:       http.StatusBadRequest,
-			mockGetFirstUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
 				result: 0,
 				err:    nil,
 			},
-			mockGetCommonFriendList: mockGetCommonFriendList{},
 		},
 		{
-			name: "Second email is not exist",
+			name: "Second email address's UserID is not exist",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"xyz@gmail.com",
+					"xyz@abc.com",
+					"abc@xyz.com",
 				},
 			},
-			expectedResponseBody: "second email does not exist\n",
+			expectedResponseBody: "the second email does not exist\n",
 			expectedStatus:       http.StatusBadRequest,
-			mockGetFirstUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
 				result: 10,
 				err:    nil,
 			},
-			mockGetSecondUserIDByEmail: mockGetUserIDByEmail{
-				input:  "xyz@gmail.com",
+			mockGetSecondUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
 				result: 0,
 				err:    nil,
 			},
 		},
 		{
-			name: "Two email must different each other",
+			name: "Check existed friend connection failed with error",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"abc@gmail.com",
+					"xyz@abc.com",
+					"abc@xyz.com",
 				},
 			},
-			expectedResponseBody: "two email addresses must be different\n",
-			expectedStatus:       http.StatusBadRequest,
-			mockGetFirstUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
+			expectedResponseBody: "check existed connection friend failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
 				result: 10,
 				err:    nil,
 			},
-			mockGetSecondUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
-				result: 10,
+			mockGetSecondUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 11,
 				err:    nil,
 			},
+			mockIsExistedFriend: mockIsExistedFriend{
+				input:  []int{10, 11},
+				result: false,
+				err:    errors.New("check existed connection friend failed with error"),
+			},
 		},
 		{
-			name: "Get common friend list failed with error",
+			name: "Friend connection does not exist",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"xyz@gmail.com",
+					"xyz@abc.com",
+					"abc@xyz.com",
 				},
 			},
-			expectedResponseBody: "get common friend list failed with error\n",
-			expectedStatus:       http.StatusInternalServerError,
-			mockGetFirstUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
+			expectedResponseBody: "friend connection does not exist\n",
+			expectedStatus:       http.StatusNotFound,
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
 				result: 10,
 				err:    nil,
 			},
-			mockGetSecondUserIDByEmail: mockGetUserIDByEmail{
-				input:  "xyz@gmail.com",
+			mockGetSecondUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
 				result: 11,
 				err:    nil,
 			},
-			mockGetCommonFriendList: mockGetCommonFriendList{
+			mockIsExistedFriend: mockIsExistedFriend{
 				input:  []int{10, 11},
-				result: nil,
-				err:    errors.New("get common friend list failed with error"),
+				result: false,
+				err:    nil,
 			},
 		},
 		{
-			name: "Get Success",
+			name: "Delete friend failed with error",
 			requestBody: map[string]interface{}{
 				"friends": []string{
-					"abc@gmail.com",
-					"xyz@gmail.com",
+					"xyz@abc.com",
+					"abc@xyz.com",
 				},
 			},
-			expectedResponseBody: "{\"success\":true,\"friends\":[\"abc@xyz.com\",\"xyz@abc.com\"],\"count\":2}\n",
-			expectedStatus:       http.StatusOK,
-			mockGetFirstUserIDByEmail: mockGetUserIDByEmail{
-				input:  "abc@gmail.com",
+			expectedResponseBody: "delete failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
 				result: 10,
 				err:    nil,
 			},
-			mockGetSecondUserIDByEmail: mockGetUserIDByEmail{
-				input:  "xyz@gmail.com",
+			mockGetSecondUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
 				result: 11,
 				err:    nil,
 			},
-			mockGetCommonFriendList: mockGetCommonFriendList{
-				input: []int{10, 11},
-				result: []string{
+			mockIsExistedFriend: mockIsExistedFriend{
+				input:  []int{10, 11},
+				result: true,
+				err:    nil,
+			},
+			mockDeleteFriendService: mockDeleteFriendService{
+				input: &model.FriendsServiceInput{
+					FirstID:  10,
+					SecondID: 11,
+				},
+				err: errors.New("delete failed with error"),
+			},
+		},
+		{
+			name: "Delete friend connection success",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"xyz@abc.com",
+					"abc@xyz.com",
+				},
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetFirstUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetSecondUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsExistedFriend: mockIsExistedFriend{
+				input:  []int{10, 11},
+				result: true,
+				err:    nil,
+			},
+			mockDeleteFriendService: mockDeleteFriendService{
+				input: &model.FriendsServiceInput{
+					FirstID:  10,
+					SecondID: 11,
+				},
+				err: nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockFriendService := new(mockFriendService)
+			mockUserService := new(mockUserService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetFirstUserID.input).
+				Return(testCase.mockGetFirstUserID.result, testCase.mockGetFirstUserID.err)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetSecondUserID.input).
+				Return(testCase.mockGetSecondUserID.result, testCase.mockGetSecondUserID.err)
+
+			mockFriendService.On("DeleteFriend", testCase.mockDeleteFriendService.input).
+				Return(testCase.mockDeleteFriendService.err)
+			if testCase.mockIsExistedFriend.input != nil {
+				mockFriendService.On("IsExistedFriend", testCase.mockIsExistedFriend.input[0], testCase.mockIsExistedFriend.input[1]).
+					Return(testCase.mockIsExistedFriend.result, testCase.mockIsExistedFriend.err)
+			}
+
+			handlers := FriendHandler{
+				IUserService:    mockUserService,
+				IFriendServices: mockFriendService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodDelete, "/friend", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.DeleteFriend)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestFriendHandler_GetFriendListByEmail(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetFriendsList struct {
+		input  *model.FriendListServiceInput
+		result *model.FriendListPage
+		err    error
+	}
+	type mockGetUserProfiles struct {
+		input  []string
+		result []model.UserProfile
+		err    error
+	}
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	nextCursor := model.EncodeFriendListCursor(model.FriendListCursor{
+		Sort:  model.FriendListSortEmail,
+		Email: "xyz@gmail.com",
+		ID:    2,
+	})
+	testCases := []struct {
+		name                 string
+		requestBody          interface{}
+		query                string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserIDByEmail mockGetUserIDByEmail
+		mockGetFriendList    mockGetFriendsList
+		mockGetUserProfiles  mockGetUserProfiles
+	}{
+		{
+			name: "Validate request body failed",
+			requestBody: map[string]interface{}{
+				"email": "",
+			},
+			expectedResponseBody: "\"Email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Email request format is not valid",
+			requestBody: map[string]interface{}{
+				"email": "abc",
+			},
+			expectedResponseBody: "\"email\" format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Sort is not valid",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+				"sort":  "name",
+			},
+			expectedResponseBody: "\"sort\" must be \"email\" or \"created\"\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Limit is out of range",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+				"limit": 1001,
+			},
+			expectedResponseBody: "\"limit\" must be between 1 and 1000\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Cursor is not valid",
+			requestBody: map[string]interface{}{
+				"email":  "abc@xyz.com",
+				"cursor": "not a cursor",
+			},
+			expectedResponseBody: "\"cursor\" is not valid\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Cursor belongs to another sort order",
+			requestBody: map[string]interface{}{
+				"email":  "abc@xyz.com",
+				"sort":   "created",
+				"cursor": nextCursor,
+			},
+			expectedResponseBody: "\"cursor\" is not valid\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Get UserID failed with error",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "get UserID failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    errors.New("get UserID failed with error"),
+			},
+		},
+		{
+			name: "Get UserID return not exist",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name: "Get friend list failed with error",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "get friend list failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  100,
+					Sort:   "email",
+				},
+				result: nil,
+				err:    errors.New("get friend list failed with error"),
+			},
+		},
+		{
+			name: "Success request with a body and an unrelated query parameter",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			query:                "?_=1610000000",
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"xyz@gmail.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  100,
+					Sort:   "email",
+				},
+				result: &model.FriendListPage{
+					Friends: []string{"xyz@gmail.com"},
+					Total:   1,
+				},
+			},
+		},
+		{
+			name: "Success request",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"xyz@gmail.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  100,
+					Sort:   "email",
+				},
+				result: &model.FriendListPage{
+					Friends: []string{
+						"xyz@gmail.com",
+					},
+					Total: 1,
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "Success request with next page",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+				"limit": 1,
+			},
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"xyz@gmail.com\"],\"count\":3,\"next_cursor\":\"" + nextCursor + "\"}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  1,
+					Sort:   "email",
+				},
+				result: &model.FriendListPage{
+					Friends:    []string{"xyz@gmail.com"},
+					NextCursor: nextCursor,
+					Total:      3,
+				},
+			},
+		},
+		{
+			name: "Success request from cursor",
+			requestBody: map[string]interface{}{
+				"email":  "abc@xyz.com",
+				"limit":  1,
+				"cursor": nextCursor,
+			},
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"zzz@gmail.com\"],\"count\":3}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  1,
+					Sort:   "email",
+					Cursor: &model.FriendListCursor{
+						Sort:  "email",
+						Email: "xyz@gmail.com",
+						ID:    2,
+					},
+				},
+				result: &model.FriendListPage{
+					Friends: []string{"zzz@gmail.com"},
+					Total:   3,
+				},
+			},
+		},
+		{
+			name:                 "Limit query parameter is not an integer",
+			query:                "?email=abc@xyz.com&limit=abc",
+			expectedResponseBody: "\"limit\" must be an integer\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Email query parameter format is not valid",
+			query:                "?email=abc",
+			expectedResponseBody: "\"email\" format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Success request with query parameters",
+			query:                "?email=abc@xyz.com&limit=1&sort=created",
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"xyz@gmail.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  1,
+					Sort:   "created",
+				},
+				result: &model.FriendListPage{
+					Friends: []string{"xyz@gmail.com"},
+					Total:   1,
+				},
+			},
+		},
+		{
+			name:                 "Expand is not valid",
+			query:                "?email=abc@xyz.com&expand=friends",
+			expectedResponseBody: "\"expand\" must be \"profile\"\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Get friend profiles failed with error",
+			query:                "?email=abc@xyz.com&expand=profile",
+			expectedResponseBody: "get profiles failed\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  100,
+					Sort:   "email",
+				},
+				result: &model.FriendListPage{
+					Friends: []string{"xyz@gmail.com"},
+					Total:   1,
+				},
+			},
+			mockGetUserProfiles: mockGetUserProfiles{
+				input: []string{"xyz@gmail.com"},
+				err:   errors.New("get profiles failed"),
+			},
+		},
+		{
+			name:  "Success request with profiles",
+			query: "?email=abc@xyz.com&expand=profile",
+			expectedResponseBody: "{\"success\":true,\"friends\":[{\"email\":\"xyz@gmail.com\",\"display_name\":\"Xyz\"," +
+				"\"avatar_url\":\"\",\"bio\":\"\",\"status\":\"active\",\"created_at\":\"2021-01-02T03:04:05Z\"}],\"count\":1}\n",
+			expectedStatus: http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetFriendList: mockGetFriendsList{
+				input: &model.FriendListServiceInput{
+					UserID: 1,
+					Limit:  100,
+					Sort:   "email",
+				},
+				result: &model.FriendListPage{
+					Friends: []string{"xyz@gmail.com"},
+					Total:   1,
+				},
+			},
+			mockGetUserProfiles: mockGetUserProfiles{
+				input: []string{"xyz@gmail.com"},
+				result: []model.UserProfile{
+					{Email: "xyz@gmail.com", DisplayName: "Xyz", Status: model.UserStatusActive, CreatedAt: createdAt},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockFriendService := new(mockFriendService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserIDByEmail.input).
+				Return(testCase.mockGetUserIDByEmail.result, testCase.mockGetUserIDByEmail.err)
+
+			mockFriendService.On("GetFriendListPageByID", testCase.mockGetFriendList.input).
+				Return(testCase.mockGetFriendList.result, testCase.mockGetFriendList.err)
+
+			mockUserService.On("GetUserProfilesByEmails", testCase.mockGetUserProfiles.input).
+				Return(testCase.mockGetUserProfiles.result, testCase.mockGetUserProfiles.err)
+
+			handlers := FriendHandler{
+				IUserService:    mockUserService,
+				IFriendServices: mockFriendService,
+			}
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/friends"+testCase.query, bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetFriendListByEmail)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestFriendHandler_GetCommonFriendListByEmails(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetCommonFriendList struct {
+		input  []int
+		result []string
+		err    error
+	}
+	type mockGetUserProfiles struct {
+		input  []string
+		result []model.UserProfile
+		err    error
+	}
+	testCases := []struct {
+		name                     string
+		requestBody              interface{}
+		query                    string
+		expectedResponseBody     string
+		expectedStatus           int
+		mockGetUserIDByEmailList []mockGetUserIDByEmail
+		mockGetCommonFriendList  mockGetCommonFriendList
+		mockGetUserProfiles      mockGetUserProfiles
+	}{
+		{
+			name: "Validate request body failed",
+			requestBody: map[string]interface{}{
+				"friends": "././",
+			},
+			expectedResponseBody: "json: cannot unmarshal string into Go struct field FriendGetCommonFriendsRequest.friends of type []string\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "No data request body",
+			requestBody: map[string]interface{}{
+				"": "",
+			},
+			expectedResponseBody: "\"friends\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Not enough email",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc",
+				},
+			},
+			expectedResponseBody: "needs at least two email addresses\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Too many emails",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"a1@gmail.com", "a2@gmail.com", "a3@gmail.com", "a4@gmail.com", "a5@gmail.com",
+					"a6@gmail.com", "a7@gmail.com", "a8@gmail.com", "a9@gmail.com", "a10@gmail.com",
+					"a11@gmail.com", "a12@gmail.com", "a13@gmail.com", "a14@gmail.com", "a15@gmail.com",
+					"a16@gmail.com", "a17@gmail.com", "a18@gmail.com", "a19@gmail.com", "a20@gmail.com",
+					"a21@gmail.com",
+				},
+			},
+			expectedResponseBody: "needs at most 20 email addresses\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Emails must different each other",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz@gmail.com",
+					"abc@gmail.com",
+				},
+			},
+			expectedResponseBody: "email addresses must be different\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Email format invalid",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz",
+				},
+			},
+			expectedResponseBody: "\"email\" \"xyz\" is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Get UserID by email failed with error",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz@gmail.com",
+				},
+			},
+			expectedResponseBody: "get userID failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 0,
+					err:    errors.New("get userID failed with error"),
+				},
+			},
+		},
+		{
+			name: "Unknown emails",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz@gmail.com",
+					"mno@gmail.com",
+				},
+			},
+			expectedResponseBody: "{\"success\":true,\"friends\":[],\"count\":0,\"unknown_emails\":[\"abc@gmail.com\",\"mno@gmail.com\"]}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 0,
+				},
+				{
+					input:  "xyz@gmail.com",
+					result: 11,
+				},
+				{
+					input:  "mno@gmail.com",
+					result: 0,
+				},
+			},
+		},
+		{
+			name: "Get common friend list failed with error",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz@gmail.com",
+				},
+			},
+			expectedResponseBody: "get common friend list failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 10,
+				},
+				{
+					input:  "xyz@gmail.com",
+					result: 11,
+				},
+			},
+			mockGetCommonFriendList: mockGetCommonFriendList{
+				input:  []int{10, 11},
+				result: nil,
+				err:    errors.New("get common friend list failed with error"),
+			},
+		},
+		{
+			name: "Get Success",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz@gmail.com",
+				},
+			},
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"abc@xyz.com\",\"xyz@abc.com\"],\"count\":2,\"unknown_emails\":[]}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 10,
+				},
+				{
+					input:  "xyz@gmail.com",
+					result: 11,
+				},
+			},
+			mockGetCommonFriendList: mockGetCommonFriendList{
+				input: []int{10, 11},
+				result: []string{
 					"abc@xyz.com",
 					"xyz@abc.com",
 				},
 				err: nil,
 			},
 		},
+		{
+			name: "Get Success with more than two emails",
+			requestBody: map[string]interface{}{
+				"friends": []string{
+					"abc@gmail.com",
+					"xyz@gmail.com",
+					"mno@gmail.com",
+				},
+			},
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"common@xyz.com\"],\"count\":1,\"unknown_emails\":[]}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 10,
+				},
+				{
+					input:  "xyz@gmail.com",
+					result: 11,
+				},
+				{
+					input:  "mno@gmail.com",
+					result: 12,
+				},
+			},
+			mockGetCommonFriendList: mockGetCommonFriendList{
+				input:  []int{10, 11, 12},
+				result: []string{"common@xyz.com"},
+			},
+		},
+		{
+			name:                 "Not enough email in query parameters",
+			query:                "?friends=abc@gmail.com",
+			expectedResponseBody: "needs at least two email addresses\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Get Success with query parameters",
+			query:                "?friends=abc@gmail.com&friends=xyz@gmail.com",
+			expectedResponseBody: "{\"success\":true,\"friends\":[\"common@xyz.com\"],\"count\":1,\"unknown_emails\":[]}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 10,
+				},
+				{
+					input:  "xyz@gmail.com",
+					result: 11,
+				},
+			},
+			mockGetCommonFriendList: mockGetCommonFriendList{
+				input:  []int{10, 11},
+				result: []string{"common@xyz.com"},
+			},
+		},
+		{
+			name:  "Get Success with profiles",
+			query: "?friends=abc@gmail.com&friends=xyz@gmail.com&expand=profile",
+			expectedResponseBody: "{\"success\":true,\"friends\":[{\"email\":\"common@xyz.com\",\"display_name\":\"\"," +
+				"\"avatar_url\":\"\",\"bio\":\"Hi\",\"status\":\"busy\",\"created_at\":\"0001-01-01T00:00:00Z\"}],\"count\":1,\"unknown_emails\":[]}\n",
+			expectedStatus: http.StatusOK,
+			mockGetUserIDByEmailList: []mockGetUserIDByEmail{
+				{
+					input:  "abc@gmail.com",
+					result: 10,
+				},
+				{
+					input:  "xyz@gmail.com",
+					result: 11,
+				},
+			},
+			mockGetCommonFriendList: mockGetCommonFriendList{
+				input:  []int{10, 11},
+				result: []string{"common@xyz.com"},
+			},
+			mockGetUserProfiles: mockGetUserProfiles{
+				input: []string{"common@xyz.com"},
+				result: []model.UserProfile{
+					{Email: "common@xyz.com", Bio: "Hi", Status: model.UserStatusBusy},
+				},
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -738,14 +1535,17 @@ func TestFriendHandler_GetCommonFriendListByEmails(t *testing.T) {
 			mockUserService := new(mockUserService)
 			mockFriendService := new(mockFriendService)
 
-			mockUserService.On("GetUserIDByEmail", testCase.mockGetFirstUserIDByEmail.input).
-				Return(testCase.mockGetFirstUserIDByEmail.result, testCase.mockGetFirstUserIDByEmail.err)
-			mockUserService.On("GetUserIDByEmail", testCase.mockGetSecondUserIDByEmail.input).
-				Return(testCase.mockGetSecondUserIDByEmail.result, testCase.mockGetSecondUserIDByEmail.err)
+			for _, mockGetUserID := range testCase.mockGetUserIDByEmailList {
+				mockUserService.On("GetUserIDByEmail", mockGetUserID.input).
+					Return(mockGetUserID.result, mockGetUserID.err)
+			}
 
 			mockFriendService.On("GetCommonFriendListByID", testCase.mockGetCommonFriendList.input).
 				Return(testCase.mockGetCommonFriendList.result, testCase.mockGetCommonFriendList.err)
 
+			mockUserService.On("GetUserProfilesByEmails", testCase.mockGetUserProfiles.input).
+				Return(testCase.mockGetUserProfiles.result, testCase.mockGetUserProfiles.err)
+
 			handlers := FriendHandler{
 				IUserService:    mockUserService,
 				IFriendServices: mockFriendService,
@@ -756,7 +1556,7 @@ func TestFriendHandler_GetCommonFriendListByEmails(t *testing.T) {
 				t.Error(err)
 			}
 			//When
-			req, err := http.NewRequest(http.MethodGet, "/friend/common-friend", bytes.NewBuffer(requestBody))
+			req, err := http.NewRequest(http.MethodGet, "/friend/common-friend"+testCase.query, bytes.NewBuffer(requestBody))
 
 			responseRecorder := httptest.NewRecorder()
 			handler := http.HandlerFunc(handlers.GetCommonFriendListByEmails)
@@ -784,6 +1584,7 @@ func TestFriendHandler_GetEmailsReceiveUpdate(t *testing.T) {
 	testCases := []struct {
 		name                       string
 		requestBody                interface{}
+		query                      string
 		expectedResponseBody       string
 		expectedStatus             int
 		mockGetSenderUserID        mockGetUserIDByEmail
@@ -862,6 +1663,27 @@ func TestFriendHandler_GetEmailsReceiveUpdate(t *testing.T) {
 				err:    nil,
 			},
 		},
+		{
+			name:                 "No text in query parameters",
+			query:                "?sender=abc@xyz.com",
+			expectedResponseBody: "\"text\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Get success with query parameters",
+			query:                "?sender=abc@xyz.com&text=hello+another%40gmail.com",
+			expectedResponseBody: "{\"success\":true,\"recipients\":[\"lmk@xyz.com\",\"another@gmail.com\"]}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetSenderUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetEmailsReceiveUpdate: mockGetEmailsReceiveUpdate{
+				sender: 10,
+				text:   "hello another@gmail.com",
+				result: []string{"lmk@xyz.com", "another@gmail.com"},
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -887,7 +1709,7 @@ func TestFriendHandler_GetEmailsReceiveUpdate(t *testing.T) {
 			}
 
 			// When
-			req, err := http.NewRequest(http.MethodGet, "/friends/emails-receiving-update", bytes.NewBuffer(requestBody))
+			req, err := http.NewRequest(http.MethodGet, "/friends/emails-receiving-update"+testCase.query, bytes.NewBuffer(requestBody))
 			if err != nil {
 				t.Error(err)
 			}
@@ -903,3 +1725,266 @@ func TestFriendHandler_GetEmailsReceiveUpdate(t *testing.T) {
 
 	}
 }
+
+func TestFriendHandler_GetFriendSuggestions(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetFriendSuggestions struct {
+		input  []int
+		result []model.FriendSuggestion
+		err    error
+	}
+	testCases := []struct {
+		name                     string
+		query                    string
+		expectedResponseBody     string
+		expectedStatus           int
+		mockGetUserIDByEmail     mockGetUserIDByEmail
+		mockGetFriendSuggestions mockGetFriendSuggestions
+	}{
+		{
+			name:                 "Email is required",
+			query:                "",
+			expectedResponseBody: "\"email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Limit is not an integer",
+			query:                "?email=abc@xyz.com&limit=abc",
+			expectedResponseBody: "\"limit\" must be an integer\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Limit is out of range",
+			query:                "?email=abc@xyz.com&limit=0",
+			expectedResponseBody: "\"limit\" must be between 1 and 100\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Email does not exist",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name:                 "Get suggestions failed with error",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "get suggestions failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetFriendSuggestions: mockGetFriendSuggestions{
+				input:  []int{1, 10},
+				result: nil,
+				err:    errors.New("get suggestions failed with error"),
+			},
+		},
+		{
+			name:                 "Get suggestions success",
+			query:                "?email=abc@xyz.com&limit=5",
+			expectedResponseBody: "{\"success\":true,\"suggestions\":[{\"email\":\"xyz@abc.com\",\"mutual_friends\":2}],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserIDByEmail: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetFriendSuggestions: mockGetFriendSuggestions{
+				input: []int{1, 5},
+				result: []model.FriendSuggestion{
+					{Email: "xyz@abc.com", MutualFriends: 2},
+				},
+				err: nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockFriendService := new(mockFriendService)
+			mockUserService := new(mockUserService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserIDByEmail.input).
+				Return(testCase.mockGetUserIDByEmail.result, testCase.mockGetUserIDByEmail.err)
+			if testCase.mockGetFriendSuggestions.input != nil {
+				mockFriendService.On("GetFriendSuggestions", testCase.mockGetFriendSuggestions.input[0], testCase.mockGetFriendSuggestions.input[1]).
+					Return(testCase.mockGetFriendSuggestions.result, testCase.mockGetFriendSuggestions.err)
+			}
+
+			handlers := FriendHandler{
+				IUserService:    mockUserService,
+				IFriendServices: mockFriendService,
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodGet, "/friend/suggestions"+testCase.query, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetFriendSuggestions)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestFriendHandler_GetFriendPath(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetFriendPath struct {
+		input  []int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetFromUserID    mockGetUserIDByEmail
+		mockGetToUserID      mockGetUserIDByEmail
+		mockGetFriendPath    mockGetFriendPath
+	}{
+		{
+			name:                 "From is required",
+			query:                "?to=xyz@abc.com",
+			expectedResponseBody: "\"from\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Max depth is not an integer",
+			query:                "?from=abc@xyz.com&to=xyz@abc.com&maxDepth=abc",
+			expectedResponseBody: "\"maxDepth\" must be an integer\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Max depth is out of range",
+			query:                "?from=abc@xyz.com&to=xyz@abc.com&maxDepth=11",
+			expectedResponseBody: "\"maxDepth\" must be between 1 and 10\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "To email does not exist",
+			query:                "?from=abc@xyz.com&to=xyz@abc.com",
+			expectedResponseBody: "\"to\" email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetFromUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetToUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 0,
+			},
+		},
+		{
+			name:                 "Get path failed with error",
+			query:                "?from=abc@xyz.com&to=xyz@abc.com",
+			expectedResponseBody: "get path failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetFromUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetToUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+			},
+			mockGetFriendPath: mockGetFriendPath{
+				input:  []int{1, 2, 6},
+				result: nil,
+				err:    errors.New("get path failed with error"),
+			},
+		},
+		{
+			name:                 "Path not found",
+			query:                "?from=abc@xyz.com&to=xyz@abc.com&maxDepth=2",
+			expectedResponseBody: "{\"success\":true,\"found\":false,\"path\":[],\"degrees\":0}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetFromUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetToUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+			},
+			mockGetFriendPath: mockGetFriendPath{
+				input:  []int{1, 2, 2},
+				result: []string{},
+			},
+		},
+		{
+			name:                 "Path found",
+			query:                "?from=abc@xyz.com&to=xyz@abc.com",
+			expectedResponseBody: "{\"success\":true,\"found\":true,\"path\":[\"abc@xyz.com\",\"common@xyz.com\",\"xyz@abc.com\"],\"degrees\":2}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetFromUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetToUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+			},
+			mockGetFriendPath: mockGetFriendPath{
+				input:  []int{1, 2, 6},
+				result: []string{"abc@xyz.com", "common@xyz.com", "xyz@abc.com"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockFriendService := new(mockFriendService)
+			mockUserService := new(mockUserService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetFromUserID.input).
+				Return(testCase.mockGetFromUserID.result, testCase.mockGetFromUserID.err)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetToUserID.input).
+				Return(testCase.mockGetToUserID.result, testCase.mockGetToUserID.err)
+			if testCase.mockGetFriendPath.input != nil {
+				mockFriendService.On("GetFriendPath", testCase.mockGetFriendPath.input[0], testCase.mockGetFriendPath.input[1], testCase.mockGetFriendPath.input[2]).
+					Return(testCase.mockGetFriendPath.result, testCase.mockGetFriendPath.err)
+			}
+
+			handlers := FriendHandler{
+				IUserService:    mockUserService,
+				IFriendServices: mockFriendService,
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodGet, "/friend/path"+testCase.query, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetFriendPath)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
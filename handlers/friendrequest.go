@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"S3_FriendManagement_ThinhNguyen/events"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+type FriendRequestHandler struct {
+	IUserService          services.IUserService
+	IFriendRequestService services.IFriendRequestService
+	IEventHub             events.IEventHub
+}
+
+func (_self FriendRequestHandler) CreateFriendRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	friendRequest := model.FriendRequestRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&friendRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate request
+	if err := friendRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate and get UserID by email
+	userIDList, statusCode, err := _self.createFriendRequestValidation(ctx, friendRequest)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Create input services model
+	serviceInput := &model.FriendRequestServiceInput{
+		Requestor: userIDList[0],
+		Target:    userIDList[1],
+	}
+
+	//Call services
+	if err := _self.IFriendRequestService.CreateFriendRequest(ctx, serviceInput); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.SuccessResponse{
+		Success: true,
+	})
+	return
+}
+
+func (_self FriendRequestHandler) AcceptFriendRequest(w http.ResponseWriter, r *http.Request) {
+	_self.handleFriendRequestAction(w, r, true, _self.IFriendRequestService.AcceptFriendRequest, model.EventFriendAdded)
+}
+
+func (_self FriendRequestHandler) RejectFriendRequest(w http.ResponseWriter, r *http.Request) {
+	_self.handleFriendRequestAction(w, r, false, _self.IFriendRequestService.RejectFriendRequest, "")
+}
+
+func (_self FriendRequestHandler) CancelFriendRequest(w http.ResponseWriter, r *http.Request) {
+	_self.handleFriendRequestAction(w, r, false, _self.IFriendRequestService.CancelFriendRequest, "")
+}
+
+func (_self FriendRequestHandler) GetIncomingFriendRequests(w http.ResponseWriter, r *http.Request) {
+	_self.handleFriendRequestList(w, r, _self.IFriendRequestService.GetIncomingFriendRequests)
+}
+
+func (_self FriendRequestHandler) GetOutgoingFriendRequests(w http.ResponseWriter, r *http.Request) {
+	_self.handleFriendRequestList(w, r, _self.IFriendRequestService.GetOutgoingFriendRequests)
+}
+
+func (_self FriendRequestHandler) handleFriendRequestAction(w http.ResponseWriter, r *http.Request, checkBlocked bool, action func(context.Context, *model.FriendRequestServiceInput) error, eventType string) {
+	ctx := r.Context()
+
+	//Decode request body
+	friendRequest := model.FriendRequestRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&friendRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate request
+	if err := friendRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Check pending request and get UserID by email
+	userIDList, statusCode, err := _self.pendingFriendRequestValidation(ctx, friendRequest, checkBlocked)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Create input services model
+	serviceInput := &model.FriendRequestServiceInput{
+		Requestor: userIDList[0],
+		Target:    userIDList[1],
+	}
+
+	//Call services
+	if err := action(ctx, serviceInput); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	//Notify both users when the action changes the friend connection
+	if eventType != "" {
+		publishEvent(_self.IEventHub, friendRequest.Requestor, eventType, model.EventData{Email: friendRequest.Target})
+		publishEvent(_self.IEventHub, friendRequest.Target, eventType, model.EventData{Email: friendRequest.Requestor})
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.SuccessResponse{
+		Success: true,
+	})
+}
+
+func (_self FriendRequestHandler) handleFriendRequestList(w http.ResponseWriter, r *http.Request, list func(context.Context, int) ([]string, error)) {
+	ctx := r.Context()
+
+	//Decode query parameters or request body
+	listRequest := model.FriendRequestListRequest{}
+	if err := decodeRequest(r, &listRequest, []string{"email"}, func(query url.Values) error {
+		listRequest.Email = query.Get("email")
+		return nil
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validation
+	if err := listRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Check existed email and get ID by email
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, listRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if userID == 0 {
+		http.Error(w, "email does not exist", http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	emails, err := list(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.FriendRequestListResponse{
+		Success: true,
+		Emails:  emails,
+		Count:   len(emails),
+	})
+}
+
+func (_self FriendRequestHandler) getFriendRequestUserIDs(ctx context.Context, friendRequest model.FriendRequestRequest) ([]int, int, error) {
+	//Check requestor email
+	requestorUserID, err := _self.IUserService.GetUserIDByEmail(ctx, friendRequest.Requestor)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if requestorUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("the requestor does not exist")
+	}
+
+	//Check target email
+	targetUserID, err := _self.IUserService.GetUserIDByEmail(ctx, friendRequest.Target)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if targetUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("the target does not exist")
+	}
+	return []int{requestorUserID, targetUserID}, 0, nil
+}
+
+func (_self FriendRequestHandler) createFriendRequestValidation(ctx context.Context, friendRequest model.FriendRequestRequest) ([]int, int, error) {
+	userIDList, statusCode, err := _self.getFriendRequestUserIDs(ctx, friendRequest)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	requestorUserID, targetUserID := userIDList[0], userIDList[1]
+
+	//Check friend connection exists
+	existed, err := _self.IFriendRequestService.IsExistedFriend(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if existed {
+		return nil, http.StatusAlreadyReported, errors.New("friend connection existed")
+	}
+
+	//Check pending request from requestor to target
+	pending, err := _self.IFriendRequestService.IsPendingFriendRequest(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if pending {
+		return nil, http.StatusAlreadyReported, model.ErrFriendRequestSent
+	}
+
+	//Check pending request from target to requestor
+	pending, err = _self.IFriendRequestService.IsPendingFriendRequest(ctx, targetUserID, requestorUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if pending {
+		return nil, http.StatusAlreadyReported, errors.New("target has already sent a friend request to requestor")
+	}
+
+	//Check blocking between 2 emails
+	blocked, err := _self.IFriendRequestService.IsBlockedByOtherEmail(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if blocked {
+		return nil, http.StatusPreconditionFailed, errors.New("emails blocked each other")
+	}
+	return userIDList, 0, nil
+}
+
+func (_self FriendRequestHandler) pendingFriendRequestValidation(ctx context.Context, friendRequest model.FriendRequestRequest, checkBlocked bool) ([]int, int, error) {
+	userIDList, statusCode, err := _self.getFriendRequestUserIDs(ctx, friendRequest)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	requestorUserID, targetUserID := userIDList[0], userIDList[1]
+
+	//Check pending request exists
+	pending, err := _self.IFriendRequestService.IsPendingFriendRequest(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !pending {
+		return nil, http.StatusNotFound, errors.New("pending friend request does not exist")
+	}
+
+	if !checkBlocked {
+		return userIDList, 0, nil
+	}
+
+	//Check blocking between 2 emails, a block may have been added after the request was sent
+	blocked, err := _self.IFriendRequestService.IsBlockedByOtherEmail(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if blocked {
+		return nil, http.StatusPreconditionFailed, errors.New("emails blocked each other")
+	}
+	return userIDList, 0, nil
+}
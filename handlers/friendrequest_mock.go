@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockFriendRequestService struct {
+	mock.Mock
+}
+
+func (_self mockFriendRequestService) CreateFriendRequest(ctx context.Context, input *model.FriendRequestServiceInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestService) AcceptFriendRequest(ctx context.Context, input *model.FriendRequestServiceInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestService) RejectFriendRequest(ctx context.Context, input *model.FriendRequestServiceInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestService) CancelFriendRequest(ctx context.Context, input *model.FriendRequestServiceInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestService) IsPendingFriendRequest(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	args := _self.Called(requestorID, targetID)
+	r0 := args.Get(0).(bool)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRequestService) IsExistedFriend(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	args := _self.Called(firstUserID, secondUserID)
+	r0 := args.Get(0).(bool)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRequestService) IsBlockedByOtherEmail(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	args := _self.Called(firstUserID, secondUserID)
+	r0 := args.Get(0).(bool)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRequestService) GetIncomingFriendRequests(ctx context.Context, userID int) ([]string, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRequestService) GetOutgoingFriendRequests(ctx context.Context, userID int) ([]string, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
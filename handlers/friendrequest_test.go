@@ -0,0 +1,814 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFriendRequestHandler_CreateFriendRequest(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockCheckPair struct {
+		input  []int
+		result bool
+		err    error
+	}
+	type mockCreateFriendRequest struct {
+		input *model.FriendRequestServiceInput
+		err   error
+	}
+	testCases := []struct {
+		name                    string
+		requestBody             interface{}
+		expectedResponseBody    string
+		expectedStatus          int
+		mockGetRequestorUserID  mockGetUserIDByEmail
+		mockGetTargetUserID     mockGetUserIDByEmail
+		mockIsExistedFriend     mockCheckPair
+		mockIsPendingSent       mockCheckPair
+		mockIsPendingReceived   mockCheckPair
+		mockIsBlocked           mockCheckPair
+		mockCreateFriendRequest mockCreateFriendRequest
+	}{
+		{
+			name: "Body no data",
+			requestBody: map[string]interface{}{
+				"": "",
+			},
+			expectedResponseBody: "\"requestor\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Two email addresses must be different",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "abc@xyz.com",
+			},
+			expectedResponseBody: "two email addresses must be different\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Requestor userID is not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "the requestor does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name: "Friend connection existed",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "friend connection existed\n",
+			expectedStatus:       http.StatusAlreadyReported,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+		},
+		{
+			name: "Friend request has already been sent",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "friend request has already been sent\n",
+			expectedStatus:       http.StatusAlreadyReported,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingSent: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+		},
+		{
+			name: "Target has already sent a friend request",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "target has already sent a friend request to requestor\n",
+			expectedStatus:       http.StatusAlreadyReported,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingSent: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingReceived: mockCheckPair{
+				input:  []int{11, 10},
+				result: true,
+			},
+		},
+		{
+			name: "Email addresses blocked each other",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "emails blocked each other\n",
+			expectedStatus:       http.StatusPreconditionFailed,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingSent: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingReceived: mockCheckPair{
+				input:  []int{11, 10},
+				result: false,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+		},
+		{
+			name: "Create friend request failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "create friend request failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingSent: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingReceived: mockCheckPair{
+				input:  []int{11, 10},
+				result: false,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockCreateFriendRequest: mockCreateFriendRequest{
+				input: &model.FriendRequestServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: errors.New("create friend request failed with error"),
+			},
+		},
+		{
+			name: "Friend request was sent by another request first",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "friend request has already been sent\n",
+			expectedStatus:       http.StatusAlreadyReported,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingSent: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingReceived: mockCheckPair{
+				input:  []int{11, 10},
+				result: false,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockCreateFriendRequest: mockCreateFriendRequest{
+				input: &model.FriendRequestServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: model.ErrFriendRequestSent,
+			},
+		},
+		{
+			name: "Create friend request success",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsExistedFriend: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingSent: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockIsPendingReceived: mockCheckPair{
+				input:  []int{11, 10},
+				result: false,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockCreateFriendRequest: mockCreateFriendRequest{
+				input: &model.FriendRequestServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockFriendRequestService := new(mockFriendRequestService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetRequestorUserID.input).
+				Return(testCase.mockGetRequestorUserID.result, testCase.mockGetRequestorUserID.err)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetTargetUserID.input).
+				Return(testCase.mockGetTargetUserID.result, testCase.mockGetTargetUserID.err)
+
+			if testCase.mockIsExistedFriend.input != nil {
+				mockFriendRequestService.On("IsExistedFriend", testCase.mockIsExistedFriend.input[0], testCase.mockIsExistedFriend.input[1]).
+					Return(testCase.mockIsExistedFriend.result, testCase.mockIsExistedFriend.err)
+			}
+			if testCase.mockIsPendingSent.input != nil {
+				mockFriendRequestService.On("IsPendingFriendRequest", testCase.mockIsPendingSent.input[0], testCase.mockIsPendingSent.input[1]).
+					Return(testCase.mockIsPendingSent.result, testCase.mockIsPendingSent.err)
+			}
+			if testCase.mockIsPendingReceived.input != nil {
+				mockFriendRequestService.On("IsPendingFriendRequest", testCase.mockIsPendingReceived.input[0], testCase.mockIsPendingReceived.input[1]).
+					Return(testCase.mockIsPendingReceived.result, testCase.mockIsPendingReceived.err)
+			}
+			if testCase.mockIsBlocked.input != nil {
+				mockFriendRequestService.On("IsBlockedByOtherEmail", testCase.mockIsBlocked.input[0], testCase.mockIsBlocked.input[1]).
+					Return(testCase.mockIsBlocked.result, testCase.mockIsBlocked.err)
+			}
+			mockFriendRequestService.On("CreateFriendRequest", testCase.mockCreateFriendRequest.input).
+				Return(testCase.mockCreateFriendRequest.err)
+
+			handlers := FriendRequestHandler{
+				IUserService:          mockUserService,
+				IFriendRequestService: mockFriendRequestService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/friend-request", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.CreateFriendRequest)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestFriendRequestHandler_AcceptFriendRequest(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockCheckPair struct {
+		input  []int
+		result bool
+		err    error
+	}
+	type mockAcceptFriendRequest struct {
+		input *model.FriendRequestServiceInput
+		err   error
+	}
+	testCases := []struct {
+		name                    string
+		requestBody             interface{}
+		expectedResponseBody    string
+		expectedStatus          int
+		mockGetRequestorUserID  mockGetUserIDByEmail
+		mockGetTargetUserID     mockGetUserIDByEmail
+		mockIsPending           mockCheckPair
+		mockIsBlocked           mockCheckPair
+		mockAcceptFriendRequest mockAcceptFriendRequest
+	}{
+		{
+			name: "Target email is invalid",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "abc",
+			},
+			expectedResponseBody: "\"target\" is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Target userID is not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "the target does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 0,
+			},
+		},
+		{
+			name: "Check pending friend request failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "check pending failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+				err:    errors.New("check pending failed with error"),
+			},
+		},
+		{
+			name: "Pending friend request does not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "pending friend request does not exist\n",
+			expectedStatus:       http.StatusNotFound,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+		},
+		{
+			name: "Blocked after the request was sent",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "emails blocked each other\n",
+			expectedStatus:       http.StatusPreconditionFailed,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+		},
+		{
+			name: "Accept friend request failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "accept failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockAcceptFriendRequest: mockAcceptFriendRequest{
+				input: &model.FriendRequestServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: errors.New("accept failed with error"),
+			},
+		},
+		{
+			name: "Accept friend request success",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+			},
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+			mockIsBlocked: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+			mockAcceptFriendRequest: mockAcceptFriendRequest{
+				input: &model.FriendRequestServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockFriendRequestService := new(mockFriendRequestService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetRequestorUserID.input).
+				Return(testCase.mockGetRequestorUserID.result, testCase.mockGetRequestorUserID.err)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetTargetUserID.input).
+				Return(testCase.mockGetTargetUserID.result, testCase.mockGetTargetUserID.err)
+
+			if testCase.mockIsPending.input != nil {
+				mockFriendRequestService.On("IsPendingFriendRequest", testCase.mockIsPending.input[0], testCase.mockIsPending.input[1]).
+					Return(testCase.mockIsPending.result, testCase.mockIsPending.err)
+			}
+			if testCase.mockIsBlocked.input != nil {
+				mockFriendRequestService.On("IsBlockedByOtherEmail", testCase.mockIsBlocked.input[0], testCase.mockIsBlocked.input[1]).
+					Return(testCase.mockIsBlocked.result, testCase.mockIsBlocked.err)
+			}
+			mockFriendRequestService.On("AcceptFriendRequest", testCase.mockAcceptFriendRequest.input).
+				Return(testCase.mockAcceptFriendRequest.err)
+
+			handlers := FriendRequestHandler{
+				IUserService:          mockUserService,
+				IFriendRequestService: mockFriendRequestService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/friend-request/accept", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.AcceptFriendRequest)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestFriendRequestHandler_CancelFriendRequest(t *testing.T) {
+	type mockCheckPair struct {
+		input  []int
+		result bool
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		requestBody          interface{}
+		expectedResponseBody string
+		expectedStatus       int
+		mockIsPending        mockCheckPair
+		mockCancelErr        error
+	}{
+		{
+			name: "Pending friend request does not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "pending friend request does not exist\n",
+			expectedStatus:       http.StatusNotFound,
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: false,
+			},
+		},
+		{
+			name: "Cancel friend request failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "cancel failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+			mockCancelErr: errors.New("cancel failed with error"),
+		},
+		{
+			name: "Cancel friend request success",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockIsPending: mockCheckPair{
+				input:  []int{10, 11},
+				result: true,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockFriendRequestService := new(mockFriendRequestService)
+
+			mockUserService.On("GetUserIDByEmail", "abc@xyz.com").Return(10, nil)
+			mockUserService.On("GetUserIDByEmail", "xyz@abc.com").Return(11, nil)
+			mockFriendRequestService.On("IsPendingFriendRequest", testCase.mockIsPending.input[0], testCase.mockIsPending.input[1]).
+				Return(testCase.mockIsPending.result, testCase.mockIsPending.err)
+			mockFriendRequestService.On("CancelFriendRequest", &model.FriendRequestServiceInput{Requestor: 10, Target: 11}).
+				Return(testCase.mockCancelErr)
+
+			handlers := FriendRequestHandler{
+				IUserService:          mockUserService,
+				IFriendRequestService: mockFriendRequestService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/friend-request/cancel", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.CancelFriendRequest)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestFriendRequestHandler_GetIncomingFriendRequests(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetIncoming struct {
+		input  int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		requestBody          interface{}
+		query                string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserID        mockGetUserIDByEmail
+		mockGetIncoming      mockGetIncoming
+	}{
+		{
+			name: "Email is required",
+			requestBody: map[string]interface{}{
+				"email": "",
+			},
+			expectedResponseBody: "\"email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Email does not exist",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+			},
+		},
+		{
+			name: "Get incoming friend requests failed with error",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "get incoming failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetIncoming: mockGetIncoming{
+				input:  10,
+				result: nil,
+				err:    errors.New("get incoming failed with error"),
+			},
+		},
+		{
+			name: "Get incoming friend requests success",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "{\"success\":true,\"emails\":[\"xyz@abc.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetIncoming: mockGetIncoming{
+				input:  10,
+				result: []string{"xyz@abc.com"},
+				err:    nil,
+			},
+		},
+		{
+			name:                 "Get incoming friend requests success with query parameters",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "{\"success\":true,\"emails\":[\"xyz@abc.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+			},
+			mockGetIncoming: mockGetIncoming{
+				input:  10,
+				result: []string{"xyz@abc.com"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockFriendRequestService := new(mockFriendRequestService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+			mockFriendRequestService.On("GetIncomingFriendRequests", testCase.mockGetIncoming.input).
+				Return(testCase.mockGetIncoming.result, testCase.mockGetIncoming.err)
+
+			handlers := FriendRequestHandler{
+				IUserService:          mockUserService,
+				IFriendRequestService: mockFriendRequestService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/friend-request/incoming"+testCase.query, bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetIncomingFriendRequests)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
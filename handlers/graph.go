@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"S3_FriendManagement_ThinhNguyen/graphio"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+type GraphHandler struct {
+	IGraphService services.IGraphService
+}
+
+// ExportGraph sends every user and relationship as JSON, as a zip of CSV files or as GraphML
+func (_self GraphHandler) ExportGraph(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameter
+	exportRequest := model.GraphExportRequest{
+		Format: r.URL.Query().Get("format"),
+	}
+
+	//Validation
+	if err := exportRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Response, streamed while the rows are read
+	w.Header().Set("Content-Type", graphio.ContentTypes[exportRequest.Format])
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+graphio.FileNames[exportRequest.Format]+"\"")
+	out := &trackedResponse{ResponseWriter: w}
+	if err := _self.IGraphService.ExportGraph(ctx, graphio.NewWriter(out, exportRequest.Format)); err != nil {
+		if out.written {
+			//The status is already sent, only cutting the connection tells the client the file is incomplete
+			panic(http.ErrAbortHandler)
+		}
+		w.Header().Del("Content-Disposition")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// trackedResponse remembers whether the body has been started
+type trackedResponse struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (_self *trackedResponse) Write(p []byte) (int, error) {
+	_self.written = true
+	return _self.ResponseWriter.Write(p)
+}
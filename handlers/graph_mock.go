@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/graphio"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockGraphService struct {
+	mock.Mock
+}
+
+func (_self mockGraphService) ExportGraph(ctx context.Context, writer model.GraphWriter) error {
+	args := _self.Called()
+	if graph := args.Get(0).(*model.Graph); graph != nil {
+		if err := graphio.WriteGraph(writer, graph); err != nil {
+			return err
+		}
+	}
+	var r error
+	if args.Get(1) != nil {
+		r = args.Get(1).(error)
+	}
+	return r
+}
+
+func (_self mockGraphService) ImportGraph(ctx context.Context, graph *model.Graph) error {
+	args := _self.Called(graph)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
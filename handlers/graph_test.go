@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphHandler_ExportGraph(t *testing.T) {
+	type mockExportGraph struct {
+		result *model.Graph
+		err    error
+	}
+	createdAt := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	graph := &model.Graph{
+		ExportedAt: createdAt,
+		Users: []model.GraphUser{
+			{ID: 1, Email: "abc@xyz.com", DisplayName: "Andy", Status: model.UserStatusActive, CreatedAt: createdAt},
+			{ID: 2, Email: "xyz@abc.com", Status: model.UserStatusBusy, CreatedAt: createdAt},
+		},
+		Friends:       []model.GraphEdge{{Source: 1, Target: 2, CreatedAt: &createdAt}},
+		Subscriptions: []model.GraphEdge{{Source: 2, Target: 1}},
+		Blocks:        []model.GraphEdge{},
+	}
+	testCases := []struct {
+		name                       string
+		query                      string
+		expectedResponseBody       string
+		expectedResponseStatus     int
+		expectedContentType        string
+		expectedContentDisposition string
+		mockExportGraph            mockExportGraph
+	}{
+		{
+			name:                   "Format is not valid",
+			query:                  "?format=xml",
+			expectedResponseBody:   "\"format\" must be \"json\", \"csv\" or \"graphml\"\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Export failed with error",
+			expectedResponseBody:   "export failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockExportGraph: mockExportGraph{
+				err: errors.New("export failed"),
+			},
+		},
+		{
+			name: "Export as JSON success",
+			expectedResponseBody: "{\"exported_at\":\"2021-02-03T04:05:06Z\",\"users\":[" +
+				"{\"id\":1,\"email\":\"abc@xyz.com\",\"display_name\":\"Andy\",\"avatar_url\":\"\",\"bio\":\"\",\"status\":\"active\",\"created_at\":\"2021-02-03T04:05:06Z\"}," +
+				"{\"id\":2,\"email\":\"xyz@abc.com\",\"display_name\":\"\",\"avatar_url\":\"\",\"bio\":\"\",\"status\":\"busy\",\"created_at\":\"2021-02-03T04:05:06Z\"}]," +
+				"\"friends\":[{\"source\":1,\"target\":2,\"created_at\":\"2021-02-03T04:05:06Z\"}]," +
+				"\"subscriptions\":[{\"source\":2,\"target\":1}],\"blocks\":[]}\n",
+			expectedResponseStatus:     http.StatusOK,
+			expectedContentType:        "application/json",
+			expectedContentDisposition: "attachment; filename=\"graph.json\"",
+			mockExportGraph: mockExportGraph{
+				result: graph,
+			},
+		},
+		{
+			name:  "Export as GraphML success",
+			query: "?format=graphml",
+			expectedResponseBody: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+				"<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n" +
+				"  <key id=\"email\" for=\"node\" attr.name=\"email\" attr.type=\"string\"></key>\n" +
+				"  <key id=\"display_name\" for=\"node\" attr.name=\"display_name\" attr.type=\"string\"></key>\n" +
+				"  <key id=\"avatar_url\" for=\"node\" attr.name=\"avatar_url\" attr.type=\"string\"></key>\n" +
+				"  <key id=\"bio\" for=\"node\" attr.name=\"bio\" attr.type=\"string\"></key>\n" +
+				"  <key id=\"status\" for=\"node\" attr.name=\"status\" attr.type=\"string\"></key>\n" +
+				"  <key id=\"created_at\" for=\"all\" attr.name=\"created_at\" attr.type=\"string\"></key>\n" +
+				"  <key id=\"relation\" for=\"edge\" attr.name=\"relation\" attr.type=\"string\"></key>\n" +
+				"  <graph id=\"friends\" edgedefault=\"directed\">\n" +
+				"    <node id=\"n1\">\n" +
+				"      <data key=\"email\">abc@xyz.com</data>\n" +
+				"      <data key=\"display_name\">Andy</data>\n" +
+				"      <data key=\"avatar_url\"></data>\n" +
+				"      <data key=\"bio\"></data>\n" +
+				"      <data key=\"status\">active</data>\n" +
+				"      <data key=\"created_at\">2021-02-03T04:05:06Z</data>\n" +
+				"    </node>\n" +
+				"    <node id=\"n2\">\n" +
+				"      <data key=\"email\">xyz@abc.com</data>\n" +
+				"      <data key=\"display_name\"></data>\n" +
+				"      <data key=\"avatar_url\"></data>\n" +
+				"      <data key=\"bio\"></data>\n" +
+				"      <data key=\"status\">busy</data>\n" +
+				"      <data key=\"created_at\">2021-02-03T04:05:06Z</data>\n" +
+				"    </node>\n" +
+				"    <edge id=\"e1\" source=\"n1\" target=\"n2\" directed=\"false\">\n" +
+				"      <data key=\"relation\">friend</data>\n" +
+				"      <data key=\"created_at\">2021-02-03T04:05:06Z</data>\n" +
+				"    </edge>\n" +
+				"    <edge id=\"e2\" source=\"n2\" target=\"n1\">\n" +
+				"      <data key=\"relation\">subscription</data>\n" +
+				"    </edge>\n" +
+				"  </graph>\n" +
+				"</graphml>\n",
+			expectedResponseStatus:     http.StatusOK,
+			expectedContentType:        "application/graphml+xml",
+			expectedContentDisposition: "attachment; filename=\"graph.graphml\"",
+			mockExportGraph: mockExportGraph{
+				result: graph,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockGraphService)
+			mockService.On("ExportGraph").
+				Return(testCase.mockExportGraph.result, testCase.mockExportGraph.err)
+
+			handlers := GraphHandler{
+				IGraphService: mockService,
+			}
+			router := chi.NewRouter()
+			router.Get("/graph/export", handlers.ExportGraph)
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/graph/export"+testCase.query, nil)
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+			if testCase.expectedContentType != "" {
+				require.Equal(t, testCase.expectedContentType, responseRecorder.Header().Get("Content-Type"))
+				require.Equal(t, testCase.expectedContentDisposition, responseRecorder.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
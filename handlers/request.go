@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// decodeRequest fills request from the query string when it carries one of fields, otherwise from the JSON body,
+// so unrelated parameters such as a cache buster do not hide the body.
+// Both ways end up in the same model so its Validate() rules apply to either.
+func decodeRequest(r *http.Request, request interface{}, fields []string, fromQuery func(url.Values) error) error {
+	query := r.URL.Query()
+	for _, field := range fields {
+		if _, ok := query[field]; ok {
+			return fromQuery(query)
+		}
+	}
+	return json.NewDecoder(r.Body).Decode(request)
+}
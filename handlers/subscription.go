@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 
+	"S3_FriendManagement_ThinhNguyen/events"
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/services"
 )
@@ -12,9 +14,12 @@ import (
 type SubscriptionHandler struct {
 	IUserService         services.IUserService
 	ISubscriptionService services.ISubscriptionService
+	IEventHub            events.IEventHub
 }
 
 func (_self SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	//Decode request body
 	subscriptionRequest := model.CreateSubscriptionRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&subscriptionRequest); err != nil {
@@ -29,7 +34,7 @@ func (_self SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *ht
 	}
 
 	//Validate and get UserID by email
-	userIDList, statusCode, err := _self.CreateSubscribeValidation(subscriptionRequest)
+	userIDList, statusCode, err := _self.CreateSubscribeValidation(ctx, subscriptionRequest)
 	if err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
@@ -40,7 +45,115 @@ func (_self SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *ht
 		Target:    userIDList[1],
 	}
 	//Call services
-	if err := _self.ISubscriptionService.CreateSubscription(modelServiceInput); err != nil {
+	if err := _self.ISubscriptionService.CreateSubscription(ctx, modelServiceInput); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	//Notify the target of the new subscriber
+	publishEvent(_self.IEventHub, subscriptionRequest.Target, model.EventSubscriberAdded, model.EventData{Email: subscriptionRequest.Requestor})
+
+	// Response
+	json.NewEncoder(w).Encode(model.SuccessResponse{
+		Success: true,
+	})
+	return
+}
+
+// CreateSubscriptions creates many subscriptions at once.
+// Each subscription gets the status and error its own POST /subscription would have answered.
+func (_self SubscriptionHandler) CreateSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	batchRequest := model.SubscriptionBatchRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&batchRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate request
+	if err := batchRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate each subscription, the invalid ones are only reported
+	results := newBatchResults(len(batchRequest.Subscriptions))
+	pairs := make([]batchPair, 0, len(batchRequest.Subscriptions))
+	for i, subscriptionRequest := range batchRequest.Subscriptions {
+		if err := subscriptionRequest.Validate(); err != nil {
+			results.fail(i, http.StatusBadRequest, err)
+			continue
+		}
+		pairs = append(pairs, batchPair{index: i, emails: [2]string{subscriptionRequest.Requestor, subscriptionRequest.Target}})
+	}
+
+	//Get the UserIDs of every email at once
+	pairs, err := resolveBatchPairs(ctx, _self.IUserService, pairs, results,
+		errors.New("requestor email does not exist"), errors.New("target email does not exist"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(pairs) > 0 {
+		//Create input services models
+		modelServiceInputs := make([]model.SubscriptionServiceInput, len(pairs))
+		for i, pair := range pairs {
+			modelServiceInputs[i] = model.SubscriptionServiceInput{
+				Requestor: pair.firstID,
+				Target:    pair.secondID,
+			}
+		}
+
+		//Call services
+		createErrs, err := _self.ISubscriptionService.CreateSubscriptions(ctx, modelServiceInputs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		//Notify the targets of their new subscribers
+		for _, pair := range applyBatchErrors(pairs, createErrs, results) {
+			publishEvent(_self.IEventHub, pair.emails[1], model.EventSubscriberAdded, model.EventData{Email: pair.emails[0]})
+		}
+	}
+
+	// Response
+	json.NewEncoder(w).Encode(results.response())
+	return
+}
+
+func (_self SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	subscriptionRequest := model.CreateSubscriptionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&subscriptionRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate request
+	if err := subscriptionRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validate and get UserID by email
+	userIDList, statusCode, err := _self.DeleteSubscriptionValidation(ctx, subscriptionRequest)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	//Create input services model
+	modelServiceInput := &model.SubscriptionServiceInput{
+		Requestor: userIDList[0],
+		Target:    userIDList[1],
+	}
+	//Call services
+	if err := _self.ISubscriptionService.DeleteSubscription(ctx, modelServiceInput); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -52,9 +165,57 @@ func (_self SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *ht
 	return
 }
 
-func (_self SubscriptionHandler) CreateSubscribeValidation(subscriptionRequest model.CreateSubscriptionRequest) ([]int, int, error) {
+func (_self SubscriptionHandler) GetSubscriberList(w http.ResponseWriter, r *http.Request) {
+	_self.handleSubscriptionList(w, r, _self.ISubscriptionService.GetSubscriberList)
+}
+
+func (_self SubscriptionHandler) GetFollowingList(w http.ResponseWriter, r *http.Request) {
+	_self.handleSubscriptionList(w, r, _self.ISubscriptionService.GetFollowingList)
+}
+
+func (_self SubscriptionHandler) handleSubscriptionList(w http.ResponseWriter, r *http.Request, list func(context.Context, int) ([]string, error)) {
+	ctx := r.Context()
+
+	//Read query parameter
+	listRequest := model.SubscriptionListRequest{
+		Email: r.URL.Query().Get("email"),
+	}
+
+	//Validate request
+	if err := listRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Get UserID by email
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, listRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if userID == 0 {
+		http.Error(w, "email does not exist", http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	emails, err := list(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Response
+	json.NewEncoder(w).Encode(model.SubscriptionListResponse{
+		Success: true,
+		Emails:  emails,
+		Count:   len(emails),
+	})
+}
+
+func (_self SubscriptionHandler) CreateSubscribeValidation(ctx context.Context, subscriptionRequest model.CreateSubscriptionRequest) ([]int, int, error) {
 	//Check requestor email
-	requestorUSerID, err := _self.IUserService.GetUserIDByEmail(subscriptionRequest.Requestor)
+	requestorUSerID, err := _self.IUserService.GetUserIDByEmail(ctx, subscriptionRequest.Requestor)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -63,7 +224,7 @@ func (_self SubscriptionHandler) CreateSubscribeValidation(subscriptionRequest m
 	}
 
 	//Check target email
-	targetUserID, err := _self.IUserService.GetUserIDByEmail(subscriptionRequest.Target)
+	targetUserID, err := _self.IUserService.GetUserIDByEmail(ctx, subscriptionRequest.Target)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -72,7 +233,7 @@ func (_self SubscriptionHandler) CreateSubscribeValidation(subscriptionRequest m
 	}
 
 	//Check subscription existed
-	exist, err := _self.ISubscriptionService.IsExistedSubscription(requestorUSerID, targetUserID)
+	exist, err := _self.ISubscriptionService.IsExistedSubscription(ctx, requestorUSerID, targetUserID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -81,7 +242,7 @@ func (_self SubscriptionHandler) CreateSubscribeValidation(subscriptionRequest m
 	}
 
 	//Check blocked
-	blocked, err := _self.ISubscriptionService.IsBlockedByOtherEmail(requestorUSerID, targetUserID)
+	blocked, err := _self.ISubscriptionService.IsBlockedByOtherEmail(ctx, requestorUSerID, targetUserID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
@@ -90,3 +251,33 @@ func (_self SubscriptionHandler) CreateSubscribeValidation(subscriptionRequest m
 	}
 	return []int{requestorUSerID, targetUserID}, 0, nil
 }
+
+func (_self SubscriptionHandler) DeleteSubscriptionValidation(ctx context.Context, subscriptionRequest model.CreateSubscriptionRequest) ([]int, int, error) {
+	//Check requestor email
+	requestorUserID, err := _self.IUserService.GetUserIDByEmail(ctx, subscriptionRequest.Requestor)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if requestorUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("requestor email does not exist")
+	}
+
+	//Check target email
+	targetUserID, err := _self.IUserService.GetUserIDByEmail(ctx, subscriptionRequest.Target)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if targetUserID == 0 {
+		return nil, http.StatusBadRequest, errors.New("target email does not exist")
+	}
+
+	//Check subscription existed
+	exist, err := _self.ISubscriptionService.IsExistedSubscription(ctx, requestorUserID, targetUserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !exist {
+		return nil, http.StatusNotFound, errors.New("requestor has not subscribed to target")
+	}
+	return []int{requestorUserID, targetUserID}, 0, nil
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockSubscriptionService struct {
 	mock.Mock
 }
 
-func (_self mockSubscriptionService) CreateSubscription(subscriptionServiceInput *model.SubscriptionServiceInput) error {
+func (_self mockSubscriptionService) CreateSubscription(ctx context.Context, subscriptionServiceInput *model.SubscriptionServiceInput) error {
 	args := _self.Called(subscriptionServiceInput)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,17 @@ func (_self mockSubscriptionService) CreateSubscription(subscriptionServiceInput
 	return r
 }
 
-func (_self mockSubscriptionService) IsExistedSubscription(requestorid int, targetid int) (bool, error) {
+func (_self mockSubscriptionService) CreateSubscriptions(ctx context.Context, inputs []model.SubscriptionServiceInput) ([]error, error) {
+	args := _self.Called(inputs)
+	r0 := args.Get(0).([]error)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockSubscriptionService) IsExistedSubscription(ctx context.Context, requestorid int, targetid int) (bool, error) {
 	args := _self.Called(requestorid, targetid)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -28,7 +40,7 @@ func (_self mockSubscriptionService) IsExistedSubscription(requestorid int, targ
 	return r0, r1
 }
 
-func (_self mockSubscriptionService) IsBlockedByOtherEmail(requestorid int, targetid int) (bool, error) {
+func (_self mockSubscriptionService) IsBlockedByOtherEmail(ctx context.Context, requestorid int, targetid int) (bool, error) {
 	args := _self.Called(requestorid, targetid)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -37,3 +49,32 @@ func (_self mockSubscriptionService) IsBlockedByOtherEmail(requestorid int, targ
 	}
 	return r0, r1
 }
+
+func (_self mockSubscriptionService) DeleteSubscription(ctx context.Context, subscriptionServiceInput *model.SubscriptionServiceInput) error {
+	args := _self.Called(subscriptionServiceInput)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockSubscriptionService) GetSubscriberList(ctx context.Context, userID int) ([]string, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockSubscriptionService) GetFollowingList(ctx context.Context, userID int) ([]string, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
@@ -294,6 +294,42 @@ func TestSubscriptionHandler_CreateSubscription(t *testing.T) {
 				err: errors.New("failed with error"),
 			},
 		},
+		{
+			name: "Subscription created by a concurrent request",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "those email address have already subscribed the each other\n",
+			expectedStatus:       http.StatusAlreadyReported,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 10,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 11,
+				err:    nil,
+			},
+			mockIsExistedSubscription: mockIsExistedSubscription{
+				input:  []int{10, 11},
+				result: false,
+				err:    nil,
+			},
+			mockIsBlocked: mockIsBlocked{
+				input:  []int{10, 11},
+				result: false,
+				err:    nil,
+			},
+			mockCreateSubscription: mockCreateSubscription{
+				input: &model.SubscriptionServiceInput{
+					Requestor: 10,
+					Target:    11,
+				},
+				err: model.ErrSubscriptionExisted,
+			},
+		},
 		{
 			name: "Create success",
 			requestBody: map[string]interface{}{
@@ -377,3 +413,464 @@ func TestSubscriptionHandler_CreateSubscription(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionHandler_CreateSubscriptions(t *testing.T) {
+	type mockGetUserIDMapByEmails struct {
+		input  []string
+		result map[string]int
+		err    error
+	}
+	type mockCreateSubscriptions struct {
+		input  []model.SubscriptionServiceInput
+		result []error
+		err    error
+	}
+	testCases := []struct {
+		name                     string
+		requestBody              interface{}
+		expectedResponseBody     string
+		expectedStatus           int
+		mockGetUserIDMapByEmails mockGetUserIDMapByEmails
+		mockCreateSubscriptions  mockCreateSubscriptions
+	}{
+		{
+			name: "Body no data",
+			requestBody: map[string]interface{}{
+				"": "",
+			},
+			expectedResponseBody: "\"subscriptions\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Create subscriptions failed with error",
+			requestBody: map[string]interface{}{
+				"subscriptions": []map[string]string{
+					{"requestor": "abc@xyz.com", "target": "xyz@abc.com"},
+				},
+			},
+			expectedResponseBody: "create subscriptions failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input:  []string{"abc@xyz.com", "xyz@abc.com"},
+				result: map[string]int{"abc@xyz.com": 1, "xyz@abc.com": 2},
+			},
+			mockCreateSubscriptions: mockCreateSubscriptions{
+				input:  []model.SubscriptionServiceInput{{Requestor: 1, Target: 2}},
+				result: nil,
+				err:    errors.New("create subscriptions failed with error"),
+			},
+		},
+		{
+			name: "Each subscription gets its own result",
+			requestBody: map[string]interface{}{
+				"subscriptions": []map[string]string{
+					{"requestor": "abc@xyz.com", "target": "xyz@abc.com"},
+					{"requestor": "abc@xyz.com"},
+					{"requestor": "new@abc.com", "target": "xyz@abc.com"},
+					{"requestor": "xyz@abc.com", "target": "abc@xyz.com"},
+				},
+			},
+			expectedResponseBody: "{\"success\":true,\"results\":[" +
+				"{\"index\":0,\"status\":200}," +
+				"{\"index\":1,\"status\":400,\"error\":\"\\\"target\\\" is required\"}," +
+				"{\"index\":2,\"status\":400,\"error\":\"requestor email does not exist\"}," +
+				"{\"index\":3,\"status\":208,\"error\":\"those email address have already subscribed the each other\"}" +
+				"],\"created\":1}\n",
+			expectedStatus: http.StatusOK,
+			mockGetUserIDMapByEmails: mockGetUserIDMapByEmails{
+				input: []string{
+					"abc@xyz.com", "xyz@abc.com",
+					"new@abc.com", "xyz@abc.com",
+					"xyz@abc.com", "abc@xyz.com",
+				},
+				result: map[string]int{"abc@xyz.com": 1, "xyz@abc.com": 2},
+			},
+			mockCreateSubscriptions: mockCreateSubscriptions{
+				input: []model.SubscriptionServiceInput{
+					{Requestor: 1, Target: 2},
+					{Requestor: 2, Target: 1},
+				},
+				result: []error{nil, model.ErrSubscriptionExisted},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			mockSubscriptionService := new(mockSubscriptionService)
+
+			if testCase.mockGetUserIDMapByEmails.input != nil {
+				mockUserService.On("GetUserIDMapByEmails", testCase.mockGetUserIDMapByEmails.input).
+					Return(testCase.mockGetUserIDMapByEmails.result, testCase.mockGetUserIDMapByEmails.err)
+			}
+			if testCase.mockCreateSubscriptions.input != nil {
+				mockSubscriptionService.On("CreateSubscriptions", testCase.mockCreateSubscriptions.input).
+					Return(testCase.mockCreateSubscriptions.result, testCase.mockCreateSubscriptions.err)
+			}
+
+			handlers := SubscriptionHandler{
+				IUserService:         mockUserService,
+				ISubscriptionService: mockSubscriptionService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/subscription/batch", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.CreateSubscriptions)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestSubscriptionHandler_DeleteSubscription(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockIsExistedSubscription struct {
+		input  []int
+		result bool
+		err    error
+	}
+	type mockDeleteSubscription struct {
+		input *model.SubscriptionServiceInput
+		err   error
+	}
+	testCases := []struct {
+		name                      string
+		requestBody               interface{}
+		expectedResponseBody      string
+		expectedStatus            int
+		mockGetRequestorUserID    mockGetUserIDByEmail
+		mockGetTargetUserID       mockGetUserIDByEmail
+		mockIsExistedSubscription mockIsExistedSubscription
+		mockDeleteSubscription    mockDeleteSubscription
+	}{
+		{
+			name: "No requestor email",
+			requestBody: map[string]interface{}{
+				"target": "abc@xyz.com",
+			},
+			expectedResponseBody: "\"requestor\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Target email does not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "target email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name: "Check existed subscription failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "check existed failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+				err:    nil,
+			},
+			mockIsExistedSubscription: mockIsExistedSubscription{
+				input:  []int{1, 2},
+				result: false,
+				err:    errors.New("check existed failed with error"),
+			},
+		},
+		{
+			name: "Subscription does not exist",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "requestor has not subscribed to target\n",
+			expectedStatus:       http.StatusNotFound,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+				err:    nil,
+			},
+			mockIsExistedSubscription: mockIsExistedSubscription{
+				input:  []int{1, 2},
+				result: false,
+				err:    nil,
+			},
+		},
+		{
+			name: "Delete subscription failed with error",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "delete failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+				err:    nil,
+			},
+			mockIsExistedSubscription: mockIsExistedSubscription{
+				input:  []int{1, 2},
+				result: true,
+				err:    nil,
+			},
+			mockDeleteSubscription: mockDeleteSubscription{
+				input: &model.SubscriptionServiceInput{
+					Requestor: 1,
+					Target:    2,
+				},
+				err: errors.New("delete failed with error"),
+			},
+		},
+		{
+			name: "Delete subscription success",
+			requestBody: map[string]interface{}{
+				"requestor": "abc@xyz.com",
+				"target":    "xyz@abc.com",
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetRequestorUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetTargetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+				err:    nil,
+			},
+			mockIsExistedSubscription: mockIsExistedSubscription{
+				input:  []int{1, 2},
+				result: true,
+				err:    nil,
+			},
+			mockDeleteSubscription: mockDeleteSubscription{
+				input: &model.SubscriptionServiceInput{
+					Requestor: 1,
+					Target:    2,
+				},
+				err: nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockSubscriptionService := new(mockSubscriptionService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetRequestorUserID.input).
+				Return(testCase.mockGetRequestorUserID.result, testCase.mockGetRequestorUserID.err)
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetTargetUserID.input).
+				Return(testCase.mockGetTargetUserID.result, testCase.mockGetTargetUserID.err)
+			if testCase.mockIsExistedSubscription.input != nil {
+				mockSubscriptionService.On("IsExistedSubscription", testCase.mockIsExistedSubscription.input[0], testCase.mockIsExistedSubscription.input[1]).
+					Return(testCase.mockIsExistedSubscription.result, testCase.mockIsExistedSubscription.err)
+			}
+			mockSubscriptionService.On("DeleteSubscription", testCase.mockDeleteSubscription.input).
+				Return(testCase.mockDeleteSubscription.err)
+
+			handlers := SubscriptionHandler{
+				IUserService:         mockUserService,
+				ISubscriptionService: mockSubscriptionService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodDelete, "/subscription", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.DeleteSubscription)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestSubscriptionHandler_GetSubscriptionLists(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetList struct {
+		method string
+		input  int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		path                 string
+		query                string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserID        mockGetUserIDByEmail
+		mockGetList          mockGetList
+	}{
+		{
+			name:                 "Email is required",
+			path:                 "/subscription/subscribers",
+			query:                "",
+			expectedResponseBody: "\"email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Email does not exist",
+			path:                 "/subscription/following",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+				err:    nil,
+			},
+		},
+		{
+			name:                 "Get subscriber list failed with error",
+			path:                 "/subscription/subscribers",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "get subscribers failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetList: mockGetList{
+				method: "GetSubscriberList",
+				input:  1,
+				result: nil,
+				err:    errors.New("get subscribers failed with error"),
+			},
+		},
+		{
+			name:                 "Get subscriber list success",
+			path:                 "/subscription/subscribers",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "{\"success\":true,\"emails\":[\"xyz@abc.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+				err:    nil,
+			},
+			mockGetList: mockGetList{
+				method: "GetSubscriberList",
+				input:  1,
+				result: []string{"xyz@abc.com"},
+				err:    nil,
+			},
+		},
+		{
+			name:                 "Get following list success",
+			path:                 "/subscription/following",
+			query:                "?email=xyz@abc.com",
+			expectedResponseBody: "{\"success\":true,\"emails\":[\"abc@xyz.com\"],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "xyz@abc.com",
+				result: 2,
+				err:    nil,
+			},
+			mockGetList: mockGetList{
+				method: "GetFollowingList",
+				input:  2,
+				result: []string{"abc@xyz.com"},
+				err:    nil,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockSubscriptionService := new(mockSubscriptionService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+			if testCase.mockGetList.method != "" {
+				mockSubscriptionService.On(testCase.mockGetList.method, testCase.mockGetList.input).
+					Return(testCase.mockGetList.result, testCase.mockGetList.err)
+			}
+
+			handlers := SubscriptionHandler{
+				IUserService:         mockUserService,
+				ISubscriptionService: mockSubscriptionService,
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodGet, testCase.path+testCase.query, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetSubscriberList)
+			if testCase.path == "/subscription/following" {
+				handler = handlers.GetFollowingList
+			}
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"S3_FriendManagement_ThinhNguyen/events"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+type UpdateHandler struct {
+	IUserService    services.IUserService
+	IFriendServices services.IFriendService
+	IUpdateService  services.IUpdateService
+	IEventHub       events.IEventHub
+}
+
+func (_self UpdateHandler) CreateUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	updateRequest := model.UpdateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if err := updateRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Check existed sender and get userID
+	senderID, err := _self.IUserService.GetUserIDByEmail(ctx, updateRequest.Sender)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if senderID == 0 {
+		http.Error(w, "the sender does not exist", http.StatusBadRequest)
+		return
+	}
+
+	//Get recipients
+	recipients, err := _self.IFriendServices.GetEmailsReceiveUpdate(ctx, senderID, updateRequest.Text)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Call services
+	updateID, err := _self.IUpdateService.CreateUpdate(ctx, &model.UpdateServiceInput{
+		SenderID:   senderID,
+		Sender:     updateRequest.Sender,
+		Text:       updateRequest.Text,
+		Recipients: recipients,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Notify the recipients
+	for _, recipient := range recipients {
+		publishEvent(_self.IEventHub, recipient, model.EventUpdateReceived, model.EventData{
+			Email:    updateRequest.Sender,
+			UpdateID: updateID,
+			Text:     updateRequest.Text,
+		})
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.CreateUpdateResponse{
+		Success:    true,
+		UpdateID:   updateID,
+		Recipients: recipients,
+	})
+}
+
+func (_self UpdateHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameters
+	feedRequest := model.FeedRequest{
+		Email:  r.URL.Query().Get("email"),
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  model.DefaultFeedLimit,
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "\"limit\" must be an integer", http.StatusBadRequest)
+			return
+		}
+		feedRequest.Limit = value
+	}
+
+	// Validate request
+	if err := feedRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Check existed email and get ID by email
+	userID, statusCode, err := _self.getFeedUserID(ctx, feedRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Model services input
+	feedInput := &model.FeedServiceInput{
+		UserID: userID,
+		Limit:  feedRequest.Limit,
+		Cursor: feedRequest.DecodedCursor,
+	}
+
+	//Call services
+	page, err := _self.IUpdateService.GetFeed(ctx, feedInput)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.FeedResponse{
+		Success:    true,
+		Updates:    page.Updates,
+		NextCursor: page.NextCursor,
+	})
+}
+
+func (_self UpdateHandler) MarkFeedAsRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	readRequest := model.FeedReadRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&readRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if err := readRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Check existed email and get ID by email
+	userID, statusCode, err := _self.getFeedUserID(ctx, readRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Call services
+	if err := _self.IUpdateService.MarkUpdatesAsRead(ctx, userID, readRequest.UpdateIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.SuccessResponse{
+		Success: true,
+	})
+}
+
+func (_self UpdateHandler) getFeedUserID(ctx context.Context, email string) (int, int, error) {
+	userID, err := _self.IUserService.GetUserIDByEmail(ctx, email)
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+	if userID == 0 {
+		return 0, http.StatusBadRequest, errors.New("email does not exist")
+	}
+	return userID, 0, nil
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUpdateService struct {
+	mock.Mock
+}
+
+func (_self mockUpdateService) CreateUpdate(ctx context.Context, input *model.UpdateServiceInput) (int, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUpdateService) GetFeed(ctx context.Context, input *model.FeedServiceInput) (*model.FeedPage, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(*model.FeedPage)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUpdateService) MarkUpdatesAsRead(ctx context.Context, userID int, updateIDs []int) error {
+	args := _self.Called(userID, updateIDs)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler_CreateUpdate(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetEmailsReceiveUpdate struct {
+		sender int
+		text   string
+		result []string
+		err    error
+	}
+	type mockCreateUpdate struct {
+		input  *model.UpdateServiceInput
+		result int
+		err    error
+	}
+	testCases := []struct {
+		name                       string
+		requestBody                interface{}
+		expectedResponseBody       string
+		expectedStatus             int
+		mockGetSenderUserID        mockGetUserIDByEmail
+		mockGetEmailsReceiveUpdate mockGetEmailsReceiveUpdate
+		mockCreateUpdate           mockCreateUpdate
+	}{
+		{
+			name: "Decode request body failed",
+			requestBody: map[string]interface{}{
+				"sender": 1,
+			},
+			expectedResponseBody: "json: cannot unmarshal number into Go struct field UpdateRequest.sender of type string\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "No text",
+			requestBody: map[string]interface{}{
+				"sender": "abc@xyz.com",
+			},
+			expectedResponseBody: "\"text\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Sender does not exist",
+			requestBody: map[string]interface{}{
+				"sender": "abc@xyz.com",
+				"text":   "hello",
+			},
+			expectedResponseBody: "the sender does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetSenderUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+			},
+		},
+		{
+			name: "Get recipients failed with error",
+			requestBody: map[string]interface{}{
+				"sender": "abc@xyz.com",
+				"text":   "hello",
+			},
+			expectedResponseBody: "get recipients failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetSenderUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetEmailsReceiveUpdate: mockGetEmailsReceiveUpdate{
+				sender: 1,
+				text:   "hello",
+				result: nil,
+				err:    errors.New("get recipients failed with error"),
+			},
+		},
+		{
+			name: "Create update failed with error",
+			requestBody: map[string]interface{}{
+				"sender": "abc@xyz.com",
+				"text":   "hello",
+			},
+			expectedResponseBody: "create update failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetSenderUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetEmailsReceiveUpdate: mockGetEmailsReceiveUpdate{
+				sender: 1,
+				text:   "hello",
+				result: []string{"xyz@abc.com"},
+			},
+			mockCreateUpdate: mockCreateUpdate{
+				input: &model.UpdateServiceInput{
+					SenderID:   1,
+					Sender:     "abc@xyz.com",
+					Text:       "hello",
+					Recipients: []string{"xyz@abc.com"},
+				},
+				result: 0,
+				err:    errors.New("create update failed with error"),
+			},
+		},
+		{
+			name: "Create update success",
+			requestBody: map[string]interface{}{
+				"sender": "abc@xyz.com",
+				"text":   "hello kate@example.com",
+			},
+			expectedResponseBody: "{\"success\":true,\"update_id\":5,\"recipients\":[\"xyz@abc.com\",\"kate@example.com\"]}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetSenderUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetEmailsReceiveUpdate: mockGetEmailsReceiveUpdate{
+				sender: 1,
+				text:   "hello kate@example.com",
+				result: []string{"xyz@abc.com", "kate@example.com"},
+			},
+			mockCreateUpdate: mockCreateUpdate{
+				input: &model.UpdateServiceInput{
+					SenderID:   1,
+					Sender:     "abc@xyz.com",
+					Text:       "hello kate@example.com",
+					Recipients: []string{"xyz@abc.com", "kate@example.com"},
+				},
+				result: 5,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockFriendService := new(mockFriendService)
+			mockUpdateService := new(mockUpdateService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetSenderUserID.input).
+				Return(testCase.mockGetSenderUserID.result, testCase.mockGetSenderUserID.err)
+			mockFriendService.On("GetEmailsReceiveUpdate",
+				testCase.mockGetEmailsReceiveUpdate.sender, testCase.mockGetEmailsReceiveUpdate.text).
+				Return(testCase.mockGetEmailsReceiveUpdate.result, testCase.mockGetEmailsReceiveUpdate.err)
+			mockUpdateService.On("CreateUpdate", testCase.mockCreateUpdate.input).
+				Return(testCase.mockCreateUpdate.result, testCase.mockCreateUpdate.err)
+
+			handlers := UpdateHandler{
+				IUserService:    mockUserService,
+				IFriendServices: mockFriendService,
+				IUpdateService:  mockUpdateService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodPost, "/update", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.CreateUpdate)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUpdateHandler_GetFeed(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockGetFeed struct {
+		input  *model.FeedServiceInput
+		result *model.FeedPage
+		err    error
+	}
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	nextCursor := model.EncodeFeedCursor(model.FeedCursor{CreatedAt: createdAt, ID: 4})
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserID        mockGetUserIDByEmail
+		mockGetFeed          mockGetFeed
+	}{
+		{
+			name:                 "Email is required",
+			query:                "",
+			expectedResponseBody: "\"email\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Limit is not an integer",
+			query:                "?email=abc@xyz.com&limit=abc",
+			expectedResponseBody: "\"limit\" must be an integer\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Cursor is not valid",
+			query:                "?email=abc@xyz.com&cursor=abc",
+			expectedResponseBody: "\"cursor\" is not valid\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Email does not exist",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+			},
+		},
+		{
+			name:                 "Get feed failed with error",
+			query:                "?email=abc@xyz.com",
+			expectedResponseBody: "get feed failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetFeed: mockGetFeed{
+				input:  &model.FeedServiceInput{UserID: 1, Limit: 20},
+				result: nil,
+				err:    errors.New("get feed failed with error"),
+			},
+		},
+		{
+			name:                 "Get feed success",
+			query:                "?email=abc@xyz.com&limit=1&cursor=" + nextCursor,
+			expectedResponseBody: "{\"success\":true,\"updates\":[{\"update_id\":3,\"sender\":\"xyz@abc.com\",\"text\":\"hello\",\"created_at\":\"2021-01-02T03:04:05Z\",\"read\":false}],\"next_cursor\":\"" + nextCursor + "\"}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockGetFeed: mockGetFeed{
+				input: &model.FeedServiceInput{
+					UserID: 1,
+					Limit:  1,
+					Cursor: &model.FeedCursor{CreatedAt: createdAt, ID: 4},
+				},
+				result: &model.FeedPage{
+					Updates: []model.FeedItem{
+						{UpdateID: 3, Sender: "xyz@abc.com", Text: "hello", CreatedAt: createdAt},
+					},
+					NextCursor: nextCursor,
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockUpdateService := new(mockUpdateService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+			mockUpdateService.On("GetFeed", testCase.mockGetFeed.input).
+				Return(testCase.mockGetFeed.result, testCase.mockGetFeed.err)
+
+			handlers := UpdateHandler{
+				IUserService:   mockUserService,
+				IUpdateService: mockUpdateService,
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodGet, "/feed"+testCase.query, nil)
+			if err != nil {
+				t.Error(err)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetFeed)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUpdateHandler_MarkFeedAsRead(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockMarkUpdatesAsRead struct {
+		userID    int
+		updateIDs []int
+		err       error
+	}
+	testCases := []struct {
+		name                  string
+		requestBody           interface{}
+		expectedResponseBody  string
+		expectedStatus        int
+		mockGetUserID         mockGetUserIDByEmail
+		mockMarkUpdatesAsRead mockMarkUpdatesAsRead
+	}{
+		{
+			name: "Update IDs are required",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody: "\"update_ids\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Email does not exist",
+			requestBody: map[string]interface{}{
+				"email":      "abc@xyz.com",
+				"update_ids": []int{1},
+			},
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+			},
+		},
+		{
+			name: "Mark as read failed with error",
+			requestBody: map[string]interface{}{
+				"email":      "abc@xyz.com",
+				"update_ids": []int{1, 2},
+			},
+			expectedResponseBody: "mark as read failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockMarkUpdatesAsRead: mockMarkUpdatesAsRead{
+				userID:    1,
+				updateIDs: []int{1, 2},
+				err:       errors.New("mark as read failed with error"),
+			},
+		},
+		{
+			name: "Mark as read success",
+			requestBody: map[string]interface{}{
+				"email":      "abc@xyz.com",
+				"update_ids": []int{1, 2},
+			},
+			expectedResponseBody: "{\"Success\":true}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockMarkUpdatesAsRead: mockMarkUpdatesAsRead{
+				userID:    1,
+				updateIDs: []int{1, 2},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockUpdateService := new(mockUpdateService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+			mockUpdateService.On("MarkUpdatesAsRead", testCase.mockMarkUpdatesAsRead.userID, testCase.mockMarkUpdatesAsRead.updateIDs).
+				Return(testCase.mockMarkUpdatesAsRead.err)
+
+			handlers := UpdateHandler{
+				IUserService:   mockUserService,
+				IUpdateService: mockUpdateService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodPost, "/feed/read", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.MarkFeedAsRead)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
+	"strconv"
 
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/services"
+	"github.com/go-chi/chi"
 )
 
 type UserHandler struct {
@@ -14,6 +16,8 @@ type UserHandler struct {
 }
 
 func (_self *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
 	//Decode request body
 	userRequest := model.UserRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&userRequest); err != nil {
@@ -27,7 +31,7 @@ func (_self *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if statusCode, err := _self.IsExistedUser(userRequest.Email); err != nil {
+	if statusCode, err := _self.IsExistedUser(ctx, userRequest.Email); err != nil {
 		http.Error(w, err.Error(), statusCode)
 		return
 	}
@@ -38,8 +42,8 @@ func (_self *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//Call services
-	if err := _self.IUserService.CreateUser(userServiceInp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := _self.IUserService.CreateUser(ctx, userServiceInp); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
 		return
 	}
 
@@ -49,14 +53,239 @@ func (_self *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (_self *UserHandler) IsExistedUser(email string) (int, error) {
+func (_self *UserHandler) IsExistedUser(ctx context.Context, email string) (int, error) {
 	//Call services
-	existed, err := _self.IUserService.IsExistedUser(email)
+	existed, err := _self.IUserService.IsExistedUser(ctx, email)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 	if existed {
-		return http.StatusAlreadyReported, errors.New("this email address existed")
+		return http.StatusAlreadyReported, model.ErrUserExisted
 	}
 	return 0, nil
 }
+
+func (_self *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read path parameter
+	userRequest := model.UserRequest{
+		Email: chi.URLParam(r, "email"),
+	}
+
+	//Validation
+	if err := userRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	profile, err := _self.IUserService.GetUserProfile(ctx, userRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if profile == nil {
+		http.Error(w, "email does not exist", http.StatusNotFound)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(&model.UserProfileResponse{
+		Success: true,
+		User:    *profile,
+	})
+}
+
+func (_self *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	profileRequest := model.UpdateUserProfileRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&profileRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	profileRequest.Email = chi.URLParam(r, "email")
+
+	//Validation
+	if err := profileRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Convert to services input model
+	profileServiceInp := &model.UserProfileServiceInput{
+		Email:       profileRequest.Email,
+		DisplayName: profileRequest.DisplayName,
+		AvatarURL:   profileRequest.AvatarURL,
+		Bio:         profileRequest.Bio,
+		Status:      profileRequest.Status,
+	}
+
+	//Call services
+	profile, err := _self.IUserService.UpdateUserProfile(ctx, profileServiceInp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if profile == nil {
+		http.Error(w, "email does not exist", http.StatusNotFound)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(&model.UserProfileResponse{
+		Success: true,
+		User:    *profile,
+	})
+}
+
+func (_self *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read query parameters
+	searchRequest := model.UserSearchRequest{
+		Search: r.URL.Query().Get("search"),
+		Limit:  model.DefaultUserSearchLimit,
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil {
+			http.Error(w, "\"limit\" must be an integer", http.StatusBadRequest)
+			return
+		}
+		searchRequest.Limit = parsedLimit
+	}
+
+	//Validation
+	if err := searchRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	users, err := _self.IUserService.SearchUsers(ctx, searchRequest.Search, searchRequest.Limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(&model.UserSearchResponse{
+		Success: true,
+		Users:   users,
+		Count:   len(users),
+	})
+}
+
+func (_self *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read path parameter
+	userRequest := model.UserRequest{
+		Email: chi.URLParam(r, "email"),
+	}
+
+	//Validation
+	if err := userRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	deleted, err := _self.IUserService.DeleteUser(ctx, userRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "email does not exist", http.StatusNotFound)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(&model.SuccessResponse{
+		Success: true,
+	})
+}
+
+func (_self *UserHandler) ExportUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Read path parameter
+	userRequest := model.UserRequest{
+		Email: chi.URLParam(r, "email"),
+	}
+
+	//Validation
+	if err := userRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Call services
+	export, err := _self.IUserService.ExportUser(ctx, userRequest.Email)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if export == nil {
+		http.Error(w, "email does not exist", http.StatusNotFound)
+		return
+	}
+
+	//Response
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"user-export.json\"")
+	json.NewEncoder(w).Encode(export)
+}
+
+func (_self *UserHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	changeRequest := model.ChangeEmailRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&changeRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Validation
+	if err := changeRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//The old email must be a current one and the new email must be free
+	existed, err := _self.IUserService.IsExistedUser(ctx, changeRequest.OldEmail)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !existed {
+		http.Error(w, model.ErrUserNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	if statusCode, err := _self.IsExistedUser(ctx, changeRequest.NewEmail); err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	//Convert to services input model
+	changeServiceInp := &model.ChangeEmailServiceInput{
+		OldEmail: changeRequest.OldEmail,
+		NewEmail: changeRequest.NewEmail,
+	}
+
+	//Call services
+	if err := _self.IUserService.ChangeEmail(ctx, changeServiceInp); err != nil {
+		http.Error(w, err.Error(), serviceErrorStatus(err))
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(&model.SuccessResponse{
+		Success: true,
+	})
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockUserService struct {
 	mock.Mock
 }
 
-func (_self mockUserService) CreateUser(model *model.UserServiceInput) error {
+func (_self mockUserService) CreateUser(ctx context.Context, model *model.UserServiceInput) error {
 	args := _self.Called(model)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,17 @@ func (_self mockUserService) CreateUser(model *model.UserServiceInput) error {
 	return r
 }
 
-func (_self mockUserService) IsExistedUser(email string) (bool, error) {
+func (_self mockUserService) CreateUsers(ctx context.Context, emails []string) (int, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).(int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) IsExistedUser(ctx context.Context, email string) (bool, error) {
 	args := _self.Called(email)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -28,7 +40,7 @@ func (_self mockUserService) IsExistedUser(email string) (bool, error) {
 	return r0, r1
 }
 
-func (_self mockUserService) GetUserIDByEmail(email string) (int, error) {
+func (_self mockUserService) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
 	args := _self.Called(email)
 	r0 := args.Get(0).(int)
 	var r1 error
@@ -38,7 +50,17 @@ func (_self mockUserService) GetUserIDByEmail(email string) (int, error) {
 	return r0, r1
 }
 
-func (_self mockUserService) CheckInvalidEmails(emails []string) ([]string, error) {
+func (_self mockUserService) GetUserIDMapByEmails(ctx context.Context, emails []string) (map[string]int, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).(map[string]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) CheckInvalidEmails(ctx context.Context, emails []string) ([]string, error) {
 	args := _self.Called(emails)
 	r0 := args.Get(0).([]string)
 	var r1 error
@@ -47,3 +69,72 @@ func (_self mockUserService) CheckInvalidEmails(emails []string) ([]string, erro
 	}
 	return r0, r1
 }
+
+func (_self mockUserService) GetUserProfile(ctx context.Context, email string) (*model.UserProfile, error) {
+	args := _self.Called(email)
+	r0 := args.Get(0).(*model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) GetUserProfilesByEmails(ctx context.Context, emails []string) ([]model.UserProfile, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).([]model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) UpdateUserProfile(ctx context.Context, input *model.UserProfileServiceInput) (*model.UserProfile, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(*model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) SearchUsers(ctx context.Context, search string, limit int) ([]model.UserProfile, error) {
+	args := _self.Called(search, limit)
+	r0 := args.Get(0).([]model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) DeleteUser(ctx context.Context, email string) (bool, error) {
+	args := _self.Called(email)
+	r0 := args.Get(0).(bool)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) ExportUser(ctx context.Context, email string) (*model.UserExport, error) {
+	args := _self.Called(email)
+	r0 := args.Get(0).(*model.UserExport)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserService) ChangeEmail(ctx context.Context, input *model.ChangeEmailServiceInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
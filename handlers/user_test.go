@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/require"
 )
 
@@ -111,6 +113,44 @@ func TestUserHandler_CreateUser(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "Email is normalized before reaching services",
+			requestBody: map[string]interface{}{
+				"email": " Abc@XYZ.com ",
+			},
+			expectedResponseBody:   "{\"Success\":true}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockIsUserExisted: mockIsUserExisted{
+				input:  "abc@xyz.com",
+				result: false,
+				err:    nil,
+			},
+			mockCreateUserService: mockCreateUserService{
+				input: &model.UserServiceInput{
+					Email: "abc@xyz.com",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "User email created by a concurrent request",
+			requestBody: map[string]interface{}{
+				"email": "abc@xyz.com",
+			},
+			expectedResponseBody:   "this email address existed\n",
+			expectedResponseStatus: http.StatusAlreadyReported,
+			mockIsUserExisted: mockIsUserExisted{
+				input:  "abc@xyz.com",
+				result: false,
+				err:    nil,
+			},
+			mockCreateUserService: mockCreateUserService{
+				input: &model.UserServiceInput{
+					Email: "abc@xyz.com",
+				},
+				err: model.ErrUserExisted,
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -146,3 +186,616 @@ func TestUserHandler_CreateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_GetUser(t *testing.T) {
+	type mockGetUserProfile struct {
+		input  string
+		result *model.UserProfile
+		err    error
+	}
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name                   string
+		email                  string
+		expectedResponseBody   string
+		expectedResponseStatus int
+		mockGetUserProfile     mockGetUserProfile
+	}{
+		{
+			name:                   "Email's format is not valid",
+			email:                  "abc",
+			expectedResponseBody:   "\"email\"'s format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Get profile failed with error",
+			email:                  "abc@xyz.com",
+			expectedResponseBody:   "get profile failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockGetUserProfile: mockGetUserProfile{
+				input: "abc@xyz.com",
+				err:   errors.New("get profile failed"),
+			},
+		},
+		{
+			name:                   "Email does not exist",
+			email:                  "abc@xyz.com",
+			expectedResponseBody:   "email does not exist\n",
+			expectedResponseStatus: http.StatusNotFound,
+			mockGetUserProfile: mockGetUserProfile{
+				input: "abc@xyz.com",
+			},
+		},
+		{
+			name:  "Get profile success",
+			email: "Abc@XYZ.com",
+			expectedResponseBody: "{\"success\":true,\"user\":{\"email\":\"abc@xyz.com\",\"display_name\":\"Andy\"," +
+				"\"avatar_url\":\"https://abc.xyz/andy.png\",\"bio\":\"Hello\",\"status\":\"away\",\"created_at\":\"2021-01-02T03:04:05Z\"}}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockGetUserProfile: mockGetUserProfile{
+				input: "abc@xyz.com",
+				result: &model.UserProfile{
+					Email:       "abc@xyz.com",
+					DisplayName: "Andy",
+					AvatarURL:   "https://abc.xyz/andy.png",
+					Bio:         "Hello",
+					Status:      model.UserStatusAway,
+					CreatedAt:   createdAt,
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockUserService)
+			mockService.On("GetUserProfile", testCase.mockGetUserProfile.input).
+				Return(testCase.mockGetUserProfile.result, testCase.mockGetUserProfile.err)
+
+			handlers := UserHandler{
+				IUserService: mockService,
+			}
+			router := chi.NewRouter()
+			router.Get("/user/{email}", handlers.GetUser)
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/user/"+testCase.email, nil)
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_UpdateUser(t *testing.T) {
+	type mockUpdateUserProfile struct {
+		input  *model.UserProfileServiceInput
+		result *model.UserProfile
+		err    error
+	}
+	displayName := "Andy"
+	status := model.UserStatusBusy
+	testCases := []struct {
+		name                   string
+		email                  string
+		requestBody            interface{}
+		expectedResponseBody   string
+		expectedResponseStatus int
+		mockUpdateUserProfile  mockUpdateUserProfile
+	}{
+		{
+			name:                   "No field to update",
+			email:                  "abc@xyz.com",
+			requestBody:            map[string]interface{}{},
+			expectedResponseBody:   "needs at least one of \"display_name\", \"avatar_url\", \"bio\" or \"status\"\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Avatar URL is not valid",
+			email: "abc@xyz.com",
+			requestBody: map[string]interface{}{
+				"avatar_url": "ftp://abc.xyz/andy.png",
+			},
+			expectedResponseBody:   "\"avatar_url\" is not valid. (ex: \"https://example.com/avatar.png\")\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Status is not valid",
+			email: "abc@xyz.com",
+			requestBody: map[string]interface{}{
+				"status": "sleeping",
+			},
+			expectedResponseBody:   "\"status\" must be \"active\", \"away\" or \"busy\"\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Email does not exist",
+			email: "abc@xyz.com",
+			requestBody: map[string]interface{}{
+				"status": "busy",
+			},
+			expectedResponseBody:   "email does not exist\n",
+			expectedResponseStatus: http.StatusNotFound,
+			mockUpdateUserProfile: mockUpdateUserProfile{
+				input: &model.UserProfileServiceInput{
+					Email:  "abc@xyz.com",
+					Status: &status,
+				},
+			},
+		},
+		{
+			name:  "Update profile failed with error",
+			email: "abc@xyz.com",
+			requestBody: map[string]interface{}{
+				"status": "busy",
+			},
+			expectedResponseBody:   "update profile failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockUpdateUserProfile: mockUpdateUserProfile{
+				input: &model.UserProfileServiceInput{
+					Email:  "abc@xyz.com",
+					Status: &status,
+				},
+				err: errors.New("update profile failed"),
+			},
+		},
+		{
+			name:  "Update profile success",
+			email: "abc@xyz.com",
+			requestBody: map[string]interface{}{
+				"display_name": " Andy ",
+				"status":       "busy",
+			},
+			expectedResponseBody: "{\"success\":true,\"user\":{\"email\":\"abc@xyz.com\",\"display_name\":\"Andy\"," +
+				"\"avatar_url\":\"\",\"bio\":\"\",\"status\":\"busy\",\"created_at\":\"0001-01-01T00:00:00Z\"}}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockUpdateUserProfile: mockUpdateUserProfile{
+				input: &model.UserProfileServiceInput{
+					Email:       "abc@xyz.com",
+					DisplayName: &displayName,
+					Status:      &status,
+				},
+				result: &model.UserProfile{
+					Email:       "abc@xyz.com",
+					DisplayName: "Andy",
+					Status:      model.UserStatusBusy,
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockUserService)
+			mockService.On("UpdateUserProfile", testCase.mockUpdateUserProfile.input).
+				Return(testCase.mockUpdateUserProfile.result, testCase.mockUpdateUserProfile.err)
+
+			handlers := UserHandler{
+				IUserService: mockService,
+			}
+			router := chi.NewRouter()
+			router.Patch("/user/{email}", handlers.UpdateUser)
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			require.NoError(t, err)
+
+			//When
+			req, err := http.NewRequest(http.MethodPatch, "/user/"+testCase.email, bytes.NewBuffer(requestBody))
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_SearchUsers(t *testing.T) {
+	type mockSearchUsers struct {
+		search string
+		limit  int
+		result []model.UserProfile
+		err    error
+	}
+	testCases := []struct {
+		name                   string
+		query                  string
+		expectedResponseBody   string
+		expectedResponseStatus int
+		mockSearchUsers        mockSearchUsers
+	}{
+		{
+			name:                   "Search is missing",
+			query:                  "?search=%20",
+			expectedResponseBody:   "\"search\" is required\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Limit is not an integer",
+			query:                  "?search=an&limit=abc",
+			expectedResponseBody:   "\"limit\" must be an integer\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Limit is out of range",
+			query:                  "?search=an&limit=101",
+			expectedResponseBody:   "\"limit\" must be between 1 and 100\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Search failed with error",
+			query:                  "?search=An",
+			expectedResponseBody:   "search failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockSearchUsers: mockSearchUsers{
+				search: "an",
+				limit:  20,
+				err:    errors.New("search failed"),
+			},
+		},
+		{
+			name:  "Search success",
+			query: "?search=An&limit=5",
+			expectedResponseBody: "{\"success\":true,\"users\":[{\"email\":\"andy@abc.xyz\",\"display_name\":\"\"," +
+				"\"avatar_url\":\"\",\"bio\":\"\",\"status\":\"active\",\"created_at\":\"0001-01-01T00:00:00Z\"}],\"count\":1}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockSearchUsers: mockSearchUsers{
+				search: "an",
+				limit:  5,
+				result: []model.UserProfile{
+					{Email: "andy@abc.xyz", Status: model.UserStatusActive},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockUserService)
+			mockService.On("SearchUsers", testCase.mockSearchUsers.search, testCase.mockSearchUsers.limit).
+				Return(testCase.mockSearchUsers.result, testCase.mockSearchUsers.err)
+
+			handlers := UserHandler{
+				IUserService: mockService,
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/user"+testCase.query, nil)
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.SearchUsers)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_DeleteUser(t *testing.T) {
+	type mockDeleteUser struct {
+		input  string
+		result bool
+		err    error
+	}
+	testCases := []struct {
+		name                   string
+		email                  string
+		expectedResponseBody   string
+		expectedResponseStatus int
+		mockDeleteUser         mockDeleteUser
+	}{
+		{
+			name:                   "Email's format is not valid",
+			email:                  "abc",
+			expectedResponseBody:   "\"email\"'s format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Delete user failed with error",
+			email:                  "abc@xyz.com",
+			expectedResponseBody:   "delete failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockDeleteUser: mockDeleteUser{
+				input: "abc@xyz.com",
+				err:   errors.New("delete failed"),
+			},
+		},
+		{
+			name:                   "Email does not exist",
+			email:                  "abc@xyz.com",
+			expectedResponseBody:   "email does not exist\n",
+			expectedResponseStatus: http.StatusNotFound,
+			mockDeleteUser: mockDeleteUser{
+				input:  "abc@xyz.com",
+				result: false,
+			},
+		},
+		{
+			name:                   "Delete user success",
+			email:                  "ABC@xyz.com",
+			expectedResponseBody:   "{\"Success\":true}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockDeleteUser: mockDeleteUser{
+				input:  "abc@xyz.com",
+				result: true,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockUserService)
+			mockService.On("DeleteUser", testCase.mockDeleteUser.input).
+				Return(testCase.mockDeleteUser.result, testCase.mockDeleteUser.err)
+
+			handlers := UserHandler{
+				IUserService: mockService,
+			}
+			router := chi.NewRouter()
+			router.Delete("/user/{email}", handlers.DeleteUser)
+
+			//When
+			req, err := http.NewRequest(http.MethodDelete, "/user/"+testCase.email, nil)
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_ExportUser(t *testing.T) {
+	type mockExportUser struct {
+		input  string
+		result *model.UserExport
+		err    error
+	}
+	exportedAt := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	testCases := []struct {
+		name                   string
+		email                  string
+		expectedResponseBody   string
+		expectedResponseStatus int
+		mockExportUser         mockExportUser
+	}{
+		{
+			name:                   "Email's format is not valid",
+			email:                  "abc",
+			expectedResponseBody:   "\"email\"'s format is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name:                   "Export failed with error",
+			email:                  "abc@xyz.com",
+			expectedResponseBody:   "export failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockExportUser: mockExportUser{
+				input: "abc@xyz.com",
+				err:   errors.New("export failed"),
+			},
+		},
+		{
+			name:                   "Email does not exist",
+			email:                  "abc@xyz.com",
+			expectedResponseBody:   "email does not exist\n",
+			expectedResponseStatus: http.StatusNotFound,
+			mockExportUser: mockExportUser{
+				input: "abc@xyz.com",
+			},
+		},
+		{
+			name:  "Export success",
+			email: "abc@xyz.com",
+			expectedResponseBody: "{\"exported_at\":\"2021-02-03T04:05:06Z\",\"profile\":{\"email\":\"abc@xyz.com\",\"display_name\":\"\"," +
+				"\"avatar_url\":\"\",\"bio\":\"\",\"status\":\"active\",\"created_at\":\"0001-01-01T00:00:00Z\"}," +
+				"\"friends\":[{\"email\":\"xyz@abc.com\",\"created_at\":\"2021-02-03T04:05:06Z\"}],\"subscriptions\":[],\"subscribers\":[]," +
+				"\"blocking\":[\"mno@abc.com\"],\"friend_requests\":[],\"updates\":[{\"id\":1,\"text\":\"hello\",\"mentions\":[]," +
+				"\"created_at\":\"2021-02-03T04:05:06Z\"}],\"received_updates\":[],\"webhooks\":[]," +
+				"\"previous_emails\":[{\"email\":\"old@xyz.com\",\"changed_at\":\"2021-02-03T04:05:06Z\"}]}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockExportUser: mockExportUser{
+				input: "abc@xyz.com",
+				result: &model.UserExport{
+					ExportedAt:      exportedAt,
+					Profile:         model.UserProfile{Email: "abc@xyz.com", Status: model.UserStatusActive},
+					Friends:         []model.ExportedFriend{{Email: "xyz@abc.com", CreatedAt: exportedAt}},
+					Subscriptions:   []string{},
+					Subscribers:     []string{},
+					Blocking:        []string{"mno@abc.com"},
+					FriendRequests:  []model.ExportedFriendRequest{},
+					Updates:         []model.ExportedUpdate{{ID: 1, Text: "hello", Mentions: []string{}, CreatedAt: exportedAt}},
+					ReceivedUpdates: []model.ExportedReceivedUpdate{},
+					Webhooks:        []model.ExportedWebhook{},
+					PreviousEmails:  []model.ExportedEmailChange{{Email: "old@xyz.com", ChangedAt: exportedAt}},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockUserService)
+			mockService.On("ExportUser", testCase.mockExportUser.input).
+				Return(testCase.mockExportUser.result, testCase.mockExportUser.err)
+
+			handlers := UserHandler{
+				IUserService: mockService,
+			}
+			router := chi.NewRouter()
+			router.Get("/user/{email}/export", handlers.ExportUser)
+
+			//When
+			req, err := http.NewRequest(http.MethodGet, "/user/"+testCase.email+"/export", nil)
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			router.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_ChangeEmail(t *testing.T) {
+	type mockIsUserExisted struct {
+		input  string
+		result bool
+		err    error
+	}
+	type mockChangeEmail struct {
+		input *model.ChangeEmailServiceInput
+		err   error
+	}
+	testCases := []struct {
+		name                   string
+		requestBody            interface{}
+		expectedResponseBody   string
+		expectedResponseStatus int
+		mockIsUserExisted      []mockIsUserExisted
+		mockChangeEmail        mockChangeEmail
+	}{
+		{
+			name: "New email is missing",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+			},
+			expectedResponseBody:   "\"new_email\" is required\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Emails only differ by case",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": "ABC@xyz.com",
+			},
+			expectedResponseBody:   "two email addresses must be different\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name: "New email's format is not valid",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": "abc",
+			},
+			expectedResponseBody:   "\"new_email\" is not valid. (ex: \"andy@abc.xyz\")\n",
+			expectedResponseStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Old email does not exist",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": "new@xyz.com",
+			},
+			expectedResponseBody:   "email does not exist\n",
+			expectedResponseStatus: http.StatusNotFound,
+			mockIsUserExisted: []mockIsUserExisted{
+				{input: "abc@xyz.com", result: false},
+			},
+		},
+		{
+			name: "New email is taken",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": "new@xyz.com",
+			},
+			expectedResponseBody:   "this email address existed\n",
+			expectedResponseStatus: http.StatusAlreadyReported,
+			mockIsUserExisted: []mockIsUserExisted{
+				{input: "abc@xyz.com", result: true},
+				{input: "new@xyz.com", result: true},
+			},
+		},
+		{
+			name: "New email taken by a concurrent request",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": "new@xyz.com",
+			},
+			expectedResponseBody:   "this email address existed\n",
+			expectedResponseStatus: http.StatusAlreadyReported,
+			mockIsUserExisted: []mockIsUserExisted{
+				{input: "abc@xyz.com", result: true},
+				{input: "new@xyz.com", result: false},
+			},
+			mockChangeEmail: mockChangeEmail{
+				input: &model.ChangeEmailServiceInput{OldEmail: "abc@xyz.com", NewEmail: "new@xyz.com"},
+				err:   model.ErrUserExisted,
+			},
+		},
+		{
+			name: "Change email failed with error",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": "new@xyz.com",
+			},
+			expectedResponseBody:   "change failed\n",
+			expectedResponseStatus: http.StatusInternalServerError,
+			mockIsUserExisted: []mockIsUserExisted{
+				{input: "abc@xyz.com", result: true},
+				{input: "new@xyz.com", result: false},
+			},
+			mockChangeEmail: mockChangeEmail{
+				input: &model.ChangeEmailServiceInput{OldEmail: "abc@xyz.com", NewEmail: "new@xyz.com"},
+				err:   errors.New("change failed"),
+			},
+		},
+		{
+			name: "Change email success",
+			requestBody: map[string]interface{}{
+				"old_email": "abc@xyz.com",
+				"new_email": " New@xyz.com",
+			},
+			expectedResponseBody:   "{\"Success\":true}\n",
+			expectedResponseStatus: http.StatusOK,
+			mockIsUserExisted: []mockIsUserExisted{
+				{input: "abc@xyz.com", result: true},
+				{input: "new@xyz.com", result: false},
+			},
+			mockChangeEmail: mockChangeEmail{
+				input: &model.ChangeEmailServiceInput{OldEmail: "abc@xyz.com", NewEmail: "new@xyz.com"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockService := new(mockUserService)
+			for _, mockIsUserExisted := range testCase.mockIsUserExisted {
+				mockService.On("IsExistedUser", mockIsUserExisted.input).
+					Return(mockIsUserExisted.result, mockIsUserExisted.err)
+			}
+			mockService.On("ChangeEmail", testCase.mockChangeEmail.input).
+				Return(testCase.mockChangeEmail.err)
+
+			handlers := UserHandler{
+				IUserService: mockService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			require.NoError(t, err)
+
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/user/change-email", bytes.NewBuffer(requestBody))
+			require.NoError(t, err)
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.ChangeEmail)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedResponseStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
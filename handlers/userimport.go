@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+// ImportUsers creates the users of a text/csv or application/x-ndjson stream, one email address per line.
+// The stream is read line by line and inserted in chunks of model.UserImportChunkSize,
+// so the file never has to fit in memory. The chunks inserted before an error stay.
+func (_self UserHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request
+	importRequest := model.UserImportRequest{
+		ContentType: r.Header.Get("Content-Type"),
+		Existing:    r.URL.Query().Get("existing"),
+	}
+
+	//Validation
+	if err := importRequest.Validate(); err != nil {
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, model.ErrUserImportContentType) {
+			statusCode = http.StatusUnsupportedMediaType
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	userImport := &userImport{
+		IUserService:   _self.IUserService,
+		csv:            importRequest.ContentType == model.UserImportCSV,
+		reportExisting: importRequest.Existing == model.UserImportReportExisting,
+		summary: model.UserImportResponse{
+			Errors: []model.UserImportLineError{},
+		},
+	}
+
+	//Read the stream line by line
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 4096), model.MaxUserImportLineLength)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if err := userImport.addLine(ctx, lineNumber, scanner.Text()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = fmt.Errorf("is longer than %d bytes", model.MaxUserImportLineLength)
+		}
+		http.Error(w, fmt.Sprintf("line %d %s", lineNumber+1, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	//Insert the last chunk
+	if err := userImport.flush(ctx); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	userImport.summary.Success = true
+	json.NewEncoder(w).Encode(userImport.summary)
+	return
+}
+
+// userImport validates the lines of an import and inserts their email addresses chunk by chunk
+type userImport struct {
+	IUserService   services.IUserService
+	csv            bool
+	reportExisting bool
+	// csvHeaderRead tells the first CSV line was looked at, emailColumn comes from its "email" header when it has one
+	csvHeaderRead bool
+	emailColumn   int
+	chunk         []userImportRow
+	summary       model.UserImportResponse
+}
+
+type userImportRow struct {
+	line  int
+	email string
+}
+
+// addLine validates one line and queues its email address, the chunk is inserted once it is full
+func (_self *userImport) addLine(ctx context.Context, lineNumber int, line string) error {
+	//Spreadsheets often start their CSV files with a byte order mark
+	if lineNumber == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+
+	email, header, err := _self.readEmail(line)
+	if header {
+		return nil
+	}
+	userRequest := model.UserRequest{Email: email}
+	if err == nil {
+		err = userRequest.Validate()
+	}
+	if err != nil {
+		_self.summary.Invalid++
+		_self.addError(lineNumber, err)
+		return nil
+	}
+
+	_self.chunk = append(_self.chunk, userImportRow{line: lineNumber, email: userRequest.Email})
+	if len(_self.chunk) < model.UserImportChunkSize {
+		return nil
+	}
+	return _self.flush(ctx)
+}
+
+// readEmail returns the email address of a line, header is true for the header line of a CSV stream
+func (_self *userImport) readEmail(line string) (string, bool, error) {
+	if !_self.csv {
+		userRequest := model.UserRequest{}
+		if err := json.Unmarshal([]byte(line), &userRequest); err != nil {
+			return "", false, err
+		}
+		return userRequest.Email, false, nil
+	}
+
+	fields, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		//The reader only sees this line, its line number would always be 1
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			err = parseErr.Err
+		}
+		return "", false, err
+	}
+	if !_self.csvHeaderRead {
+		_self.csvHeaderRead = true
+		for i, field := range fields {
+			if strings.EqualFold(strings.TrimSpace(field), "email") {
+				_self.emailColumn = i
+				return "", true, nil
+			}
+		}
+	}
+	if _self.emailColumn >= len(fields) {
+		return "", false, nil
+	}
+	return fields[_self.emailColumn], false, nil
+}
+
+// flush inserts the queued email addresses, the ones which already exist or repeat within the chunk are skipped
+func (_self *userImport) flush(ctx context.Context) error {
+	if len(_self.chunk) == 0 {
+		return nil
+	}
+
+	emails := make([]string, len(_self.chunk))
+	for i, row := range _self.chunk {
+		emails[i] = row.email
+	}
+	newEmails, err := _self.IUserService.CheckInvalidEmails(ctx, emails)
+	if err != nil {
+		return err
+	}
+
+	//The first line of a new email address creates it, the others are reported like existing ones
+	isNew := make(map[string]bool, len(newEmails))
+	for _, email := range newEmails {
+		isNew[email] = true
+	}
+	creating := make([]string, 0, len(newEmails))
+	for _, row := range _self.chunk {
+		if isNew[row.email] {
+			isNew[row.email] = false
+			creating = append(creating, row.email)
+			continue
+		}
+		if _self.reportExisting {
+			_self.addError(row.line, model.ErrUserExisted)
+		}
+	}
+
+	created, err := _self.IUserService.CreateUsers(ctx, creating)
+	if err != nil {
+		return err
+	}
+	_self.summary.Created += created
+	_self.summary.Skipped += len(_self.chunk) - created
+	_self.chunk = _self.chunk[:0]
+	return nil
+}
+
+// addError lists the error of a line until the summary holds model.MaxUserImportErrors of them
+func (_self *userImport) addError(lineNumber int, err error) {
+	if len(_self.summary.Errors) >= model.MaxUserImportErrors {
+		return
+	}
+	_self.summary.Errors = append(_self.summary.Errors, model.UserImportLineError{
+		Line:  lineNumber,
+		Error: err.Error(),
+	})
+}
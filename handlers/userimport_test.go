@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHandler_ImportUsers(t *testing.T) {
+	type mockCheckInvalidEmails struct {
+		input  []string
+		result []string
+		err    error
+	}
+	type mockCreateUsers struct {
+		input  []string
+		result int
+		err    error
+	}
+	testCases := []struct {
+		name                   string
+		contentType            string
+		query                  string
+		requestBody            string
+		chunkSize              int
+		expectedResponseBody   string
+		expectedStatus         int
+		mockCheckInvalidEmails []mockCheckInvalidEmails
+		mockCreateUsers        []mockCreateUsers
+	}{
+		{
+			name:                 "Content type is not supported",
+			contentType:          "application/json",
+			requestBody:          "{\"email\":\"abc@xyz.com\"}",
+			expectedResponseBody: "content type must be \"text/csv\" or \"application/x-ndjson\"\n",
+			expectedStatus:       http.StatusUnsupportedMediaType,
+		},
+		{
+			name:                 "Existing is not valid",
+			contentType:          "text/csv",
+			query:                "?existing=fail",
+			requestBody:          "abc@xyz.com\n",
+			expectedResponseBody: "\"existing\" must be \"skip\" or \"report\"\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Line is too long",
+			contentType:          "text/csv",
+			requestBody:          "abc@xyz.com\n" + strings.Repeat("a", model.MaxUserImportLineLength+1) + "\n",
+			expectedResponseBody: "line 2 is longer than 65536 bytes\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name:                 "Check existing emails failed with error",
+			contentType:          "text/csv",
+			requestBody:          "abc@xyz.com\n",
+			expectedResponseBody: "check failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockCheckInvalidEmails: []mockCheckInvalidEmails{
+				{
+					input: []string{"abc@xyz.com"},
+					err:   errors.New("check failed with error"),
+				},
+			},
+		},
+		{
+			name:                 "Create users failed with error",
+			contentType:          "text/csv",
+			requestBody:          "abc@xyz.com\n",
+			expectedResponseBody: "create failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockCheckInvalidEmails: []mockCheckInvalidEmails{
+				{
+					input:  []string{"abc@xyz.com"},
+					result: []string{"abc@xyz.com"},
+				},
+			},
+			mockCreateUsers: []mockCreateUsers{
+				{
+					input: []string{"abc@xyz.com"},
+					err:   errors.New("create failed with error"),
+				},
+			},
+		},
+		{
+			name:        "CSV with a header and existing emails reported",
+			contentType: "text/csv; charset=utf-8",
+			query:       "?existing=report",
+			requestBody: "\ufeffname,email\r\n" +
+				"Andy,ABC@xyz.com\r\n" +
+				"John,john\r\n" +
+				"\r\n" +
+				"Kate,kate@abc.xyz\r\n" +
+				"Andy again,abc@xyz.com\r\n" +
+				"Lisa\r\n",
+			expectedResponseBody: "{\"success\":true,\"created\":1,\"skipped\":2,\"invalid\":2,\"errors\":[" +
+				"{\"line\":3,\"error\":\"\\\"email\\\"'s format is not valid. (ex: \\\"andy@abc.xyz\\\")\"}," +
+				"{\"line\":7,\"error\":\"\\\"email\\\" is required\"}," +
+				"{\"line\":2,\"error\":\"this email address existed\"}," +
+				"{\"line\":6,\"error\":\"this email address existed\"}" +
+				"]}\n",
+			expectedStatus: http.StatusOK,
+			mockCheckInvalidEmails: []mockCheckInvalidEmails{
+				{
+					input:  []string{"abc@xyz.com", "kate@abc.xyz", "abc@xyz.com"},
+					result: []string{"kate@abc.xyz"},
+				},
+			},
+			mockCreateUsers: []mockCreateUsers{
+				{
+					input:  []string{"kate@abc.xyz"},
+					result: 1,
+				},
+			},
+		},
+		{
+			name:        "NDJSON inserted in chunks",
+			contentType: "application/x-ndjson",
+			chunkSize:   2,
+			requestBody: "{\"email\":\"a@xyz.com\"}\n" +
+				"{\"email\":1}\n" +
+				"{\"email\":\"b@xyz.com\"}\n" +
+				"{\"email\":\"c@xyz.com\"}\n" +
+				"{\"email\":\"a@xyz.com\"}",
+			expectedResponseBody: "{\"success\":true,\"created\":2,\"skipped\":2,\"invalid\":1,\"errors\":[" +
+				"{\"line\":2,\"error\":\"json: cannot unmarshal number into Go struct field UserRequest.email of type string\"}" +
+				"]}\n",
+			expectedStatus: http.StatusOK,
+			mockCheckInvalidEmails: []mockCheckInvalidEmails{
+				{
+					input:  []string{"a@xyz.com", "b@xyz.com"},
+					result: []string{"a@xyz.com", "b@xyz.com"},
+				},
+				{
+					input:  []string{"c@xyz.com", "a@xyz.com"},
+					result: []string{"c@xyz.com"},
+				},
+			},
+			mockCreateUsers: []mockCreateUsers{
+				{
+					input:  []string{"a@xyz.com", "b@xyz.com"},
+					result: 2,
+				},
+				{
+					//Another request created it in the meantime
+					input:  []string{"c@xyz.com"},
+					result: 0,
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserService := new(mockUserService)
+			for _, mock := range testCase.mockCheckInvalidEmails {
+				mockUserService.On("CheckInvalidEmails", mock.input).
+					Return(mock.result, mock.err).Once()
+			}
+			for _, mock := range testCase.mockCreateUsers {
+				mockUserService.On("CreateUsers", mock.input).
+					Return(mock.result, mock.err).Once()
+			}
+			if testCase.chunkSize > 0 {
+				chunkSize := model.UserImportChunkSize
+				model.UserImportChunkSize = testCase.chunkSize
+				defer func() { model.UserImportChunkSize = chunkSize }()
+			}
+
+			handlers := UserHandler{
+				IUserService: mockUserService,
+			}
+
+			//When
+			req, err := http.NewRequest(http.MethodPost, "/user/import"+testCase.query, strings.NewReader(testCase.requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+			req.Header.Set("Content-Type", testCase.contentType)
+
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.ImportUsers)
+			handler.ServeHTTP(responseRecorder, req)
+
+			//Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
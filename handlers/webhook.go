@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/services"
+)
+
+type WebhookHandler struct {
+	IUserService    services.IUserService
+	IWebhookService services.IWebhookService
+}
+
+func (_self WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Decode request body
+	webhookRequest := model.WebhookRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&webhookRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if err := webhookRequest.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//Webhooks without an email are global
+	webhookServiceInput := &model.WebhookServiceInput{
+		URL:    webhookRequest.URL,
+		Secret: webhookRequest.Secret,
+	}
+	if webhookRequest.Email != "" {
+		userID, err := _self.IUserService.GetUserIDByEmail(ctx, webhookRequest.Email)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if userID == 0 {
+			http.Error(w, "email does not exist", http.StatusBadRequest)
+			return
+		}
+		webhookServiceInput.UserID = userID
+	}
+
+	//Call services
+	id, err := _self.IWebhookService.CreateWebhook(ctx, webhookServiceInput)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.CreateWebhookResponse{
+		Success: true,
+		ID:      id,
+	})
+}
+
+func (_self WebhookHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	//Call services
+	deadLetters, err := _self.IWebhookService.GetDeadLetters(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	//Response
+	json.NewEncoder(w).Encode(model.WebhookDeadLettersResponse{
+		Success:     true,
+		DeadLetters: deadLetters,
+		Count:       len(deadLetters),
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockWebhookService struct {
+	mock.Mock
+}
+
+func (_self mockWebhookService) CreateWebhook(ctx context.Context, input *model.WebhookServiceInput) (int, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockWebhookService) GetDeadLetters(ctx context.Context) ([]model.WebhookDeadLetter, error) {
+	args := _self.Called()
+	r0 := args.Get(0).([]model.WebhookDeadLetter)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	type mockGetUserIDByEmail struct {
+		input  string
+		result int
+		err    error
+	}
+	type mockCreateWebhook struct {
+		input  *model.WebhookServiceInput
+		result int
+		err    error
+	}
+	testCases := []struct {
+		name                 string
+		requestBody          interface{}
+		expectedResponseBody string
+		expectedStatus       int
+		mockGetUserID        mockGetUserIDByEmail
+		mockCreateWebhook    mockCreateWebhook
+	}{
+		{
+			name: "URL is required",
+			requestBody: map[string]interface{}{
+				"secret": "s3cr3t",
+			},
+			expectedResponseBody: "\"url\" is required\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "URL is not valid",
+			requestBody: map[string]interface{}{
+				"url":    "ftp://example.com/hook",
+				"secret": "s3cr3t",
+			},
+			expectedResponseBody: "\"url\" is not valid. (ex: \"https://example.com/hook\")\n",
+			expectedStatus:       http.StatusBadRequest,
+		},
+		{
+			name: "Email does not exist",
+			requestBody: map[string]interface{}{
+				"email":  "abc@xyz.com",
+				"url":    "https://example.com/hook",
+				"secret": "s3cr3t",
+			},
+			expectedResponseBody: "email does not exist\n",
+			expectedStatus:       http.StatusBadRequest,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 0,
+			},
+		},
+		{
+			name: "Create webhook failed with error",
+			requestBody: map[string]interface{}{
+				"url":    "https://example.com/hook",
+				"secret": "s3cr3t",
+			},
+			expectedResponseBody: "create webhook failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockCreateWebhook: mockCreateWebhook{
+				input: &model.WebhookServiceInput{
+					URL:    "https://example.com/hook",
+					Secret: "s3cr3t",
+				},
+				result: 0,
+				err:    errors.New("create webhook failed with error"),
+			},
+		},
+		{
+			name: "Create user webhook success",
+			requestBody: map[string]interface{}{
+				"email":  "abc@xyz.com",
+				"url":    "https://example.com/hook",
+				"secret": "s3cr3t",
+			},
+			expectedResponseBody: "{\"success\":true,\"id\":3}\n",
+			expectedStatus:       http.StatusOK,
+			mockGetUserID: mockGetUserIDByEmail{
+				input:  "abc@xyz.com",
+				result: 1,
+			},
+			mockCreateWebhook: mockCreateWebhook{
+				input: &model.WebhookServiceInput{
+					UserID: 1,
+					URL:    "https://example.com/hook",
+					Secret: "s3cr3t",
+				},
+				result: 3,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUserService := new(mockUserService)
+			mockWebhookService := new(mockWebhookService)
+
+			mockUserService.On("GetUserIDByEmail", testCase.mockGetUserID.input).
+				Return(testCase.mockGetUserID.result, testCase.mockGetUserID.err)
+			mockWebhookService.On("CreateWebhook", testCase.mockCreateWebhook.input).
+				Return(testCase.mockCreateWebhook.result, testCase.mockCreateWebhook.err)
+
+			handlers := WebhookHandler{
+				IUserService:    mockUserService,
+				IWebhookService: mockWebhookService,
+			}
+
+			requestBody, err := json.Marshal(testCase.requestBody)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodPost, "/webhook", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Error(err)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.CreateWebhook)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
+
+func TestWebhookHandler_GetDeadLetters(t *testing.T) {
+	failedAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name                 string
+		expectedResponseBody string
+		expectedStatus       int
+		mockResult           []model.WebhookDeadLetter
+		mockErr              error
+	}{
+		{
+			name:                 "Get dead letters failed with error",
+			expectedResponseBody: "get dead letters failed with error\n",
+			expectedStatus:       http.StatusInternalServerError,
+			mockResult:           nil,
+			mockErr:              errors.New("get dead letters failed with error"),
+		},
+		{
+			name:                 "Get dead letters success",
+			expectedResponseBody: "{\"success\":true,\"dead_letters\":[{\"id\":1,\"webhook_id\":2,\"url\":\"https://example.com/hook\",\"payload\":{\"update_id\":1},\"attempts\":8,\"last_error\":\"webhook responded with status 500\",\"failed_at\":\"2021-01-02T03:04:05Z\"}],\"count\":1}\n",
+			expectedStatus:       http.StatusOK,
+			mockResult: []model.WebhookDeadLetter{
+				{
+					ID:        1,
+					WebhookID: 2,
+					URL:       "https://example.com/hook",
+					Payload:   json.RawMessage(`{"update_id":1}`),
+					Attempts:  8,
+					LastError: "webhook responded with status 500",
+					FailedAt:  failedAt,
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockWebhookService := new(mockWebhookService)
+			mockWebhookService.On("GetDeadLetters").Return(testCase.mockResult, testCase.mockErr)
+
+			handlers := WebhookHandler{
+				IWebhookService: mockWebhookService,
+			}
+
+			// When
+			req, err := http.NewRequest(http.MethodGet, "/webhook/dead-letters", nil)
+			if err != nil {
+				t.Error(err)
+			}
+			responseRecorder := httptest.NewRecorder()
+			handler := http.HandlerFunc(handlers.GetDeadLetters)
+			handler.ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
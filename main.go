@@ -1,49 +1,102 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"flag"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
 
+	"S3_FriendManagement_ThinhNguyen/config"
+	"S3_FriendManagement_ThinhNguyen/handlers"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
 	"S3_FriendManagement_ThinhNguyen/routes"
-	"github.com/joho/godotenv"
+	"S3_FriendManagement_ThinhNguyen/services"
+	"S3_FriendManagement_ThinhNguyen/utils"
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	//Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error load .env file")
+	//Command-line modes, ex: -export-graph=graph.json or -import-graph=backup -graph-format=csv
+	exportGraph := flag.String("export-graph", "", "write the friend graph to this file, or directory for csv, and exit")
+	importGraph := flag.String("import-graph", "", "rebuild an empty database from this file, or directory for csv, and exit")
+	graphFormat := flag.String("graph-format", model.GraphFormatJSON, "format of -export-graph and -import-graph: json, csv or graphml")
+	//Schema migrations, ex: -migrate to apply the pending ones before anything else, -migrate-down=1 to roll back the last one
+	migrate := flag.Bool("migrate", false, "apply the pending schema migrations on startup")
+	migrateDown := flag.Int("migrate-down", 0, "roll back this many schema migrations, newest first, and exit")
+
+	//Load settings from .env or -config, the environment and the flags
+	settings, err := config.Load(flag.CommandLine, os.Args[1:], os.LookupEnv)
+	if err != nil {
+		log.Fatal(err)
 	}
+	log.Printf("settings:\n%s", settings.Summary())
+	applySettings(settings)
 
 	//Connect DB
-	db := ConnectDB()
+	db := ConnectDB(settings.Database)
 	defer db.Close()
 
+	if *migrateDown > 0 {
+		if err := rollbackMigrations(db, *migrateDown); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *migrate {
+		if err := applyMigrations(db); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *exportGraph != "" || *importGraph != "" {
+		if err := runGraphCommand(db, *exportGraph, *importGraph, *graphFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	//Deliver queued webhooks in the background, unless another server does it
+	if settings.WebhooksEnabled {
+		dispatcher := services.WebhookDispatcher{
+			IWebhookRepo: repositories.WebhookRepo{
+				Db: db,
+			},
+			Client: &http.Client{Timeout: settings.WebhookTimeout},
+		}
+		go dispatcher.Run(context.Background(), settings.WebhookInterval)
+	}
+
 	//create routes
 	r := routes.CreateRoutes(db)
-	log.Fatal(http.ListenAndServe(":8080", r))
+	log.Fatal(http.ListenAndServe(settings.ListenAddr, r))
 }
 
-func ConnectDB() *sql.DB {
-	var (
-		host     = os.Getenv("POSTGRES_HOST")
-		port, _  = strconv.Atoi(os.Getenv("POSTGRES_PORT"))
-		user     = os.Getenv("POSTGRES_USER")
-		password = os.Getenv("POSTGRES_PASSWORD")
-		dbname   = os.Getenv("POSTGRES_DBNAME")
-	)
-
-	pgsqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+"password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// applySettings hands the settings to the packages which read them from variables
+func applySettings(settings *config.Config) {
+	routes.DefaultRouteTimeout = settings.RouteTimeout
+	routes.RouteTimeouts = settings.RouteTimeouts
+	routes.EventsEnabled = settings.EventsEnabled
+	handlers.EventsHeartbeatInterval = settings.EventsHeartbeat
+	repositories.EmailGracePeriod = settings.EmailGracePeriod
+	model.MaxBatchSize = settings.MaxBatchSize
+	model.MaxCommonFriendsEmails = settings.MaxCommonFriendsEmails
+	model.UserImportChunkSize = settings.UserImportChunkSize
+	if settings.EmailGmailRules {
+		utils.EnableGmailRules()
+	}
+}
 
-	db, err := sql.Open("postgres", pgsqlInfo)
+func ConnectDB(database config.Database) *sql.DB {
+	db, err := sql.Open("postgres", database.DSN())
 	if err != nil {
 		panic(err)
 	}
+	db.SetMaxOpenConns(database.MaxOpenConns)
+	db.SetMaxIdleConns(database.MaxIdleConns)
+	db.SetConnMaxLifetime(database.ConnMaxLifetime)
 
 	return db
 }
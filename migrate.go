@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"S3_FriendManagement_ThinhNguyen/migrations"
+)
+
+// applyMigrations runs the -migrate mode, which brings the schema up to date before serving
+func applyMigrations(db *sql.DB) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	versions, err := migrator.Up(context.Background())
+	if len(versions) > 0 {
+		log.Printf("applied migrations %v", versions)
+	}
+	return err
+}
+
+// rollbackMigrations runs the -migrate-down mode, which rolls back the last steps migrations
+func rollbackMigrations(db *sql.DB, steps int) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	versions, err := migrator.Down(context.Background(), steps)
+	if len(versions) > 0 {
+		log.Printf("rolled back migrations %v", versions)
+	}
+	return err
+}
+
+func newMigrator(db *sql.DB) (migrations.Migrator, error) {
+	embedded, err := migrations.Embedded()
+	if err != nil {
+		return migrations.Migrator{}, err
+	}
+	return migrations.Migrator{
+		Db:         db,
+		Migrations: embedded,
+	}, nil
+}
@@ -0,0 +1,266 @@
+// Package migrations keeps the database schema as numbered migrations embedded in the binary.
+// Every schema change is a new pair of files in sql/, named <version>_<name>.up.sql and <version>_<name>.down.sql,
+// and the applied versions are kept in the schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockID is the advisory lock held while migrating, so two servers starting together do not both migrate
+const lockID = 5_310_342_024
+
+var fileNameRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Embedded returns the migrations of the binary, ordered by version
+func Embedded() ([]Migration, error) {
+	sqlFiles, err := fs.Sub(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return Load(sqlFiles)
+}
+
+// Load reads the migrations of a directory, ordered by version. Every version needs an up and a down file.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileNameRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			return nil, fmt.Errorf("migration file %q is not valid. (ex: \"0001_create_users.up.sql\")", entry.Name())
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %q: version must be a positive number", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+type Migrator struct {
+	Db         *sql.DB
+	Migrations []Migration
+}
+
+// Up applies the pending migrations in order, each one in its own transaction, and returns their versions.
+// It refuses to run when the database has versions this binary does not know, or when a pending
+// migration is older than an applied one.
+func (_self Migrator) Up(ctx context.Context) ([]int, error) {
+	conn, err := _self.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer _self.unlock(conn)
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := _self.checkApplied(applied); err != nil {
+		return nil, err
+	}
+
+	versions := []int{}
+	latest := 0
+	if len(applied) > 0 {
+		latest = applied[len(applied)-1]
+	}
+	isApplied := make(map[int]bool, len(applied))
+	for _, version := range applied {
+		isApplied[version] = true
+	}
+	for _, migration := range _self.Migrations {
+		if isApplied[migration.Version] {
+			continue
+		}
+		if migration.Version < latest {
+			return versions, fmt.Errorf("migration %d_%s is older than the applied migration %d", migration.Version, migration.Name, latest)
+		}
+		query := `insert into schema_migrations(version, name) values ($1, $2)`
+		if err := run(ctx, conn, migration, migration.Up, query, migration.Version, migration.Name); err != nil {
+			return versions, err
+		}
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns their versions
+func (_self Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	conn, err := _self.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer _self.unlock(conn)
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := _self.checkApplied(applied); err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]Migration, len(_self.Migrations))
+	for _, migration := range _self.Migrations {
+		byVersion[migration.Version] = migration
+	}
+	versions := []int{}
+	for i := len(applied) - 1; i >= 0 && len(versions) < steps; i-- {
+		migration := byVersion[applied[i]]
+		query := `delete from schema_migrations where version = $1`
+		if err := run(ctx, conn, migration, migration.Down, query, migration.Version); err != nil {
+			return versions, err
+		}
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
+
+// Version returns the latest applied migration, 0 when there is none
+func (_self Migrator) Version(ctx context.Context) (int, error) {
+	conn, err := _self.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer _self.unlock(conn)
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil || len(applied) == 0 {
+		return 0, err
+	}
+	return applied[len(applied)-1], nil
+}
+
+// lock takes the migration lock on a connection of its own and creates schema_migrations when it is missing
+func (_self Migrator) lock(ctx context.Context) (*sql.Conn, error) {
+	conn, err := _self.Db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, `select pg_advisory_lock($1)`, lockID); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	query := `create table if not exists public.schema_migrations
+			  (
+			  	version int8 not null primary key,
+			  	name varchar(255) not null,
+			  	appliedat timestamp not null default now()
+			  )`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		_self.unlock(conn)
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (_self Migrator) unlock(conn *sql.Conn) {
+	//The lock is released with the session if the unlock fails
+	conn.ExecContext(context.Background(), `select pg_advisory_unlock($1)`, lockID)
+	conn.Close()
+}
+
+func (_self Migrator) checkApplied(applied []int) error {
+	known := make(map[int]bool, len(_self.Migrations))
+	for _, migration := range _self.Migrations {
+		known[migration.Version] = true
+	}
+	for _, version := range applied {
+		if !known[version] {
+			return fmt.Errorf("the database has migration %d which this binary does not know", version)
+		}
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) ([]int, error) {
+	rows, err := conn.QueryContext(ctx, `select version from schema_migrations order by version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]int, 0)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// run executes the statements of script and then query, which records the migration, in one transaction
+func run(ctx context.Context, conn *sql.Conn, migration Migration, script string, query string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, statement := range utils.SplitSQL(script) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migration %d_%s, statement %d: %v", migration.Version, migration.Name, i+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,154 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"S3_FriendManagement_ThinhNguyen/testhelpers"
+	"S3_FriendManagement_ThinhNguyen/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name           string
+		files          fstest.MapFS
+		expectedErr    error
+		expectedResult []Migration
+	}{
+		{
+			name:        "File name is not valid",
+			files:       fstest.MapFS{"create_users.sql": {Data: []byte("select 1")}},
+			expectedErr: errors.New("migration file \"create_users.sql\" is not valid. (ex: \"0001_create_users.up.sql\")"),
+		},
+		{
+			name:        "Version is not positive",
+			files:       fstest.MapFS{"0000_create_users.up.sql": {Data: []byte("select 1")}},
+			expectedErr: errors.New("migration file \"0000_create_users.up.sql\": version must be a positive number"),
+		},
+		{
+			name: "Version has two names",
+			files: fstest.MapFS{
+				"0001_create_users.up.sql":    {Data: []byte("select 1")},
+				"0001_create_emails.down.sql": {Data: []byte("select 1")},
+			},
+			expectedErr: errors.New("migration 1 is named both \"create_emails\" and \"create_users\""),
+		},
+		{
+			name:        "Down file is missing",
+			files:       fstest.MapFS{"0001_create_users.up.sql": {Data: []byte("select 1")}},
+			expectedErr: errors.New("migration 1_create_users needs both an up and a down file"),
+		},
+		{
+			name: "Load success",
+			files: fstest.MapFS{
+				"0010_add_bio.up.sql":        {Data: []byte("alter table users add column bio text")},
+				"0010_add_bio.down.sql":      {Data: []byte("alter table users drop column bio")},
+				"0002_create_users.up.sql":   {Data: []byte("create table users (id int8)")},
+				"0002_create_users.down.sql": {Data: []byte("drop table users")},
+			},
+			expectedResult: []Migration{
+				{Version: 2, Name: "create_users", Up: "create table users (id int8)", Down: "drop table users"},
+				{Version: 10, Name: "add_bio", Up: "alter table users add column bio text", Down: "alter table users drop column bio"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			result, err := Load(testCase.files)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	// When
+	result, err := Embedded()
+
+	// Then
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+	for i, migration := range result {
+		require.Equal(t, i+1, migration.Version, "versions follow each other")
+		require.NotEmpty(t, utils.SplitSQL(migration.Up), migration.Name)
+		require.NotEmpty(t, utils.SplitSQL(migration.Down), migration.Name)
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectedErr error
+		mockDb      *sql.DB
+	}{
+		{
+			name:        "Migrate failed with error",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:   "Migrate success",
+			mockDb: testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			embedded, err := Embedded()
+			require.NoError(t, err)
+
+			migrator := Migrator{
+				Db:         testCase.mockDb,
+				Migrations: embedded,
+			}
+
+			// When
+			_, err = migrator.Up(context.Background())
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			//Every migration is applied once
+			versions, err := migrator.Up(context.Background())
+			require.NoError(t, err)
+			require.Empty(t, versions)
+			version, err := migrator.Version(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, embedded[len(embedded)-1].Version, version)
+		})
+	}
+}
+
+func TestMigrator_Up_UnknownVersion(t *testing.T) {
+	// Given
+	embedded, err := Embedded()
+	require.NoError(t, err)
+	migrator := Migrator{
+		Db:         testhelpers.ConnectDB(),
+		Migrations: embedded[:1],
+	}
+	_, err = Migrator{Db: migrator.Db, Migrations: embedded}.Up(context.Background())
+	require.NoError(t, err)
+
+	// When
+	_, err = migrator.Up(context.Background())
+
+	// Then
+	require.EqualError(t, err, "the database has migration 2 which this binary does not know")
+}
@@ -0,0 +1,5 @@
+create table if not exists public.useremails
+(
+    id int8 not null generated always as identity primary key,
+    email varchar(100) not null
+);
@@ -0,0 +1 @@
+drop index if exists public.useremails_email_uq;
@@ -0,0 +1,36 @@
+-- normalize stored emails: report the users whose addresses only differ by case or surrounding spaces,
+-- they have to be merged by hand before the unique index can be created, the migration fails until then
+do $$
+declare
+    duplicate record;
+    duplicates int := 0;
+begin
+    for duplicate in
+        select lower(trim(email)) as email, string_agg(id || ' ' || quote_literal(email), ', ' order by id) as users
+        from public.useremails
+        group by lower(trim(email))
+        having count(*) > 1
+    loop
+        duplicates := duplicates + 1;
+        raise warning 'duplicate email %: users %', duplicate.email, duplicate.users;
+    end loop;
+
+    -- addresses which would collide once the optional Gmail rules are turned on, reported only
+    for duplicate in
+        select regexp_replace(split_part(split_part(lower(trim(email)), '@', 1), '+', 1), '\.', '', 'g') || '@gmail.com' as email,
+               string_agg(id || ' ' || quote_literal(email), ', ' order by id) as users
+        from public.useremails
+        where split_part(lower(trim(email)), '@', 2) in ('gmail.com', 'googlemail.com')
+        group by 1
+        having count(*) > 1
+    loop
+        raise warning 'duplicate email % with the Gmail rules: users %', duplicate.email, duplicate.users;
+    end loop;
+
+    if duplicates > 0 then
+        raise exception '% duplicate emails found, merge them before useremails_email_uq can be created', duplicates;
+    end if;
+
+    update public.useremails set email = lower(trim(email)) where email <> lower(trim(email));
+    create unique index if not exists useremails_email_uq on public.useremails (lower(email));
+end $$;
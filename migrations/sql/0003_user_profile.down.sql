@@ -0,0 +1,10 @@
+drop index if exists public.useremails_displayname_prefix_idx;
+drop index if exists public.useremails_email_prefix_idx;
+
+alter table public.useremails drop constraint if exists useremails_status_check;
+
+alter table public.useremails drop column if exists createdat;
+alter table public.useremails drop column if exists status;
+alter table public.useremails drop column if exists bio;
+alter table public.useremails drop column if exists avatarurl;
+alter table public.useremails drop column if exists displayname;
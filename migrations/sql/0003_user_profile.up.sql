@@ -0,0 +1,13 @@
+-- user profile
+alter table public.useremails add column if not exists displayname varchar(100) not null default '';
+alter table public.useremails add column if not exists avatarurl varchar(2048) not null default '';
+alter table public.useremails add column if not exists bio varchar(500) not null default '';
+alter table public.useremails add column if not exists status varchar(20) not null default 'active';
+alter table public.useremails add column if not exists createdat timestamp not null default now();
+
+alter table public.useremails drop constraint if exists useremails_status_check;
+alter table public.useremails add constraint useremails_status_check check (status in ('active', 'away', 'busy'));
+
+-- prefix search on email and display name
+create index if not exists useremails_email_prefix_idx on public.useremails (email varchar_pattern_ops);
+create index if not exists useremails_displayname_prefix_idx on public.useremails (lower(displayname) text_pattern_ops);
@@ -0,0 +1,20 @@
+create table if not exists public.friends
+(
+    id int8 not null generated always as identity primary key,
+    firstid int8 not null,
+    secondid int8 not null,
+    createdat timestamp not null default now(),
+    constraint firstemail_fk foreign key (firstid) references public.useremails(id),
+    constraint secondemail_fk foreign key (secondid) references public.useremails(id)
+);
+
+alter table public.friends add column if not exists createdat timestamp not null default now();
+
+-- keep one friend connection per pair of users, whichever way round it was stored
+delete from public.friends a using public.friends b
+where a.id > b.id
+  and least(a.firstid, a.secondid) = least(b.firstid, b.secondid)
+  and greatest(a.firstid, a.secondid) = greatest(b.firstid, b.secondid);
+
+create unique index if not exists friends_pair_uq
+    on public.friends (least(firstid, secondid), greatest(firstid, secondid));
@@ -0,0 +1,13 @@
+create table if not exists public.subscriptions
+(
+    id int8 not null generated always as identity primary key,
+    requestorid int8 not null,
+    targetid int8 not null,
+    constraint requestid_fk foreign key (requestorid) references public.useremails(id),
+    constraint targetid_fk foreign key (targetid) references public.useremails(id)
+);
+
+delete from public.subscriptions a using public.subscriptions b
+where a.id > b.id and a.requestorid = b.requestorid and a.targetid = b.targetid;
+
+create unique index if not exists subscriptions_pair_uq on public.subscriptions (requestorid, targetid);
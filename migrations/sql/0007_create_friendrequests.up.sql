@@ -0,0 +1,12 @@
+create table if not exists public.friendrequests
+(
+    id int8 not null generated always as identity primary key,
+    requestorid int8 not null,
+    targetid int8 not null,
+    status varchar(20) not null default 'pending',
+    createdat timestamp not null default now(),
+    updatedat timestamp not null default now(),
+    constraint requestid_fk foreign key (requestorid) references public.useremails(id),
+    constraint targetid_fk foreign key (targetid) references public.useremails(id),
+    constraint status_check check (status in ('pending', 'accepted', 'rejected', 'cancelled'))
+);
@@ -0,0 +1,2 @@
+drop table if exists public.updatedeliveries;
+drop table if exists public.updates;
@@ -0,0 +1,19 @@
+create table if not exists public.updates
+(
+    id int8 not null generated always as identity primary key,
+    senderid int8 not null,
+    text text not null,
+    mentions varchar(100)[] not null default '{}',
+    createdat timestamp not null default now(),
+    constraint senderid_fk foreign key (senderid) references public.useremails(id)
+);
+
+create table if not exists public.updatedeliveries
+(
+    id int8 not null generated always as identity primary key,
+    updateid int8 not null,
+    recipientid int8 not null,
+    readat timestamp,
+    constraint updateid_fk foreign key (updateid) references public.updates(id),
+    constraint recipientid_fk foreign key (recipientid) references public.useremails(id)
+);
@@ -0,0 +1,2 @@
+drop table if exists public.webhookoutbox;
+drop table if exists public.webhooks;
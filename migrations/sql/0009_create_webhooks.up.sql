@@ -0,0 +1,24 @@
+create table if not exists public.webhooks
+(
+    id int8 not null generated always as identity primary key,
+    userid int8,
+    url varchar(2048) not null,
+    secret varchar(255) not null,
+    createdat timestamp not null default now(),
+    constraint userid_fk foreign key (userid) references public.useremails(id)
+);
+
+create table if not exists public.webhookoutbox
+(
+    id int8 not null generated always as identity primary key,
+    webhookid int8 not null,
+    payload text not null,
+    status varchar(20) not null default 'pending',
+    attempts int not null default 0,
+    nextattemptat timestamp not null default now(),
+    lasterror text,
+    createdat timestamp not null default now(),
+    updatedat timestamp not null default now(),
+    constraint webhookid_fk foreign key (webhookid) references public.webhooks(id),
+    constraint status_check check (status in ('pending', 'delivered', 'dead'))
+);
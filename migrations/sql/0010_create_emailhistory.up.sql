@@ -0,0 +1,10 @@
+create table if not exists public.emailhistory
+(
+    id int8 not null generated always as identity primary key,
+    userid int8 not null,
+    email varchar(100) not null,
+    changedat timestamp not null default now(),
+    constraint userid_fk foreign key (userid) references public.useremails(id)
+);
+
+create index if not exists emailhistory_email_idx on public.emailhistory (email, changedat);
@@ -0,0 +1,3 @@
+update public.webhookoutbox set status = 'pending' where status = 'sending';
+alter table public.webhookoutbox drop constraint status_check;
+alter table public.webhookoutbox add constraint status_check check (status in ('pending', 'delivered', 'dead'));
@@ -0,0 +1,3 @@
+-- 'sending' marks the entries a dispatcher has claimed, until their nextattemptat when another one may take them
+alter table public.webhookoutbox drop constraint status_check;
+alter table public.webhookoutbox add constraint status_check check (status in ('pending', 'sending', 'delivered', 'dead'));
@@ -0,0 +1 @@
+drop index if exists public.friendrequests_pending_pair_uq;
@@ -0,0 +1,13 @@
+-- keep one pending friend request per pair of users, whichever of them sent it: the newer duplicates are cancelled
+update public.friendrequests a
+set status = 'cancelled', updatedat = now()
+from public.friendrequests b
+where a.id > b.id
+  and a.status = 'pending'
+  and b.status = 'pending'
+  and least(a.requestorid, a.targetid) = least(b.requestorid, b.targetid)
+  and greatest(a.requestorid, a.targetid) = greatest(b.requestorid, b.targetid);
+
+create unique index if not exists friendrequests_pending_pair_uq
+    on public.friendrequests (least(requestorid, targetid), greatest(requestorid, targetid))
+    where status = 'pending';
@@ -0,0 +1,55 @@
+package model
+
+import "fmt"
+
+// MaxBatchSize caps how many items a batch request accepts.
+// It is a variable so the cap can be changed at startup.
+var MaxBatchSize = 1000
+
+// validateBatchSize checks the number of items of a batch request
+func validateBatchSize(field string, size int) error {
+	if size == 0 {
+		return fmt.Errorf("%q is required", field)
+	}
+	if size > MaxBatchSize {
+		return fmt.Errorf("%q accepts at most %d items", field, MaxBatchSize)
+	}
+	return nil
+}
+
+type FriendBatchRequest struct {
+	Friends [][]string `json:"friends"`
+}
+
+func (_self *FriendBatchRequest) Validate() error {
+	return validateBatchSize("friends", len(_self.Friends))
+}
+
+type SubscriptionBatchRequest struct {
+	Subscriptions []CreateSubscriptionRequest `json:"subscriptions"`
+}
+
+func (_self *SubscriptionBatchRequest) Validate() error {
+	return validateBatchSize("subscriptions", len(_self.Subscriptions))
+}
+
+type BlockingBatchRequest struct {
+	Blocks []BlockingRequest `json:"blocks"`
+}
+
+func (_self *BlockingBatchRequest) Validate() error {
+	return validateBatchSize("blocks", len(_self.Blocks))
+}
+
+// BatchItemResult is the outcome of one item of a batch, with the status its single request would have answered
+type BatchItemResult struct {
+	Index  int    `json:"index"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Success bool              `json:"success"`
+	Results []BatchItemResult `json:"results"`
+	Created int               `json:"created"`
+}
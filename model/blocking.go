@@ -11,7 +11,9 @@ type BlockingRequest struct {
 	Target    string `json:"target"`
 }
 
-func (_self BlockingRequest) Validate() error {
+func (_self *BlockingRequest) Validate() error {
+	_self.Requestor = utils.NormalizeEmail(_self.Requestor)
+	_self.Target = utils.NormalizeEmail(_self.Target)
 	if _self.Requestor == "" {
 		return errors.New("\"requestor\" is required")
 	}
@@ -41,6 +43,31 @@ func (_self BlockingRequest) Validate() error {
 	return nil
 }
 
+type BlockingListRequest struct {
+	Email string `json:"email"`
+}
+
+func (_self *BlockingListRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	return nil
+}
+
+type BlockingListResponse struct {
+	Success bool     `json:"success"`
+	Targets []string `json:"targets"`
+	Count   int      `json:"count"`
+}
+
 //Service model
 type BlockingServiceInput struct {
 	Requestor int `json:"requestor"`
@@ -0,0 +1,16 @@
+package model
+
+import "errors"
+
+// Errors returned by the repositories when an insert loses a race against another request.
+// Their messages are the same as the ones of the checks done before the insert.
+var (
+	ErrUserExisted         = errors.New("this email address existed")
+	ErrUserNotFound        = errors.New("email does not exist")
+	ErrFriendExisted       = errors.New("friend connection existed")
+	ErrSubscriptionExisted = errors.New("those email address have already subscribed the each other")
+	ErrBlockingExisted     = errors.New("target's email have already been blocked by requestor's email")
+	ErrBlockedEachOther    = errors.New("emails blocked each other")
+	ErrFriendRequestClosed = errors.New("pending friend request does not exist")
+	ErrFriendRequestSent   = errors.New("friend request has already been sent")
+)
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+const (
+	EventFriendAdded     = "friend.added"
+	EventFriendRemoved   = "friend.removed"
+	EventSubscriberAdded = "subscriber.added"
+	EventBlocked         = "blocked"
+	EventUpdateReceived  = "update.received"
+)
+
+// Event is pushed to the stream of Email. ID grows by one for every published event.
+type Event struct {
+	ID    int64
+	Type  string
+	Email string
+	Data  EventData
+}
+
+// EventData is the JSON sent as the data of an event. Email is the other side of the relationship or the sender of the update.
+type EventData struct {
+	Email    string `json:"email"`
+	UpdateID int    `json:"update_id,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+type EventStreamRequest struct {
+	Email string `json:"email"`
+}
+
+func (_self *EventStreamRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	return nil
+}
@@ -2,15 +2,39 @@ package model
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"S3_FriendManagement_ThinhNguyen/utils"
 )
 
+// MaxCommonFriendsEmails caps how many email addresses a common friends query accepts.
+// It is a variable so the cap can be changed at startup.
+var MaxCommonFriendsEmails = 20
+
+const (
+	DefaultFriendSuggestionsLimit = 10
+	MaxFriendSuggestionsLimit     = 100
+	DefaultFriendPathDepth        = 6
+	MaxFriendPathDepth            = 10
+	DefaultFriendListLimit        = 100
+	MaxFriendListLimit            = 1000
+)
+
+const (
+	FriendListSortEmail   = "email"
+	FriendListSortCreated = "created"
+)
+
+// ExpandProfile makes a friend list return the profile of each friend instead of its email
+const ExpandProfile = "profile"
+
 type FriendConnectionRequest struct {
 	Friends []string `json:"friends"`
 }
 
-func (_self FriendConnectionRequest) Validate() error {
+func (_self *FriendConnectionRequest) Validate() error {
+	_self.Friends = utils.NormalizeEmails(_self.Friends)
 	if _self.Friends == nil {
 		return errors.New("\"friends\" is required")
 	}
@@ -41,10 +65,18 @@ func (_self FriendConnectionRequest) Validate() error {
 }
 
 type FriendGetFriendListRequest struct {
-	Email string `json:"email"`
+	Email  string `json:"email"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
+	Sort   string `json:"sort"`
+	Expand string `json:"expand"`
+
+	// DecodedCursor is Cursor decoded by Validate, nil for the first page
+	DecodedCursor *FriendListCursor `json:"-"`
 }
 
-func (_self FriendGetFriendListRequest) Validate() error {
+func (_self *FriendGetFriendListRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
 	if _self.Email == "" {
 		return errors.New("\"Email\" is required")
 	}
@@ -55,48 +87,212 @@ func (_self FriendGetFriendListRequest) Validate() error {
 	if !isValidFirstEmail {
 		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
 	}
+	if _self.Sort != "" && _self.Sort != FriendListSortEmail && _self.Sort != FriendListSortCreated {
+		return fmt.Errorf("\"sort\" must be %q or %q", FriendListSortEmail, FriendListSortCreated)
+	}
+	if _self.Limit < 0 || _self.Limit > MaxFriendListLimit {
+		return fmt.Errorf("\"limit\" must be between 1 and %d", MaxFriendListLimit)
+	}
+	if _self.Expand != "" && _self.Expand != ExpandProfile {
+		return fmt.Errorf("\"expand\" must be %q", ExpandProfile)
+	}
+	if _self.Cursor != "" {
+		cursor, err := DecodeFriendListCursor(_self.Cursor)
+		if err != nil || cursor.Sort != _self.SortOrDefault() {
+			return errors.New("\"cursor\" is not valid")
+		}
+		_self.DecodedCursor = cursor
+	}
 
 	return nil
 }
 
+// SortOrDefault returns the requested sort order, falling back to sorting by email
+func (_self FriendGetFriendListRequest) SortOrDefault() string {
+	if _self.Sort == "" {
+		return FriendListSortEmail
+	}
+	return _self.Sort
+}
+
+// LimitOrDefault returns the requested page size, falling back to DefaultFriendListLimit
+func (_self FriendGetFriendListRequest) LimitOrDefault() int {
+	if _self.Limit == 0 {
+		return DefaultFriendListLimit
+	}
+	return _self.Limit
+}
+
+// FriendListCursor points at the last friend of a page. It is handed to clients as an opaque string.
+type FriendListCursor struct {
+	Sort      string    `json:"s"`
+	Email     string    `json:"e,omitempty"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	ID        int       `json:"i"`
+}
+
+func EncodeFriendListCursor(cursor FriendListCursor) string {
+	return utils.EncodeCursor(cursor)
+}
+
+func DecodeFriendListCursor(value string) (*FriendListCursor, error) {
+	cursor := &FriendListCursor{}
+	if err := utils.DecodeCursor(value, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
 type FriendGetCommonFriendsRequest struct {
 	Friends []string `json:"friends"`
+	Expand  string   `json:"expand"`
 }
 
-func (_self FriendGetCommonFriendsRequest) Validate() error {
+func (_self *FriendGetCommonFriendsRequest) Validate() error {
+	_self.Friends = utils.NormalizeEmails(_self.Friends)
 	if _self.Friends == nil {
 		return errors.New("\"friends\" is required")
 	}
-	if len(_self.Friends) != 2 {
-		return errors.New("needs exactly two email addresses")
+	if len(_self.Friends) < 2 {
+		return errors.New("needs at least two email addresses")
 	}
-	if _self.Friends[0] == _self.Friends[1] {
+	if len(_self.Friends) > MaxCommonFriendsEmails {
+		return fmt.Errorf("needs at most %d email addresses", MaxCommonFriendsEmails)
+	}
+	if _self.Expand != "" && _self.Expand != ExpandProfile {
+		return fmt.Errorf("\"expand\" must be %q", ExpandProfile)
+	}
+
+	existedEmails := make(map[string]bool)
+	for _, email := range _self.Friends {
+		if existedEmails[email] {
+			return errors.New("email addresses must be different")
+		}
+		existedEmails[email] = true
+	}
+
+	for _, email := range _self.Friends {
+		isValidEmail, err := utils.IsValidEmail(email)
+		if err != nil {
+			return fmt.Errorf("validate \"email\" %q format failed", email)
+		}
+		if !isValidEmail {
+			return fmt.Errorf("\"email\" %q is not valid. (ex: \"andy@abc.xyz\")", email)
+		}
+	}
+
+	return nil
+}
+
+type CommonFriendsResponse struct {
+	Success       bool     `json:"success"`
+	Friends       []string `json:"friends"`
+	Count         int      `json:"count"`
+	UnknownEmails []string `json:"unknown_emails"`
+}
+
+type CommonFriendProfilesResponse struct {
+	Success       bool          `json:"success"`
+	Friends       []UserProfile `json:"friends"`
+	Count         int           `json:"count"`
+	UnknownEmails []string      `json:"unknown_emails"`
+}
+
+type FriendSuggestionsRequest struct {
+	Email string `json:"email"`
+	Limit int    `json:"limit"`
+}
+
+func (_self *FriendSuggestionsRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	if _self.Limit < 1 || _self.Limit > MaxFriendSuggestionsLimit {
+		return fmt.Errorf("\"limit\" must be between 1 and %d", MaxFriendSuggestionsLimit)
+	}
+
+	return nil
+}
+
+type FriendSuggestion struct {
+	Email         string `json:"email"`
+	MutualFriends int    `json:"mutual_friends"`
+}
+
+type FriendSuggestionsResponse struct {
+	Success     bool               `json:"success"`
+	Suggestions []FriendSuggestion `json:"suggestions"`
+	Count       int                `json:"count"`
+}
+
+type FriendPathRequest struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	MaxDepth int    `json:"maxDepth"`
+}
+
+func (_self *FriendPathRequest) Validate() error {
+	_self.From = utils.NormalizeEmail(_self.From)
+	_self.To = utils.NormalizeEmail(_self.To)
+	if _self.From == "" {
+		return errors.New("\"from\" is required")
+	}
+	if _self.To == "" {
+		return errors.New("\"to\" is required")
+	}
+	if _self.From == _self.To {
 		return errors.New("two email addresses must be different")
 	}
 
-	isValidFirstEmail, firstErr := utils.IsValidEmail(_self.Friends[0])
-	if firstErr != nil {
-		return errors.New("validate first \"email\" format failed")
+	isValidFromEmail, fromErr := utils.IsValidEmail(_self.From)
+	if fromErr != nil {
+		return errors.New("validate \"from\" format failed")
 	}
-	if !isValidFirstEmail {
-		return errors.New("first \"email\" is not valid. (ex: \"andy@abc.xyz\")")
+	if !isValidFromEmail {
+		return errors.New("\"from\" is not valid. (ex: \"andy@abc.xyz\")")
 	}
 
-	isValidSecondEmail, secondErr := utils.IsValidEmail(_self.Friends[1])
-	if secondErr != nil {
-		return errors.New("validate second \"email\" format failed")
+	isValidToEmail, toErr := utils.IsValidEmail(_self.To)
+	if toErr != nil {
+		return errors.New("validate \"to\" format failed")
 	}
-	if !isValidSecondEmail {
-		return errors.New("second \"email\" is not valid. (ex: \"andy@abc.xyz\")")
+	if !isValidToEmail {
+		return errors.New("\"to\" is not valid. (ex: \"andy@abc.xyz\")")
 	}
 
+	if _self.MaxDepth < 1 || _self.MaxDepth > MaxFriendPathDepth {
+		return fmt.Errorf("\"maxDepth\" must be between 1 and %d", MaxFriendPathDepth)
+	}
 	return nil
 }
 
-type FriendsResponse struct {
+type FriendPathResponse struct {
 	Success bool     `json:"success"`
-	Friends []string `json:"friends"`
-	Count   int      `json:"count"`
+	Found   bool     `json:"found"`
+	Path    []string `json:"path"`
+	Degrees int      `json:"degrees"`
+}
+
+type FriendsResponse struct {
+	Success    bool     `json:"success"`
+	Friends    []string `json:"friends"`
+	Count      int      `json:"count"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+type FriendProfilesResponse struct {
+	Success    bool          `json:"success"`
+	Friends    []UserProfile `json:"friends"`
+	Count      int           `json:"count"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 type GetEmailReceiveUpdateResponse struct {
@@ -109,7 +305,8 @@ type EmailReceiveUpdateRequest struct {
 	Text   string `json:"text"`
 }
 
-func (_self EmailReceiveUpdateRequest) Validate() error {
+func (_self *EmailReceiveUpdateRequest) Validate() error {
+	_self.Sender = utils.NormalizeEmail(_self.Sender)
 	if _self.Sender == "" {
 		return errors.New("\"sender\" is required")
 	}
@@ -132,8 +329,40 @@ type FriendsServiceInput struct {
 	SecondID int `json:"second_id"`
 }
 
+type FriendListServiceInput struct {
+	UserID int
+	Limit  int
+	Sort   string
+	Cursor *FriendListCursor
+}
+
+type FriendListPage struct {
+	Friends    []string
+	NextCursor string
+	Total      int
+}
+
 //Repo model
+type FriendListRepoInput struct {
+	UserID int
+	Limit  int
+	Sort   string
+	Cursor *FriendListCursor
+}
+
+type FriendListItem struct {
+	ID        int
+	Email     string
+	CreatedAt time.Time
+}
+
 type FriendsRepoInput struct {
 	FirstID  int `json:"first_id"`
 	SecondID int `json:"second_id"`
 }
+
+// FriendLink is a friend connection seen from UserID
+type FriendLink struct {
+	UserID   int
+	FriendID int
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+const (
+	FriendRequestStatusPending   = "pending"
+	FriendRequestStatusAccepted  = "accepted"
+	FriendRequestStatusRejected  = "rejected"
+	FriendRequestStatusCancelled = "cancelled"
+)
+
+type FriendRequestRequest struct {
+	Requestor string `json:"requestor"`
+	Target    string `json:"target"`
+}
+
+func (_self *FriendRequestRequest) Validate() error {
+	_self.Requestor = utils.NormalizeEmail(_self.Requestor)
+	_self.Target = utils.NormalizeEmail(_self.Target)
+	if _self.Requestor == "" {
+		return errors.New("\"requestor\" is required")
+	}
+	if _self.Target == "" {
+		return errors.New("\"target\" is required")
+	}
+
+	if _self.Target == _self.Requestor {
+		return errors.New("two email addresses must be different")
+	}
+
+	isValidFirstEmail, firstErr := utils.IsValidEmail(_self.Requestor)
+	if firstErr != nil {
+		return errors.New("validate \"requestor\" format failed")
+	}
+	if !isValidFirstEmail {
+		return errors.New("\"requestor\" is not valid. (ex: \"andy@abc.xyz\")")
+	}
+
+	isValidSecondEmail, secondErr := utils.IsValidEmail(_self.Target)
+	if secondErr != nil {
+		return errors.New("validate \"target\" format failed")
+	}
+	if !isValidSecondEmail {
+		return errors.New("\"target\" is not valid. (ex: \"andy@abc.xyz\")")
+	}
+
+	return nil
+}
+
+type FriendRequestListRequest struct {
+	Email string `json:"email"`
+}
+
+func (_self *FriendRequestListRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+
+	return nil
+}
+
+type FriendRequestListResponse struct {
+	Success bool     `json:"success"`
+	Emails  []string `json:"emails"`
+	Count   int      `json:"count"`
+}
+
+//Service model
+type FriendRequestServiceInput struct {
+	Requestor int `json:"requestor"`
+	Target    int `json:"target"`
+}
+
+//Repo model
+type FriendRequestRepoInput struct {
+	Requestor int    `json:"requestor"`
+	Target    int    `json:"target"`
+	Status    string `json:"status"`
+}
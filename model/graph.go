@@ -0,0 +1,156 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+// Formats of a graph export
+const (
+	GraphFormatJSON    = "json"
+	GraphFormatCSV     = "csv"
+	GraphFormatGraphML = "graphml"
+)
+
+// MaxEmailLength is the size of useremails.email
+const MaxEmailLength = 100
+
+// ErrGraphDatabaseNotEmpty is returned by a graph import into a database which already has users
+var ErrGraphDatabaseNotEmpty = errors.New("the database must be empty to import a graph")
+
+type GraphExportRequest struct {
+	Format string `json:"format"`
+}
+
+func (_self *GraphExportRequest) Validate() error {
+	if _self.Format == "" {
+		_self.Format = GraphFormatJSON
+	}
+	if _self.Format != GraphFormatJSON && _self.Format != GraphFormatCSV && _self.Format != GraphFormatGraphML {
+		return fmt.Errorf("\"format\" must be %q, %q or %q", GraphFormatJSON, GraphFormatCSV, GraphFormatGraphML)
+	}
+	return nil
+}
+
+// Graph is a snapshot of every user and relationship. Edges refer to users by the ID they have in the snapshot,
+// an import gives the users new IDs and remaps the edges.
+type Graph struct {
+	ExportedAt    time.Time   `json:"exported_at"`
+	Users         []GraphUser `json:"users"`
+	Friends       []GraphEdge `json:"friends"`
+	Subscriptions []GraphEdge `json:"subscriptions"`
+	Blocks        []GraphEdge `json:"blocks"`
+}
+
+type GraphUser struct {
+	ID          int       `json:"id"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+	Bio         string    `json:"bio"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GraphEdge goes from Source to Target, a friend connection has no direction and also keeps its creation time
+type GraphEdge struct {
+	Source    int        `json:"source"`
+	Target    int        `json:"target"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Relations of a graph, in the order they are exported
+const (
+	GraphRelationFriends       = "friends"
+	GraphRelationSubscriptions = "subscriptions"
+	GraphRelationBlocks        = "blocks"
+)
+
+// GraphWriter receives an export one row at a time, so the graph is never held in memory as a whole:
+// Begin, every user, then for each relation its name and its edges, and End
+type GraphWriter interface {
+	Begin(exportedAt time.Time) error
+	User(user GraphUser) error
+	Relation(name string) error
+	Edge(edge GraphEdge) error
+	End() error
+}
+
+// Validate checks the graph against the schema before an import: valid and unique normalized emails,
+// profile limits and statuses, edges between two different known users, and one edge per pair of users.
+// It normalizes the emails and gives an empty status the default one.
+func (_self *Graph) Validate() error {
+	IDs := make(map[int]bool, len(_self.Users))
+	emails := make(map[string]int, len(_self.Users))
+	for i := range _self.Users {
+		user := &_self.Users[i]
+		if IDs[user.ID] {
+			return fmt.Errorf("users[%d]: id %d is repeated", i, user.ID)
+		}
+		IDs[user.ID] = true
+
+		user.Email = utils.NormalizeEmail(user.Email)
+		isValid, err := utils.IsValidEmail(user.Email)
+		if err != nil || !isValid || len(user.Email) > MaxEmailLength {
+			return fmt.Errorf("users[%d]: email %q is not valid", i, user.Email)
+		}
+		if otherID, ok := emails[user.Email]; ok {
+			return fmt.Errorf("users[%d]: email %q is already the one of user %d", i, user.Email, otherID)
+		}
+		emails[user.Email] = user.ID
+
+		if user.Status == "" {
+			user.Status = UserStatusActive
+		}
+		if user.Status != UserStatusActive && user.Status != UserStatusAway && user.Status != UserStatusBusy {
+			return fmt.Errorf("users[%d]: status must be %q, %q or %q", i, UserStatusActive, UserStatusAway, UserStatusBusy)
+		}
+		if utf8.RuneCountInString(user.DisplayName) > MaxDisplayNameLength {
+			return fmt.Errorf("users[%d]: display name must be at most %d characters", i, MaxDisplayNameLength)
+		}
+		if len(user.AvatarURL) > MaxAvatarURLLength {
+			return fmt.Errorf("users[%d]: avatar url must be at most %d characters", i, MaxAvatarURLLength)
+		}
+		if utf8.RuneCountInString(user.Bio) > MaxBioLength {
+			return fmt.Errorf("users[%d]: bio must be at most %d characters", i, MaxBioLength)
+		}
+	}
+
+	if err := validateGraphEdges("friends", _self.Friends, IDs, true); err != nil {
+		return err
+	}
+	if err := validateGraphEdges("subscriptions", _self.Subscriptions, IDs, false); err != nil {
+		return err
+	}
+	return validateGraphEdges("blocks", _self.Blocks, IDs, false)
+}
+
+// validateGraphEdges checks the edges of one relation, unordered tells an edge and its reverse are the same
+func validateGraphEdges(name string, edges []GraphEdge, IDs map[int]bool, unordered bool) error {
+	pairs := make(map[[2]int]bool, len(edges))
+	for i, edge := range edges {
+		if !IDs[edge.Source] {
+			return fmt.Errorf("%s[%d]: user %d does not exist", name, i, edge.Source)
+		}
+		if !IDs[edge.Target] {
+			return fmt.Errorf("%s[%d]: user %d does not exist", name, i, edge.Target)
+		}
+		if edge.Source == edge.Target {
+			return fmt.Errorf("%s[%d]: user %d is linked to itself", name, i, edge.Source)
+		}
+
+		pair := [2]int{edge.Source, edge.Target}
+		if unordered && pair[0] > pair[1] {
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		if pairs[pair] {
+			return fmt.Errorf("%s[%d]: users %d and %d are already linked", name, i, edge.Source, edge.Target)
+		}
+		pairs[pair] = true
+	}
+	return nil
+}
@@ -11,7 +11,9 @@ type CreateSubscriptionRequest struct {
 	Target    string `json:"target"`
 }
 
-func (_self CreateSubscriptionRequest) Validate() error {
+func (_self *CreateSubscriptionRequest) Validate() error {
+	_self.Requestor = utils.NormalizeEmail(_self.Requestor)
+	_self.Target = utils.NormalizeEmail(_self.Target)
 	if _self.Requestor == "" {
 		return errors.New("\"requestor\" is required")
 	}
@@ -42,6 +44,31 @@ func (_self CreateSubscriptionRequest) Validate() error {
 	return nil
 }
 
+type SubscriptionListRequest struct {
+	Email string `json:"email"`
+}
+
+func (_self *SubscriptionListRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	return nil
+}
+
+type SubscriptionListResponse struct {
+	Success bool     `json:"success"`
+	Emails  []string `json:"emails"`
+	Count   int      `json:"count"`
+}
+
 //Service
 type SubscriptionServiceInput struct {
 	Requestor int `json:"requestor"`
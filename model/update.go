@@ -0,0 +1,166 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+const (
+	DefaultFeedLimit = 20
+	MaxFeedLimit     = 100
+)
+
+type UpdateRequest struct {
+	Sender string `json:"sender"`
+	Text   string `json:"text"`
+}
+
+func (_self *UpdateRequest) Validate() error {
+	_self.Sender = utils.NormalizeEmail(_self.Sender)
+	if _self.Sender == "" {
+		return errors.New("\"sender\" is required")
+	}
+	if _self.Text == "" {
+		return errors.New("\"text\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Sender)
+	if err != nil {
+		return errors.New("validate \"sender\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"sender\" is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	return nil
+}
+
+type CreateUpdateResponse struct {
+	Success    bool     `json:"success"`
+	UpdateID   int      `json:"update_id"`
+	Recipients []string `json:"recipients"`
+}
+
+type FeedRequest struct {
+	Email  string `json:"email"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
+
+	// DecodedCursor is Cursor decoded by Validate, nil for the first page
+	DecodedCursor *FeedCursor `json:"-"`
+}
+
+func (_self *FeedRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	if _self.Limit < 1 || _self.Limit > MaxFeedLimit {
+		return fmt.Errorf("\"limit\" must be between 1 and %d", MaxFeedLimit)
+	}
+	if _self.Cursor != "" {
+		cursor, err := DecodeFeedCursor(_self.Cursor)
+		if err != nil {
+			return errors.New("\"cursor\" is not valid")
+		}
+		_self.DecodedCursor = cursor
+	}
+	return nil
+}
+
+// FeedCursor points at the last update of a feed page
+type FeedCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        int       `json:"i"`
+}
+
+func EncodeFeedCursor(cursor FeedCursor) string {
+	return utils.EncodeCursor(cursor)
+}
+
+func DecodeFeedCursor(value string) (*FeedCursor, error) {
+	cursor := &FeedCursor{}
+	if err := utils.DecodeCursor(value, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+type FeedItem struct {
+	UpdateID  int       `json:"update_id"`
+	Sender    string    `json:"sender"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	Read      bool      `json:"read"`
+}
+
+type FeedResponse struct {
+	Success    bool       `json:"success"`
+	Updates    []FeedItem `json:"updates"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type FeedReadRequest struct {
+	Email     string `json:"email"`
+	UpdateIDs []int  `json:"update_ids"`
+}
+
+func (_self *FeedReadRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	if len(_self.UpdateIDs) == 0 {
+		return errors.New("\"update_ids\" is required")
+	}
+	isValidEmail, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValidEmail {
+		return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	return nil
+}
+
+//Service model
+type UpdateServiceInput struct {
+	SenderID   int
+	Sender     string
+	Text       string
+	Recipients []string
+}
+
+type FeedServiceInput struct {
+	UserID int
+	Limit  int
+	Cursor *FeedCursor
+}
+
+type FeedPage struct {
+	Updates    []FeedItem
+	NextCursor string
+}
+
+//Repo model
+type UpdateRepoInput struct {
+	SenderID   int
+	Sender     string
+	Text       string
+	Mentions   []string
+	Recipients []string
+}
+
+type FeedRepoInput struct {
+	UserID int
+	Limit  int
+	Cursor *FeedCursor
+}
@@ -2,20 +2,50 @@ package model
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"S3_FriendManagement_ThinhNguyen/utils"
 )
 
+const (
+	UserStatusActive = "active"
+	UserStatusAway   = "away"
+	UserStatusBusy   = "busy"
+)
+
+const (
+	MaxDisplayNameLength   = 100
+	MaxAvatarURLLength     = 2048
+	MaxBioLength           = 500
+	DefaultUserSearchLimit = 20
+	MaxUserSearchLimit     = 100
+)
+
 type User struct {
 	Email string
 }
 
+// UserProfile is the public profile of a user, returned by /user and by the friend lists with "expand=profile"
+type UserProfile struct {
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+	Bio         string    `json:"bio"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 //model handler
 type UserRequest struct {
 	Email string `json:"email"`
 }
 
-func (_self UserRequest) Validate() error {
+func (_self *UserRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
 	if _self.Email == "" {
 		return errors.New("\"email\" is required")
 	}
@@ -30,6 +60,171 @@ func (_self UserRequest) Validate() error {
 	return nil
 }
 
+// UpdateUserProfileRequest changes the profile fields which are given, the others keep their value
+type UpdateUserProfileRequest struct {
+	Email       string  `json:"-"`
+	DisplayName *string `json:"display_name"`
+	AvatarURL   *string `json:"avatar_url"`
+	Bio         *string `json:"bio"`
+	Status      *string `json:"status"`
+}
+
+func (_self *UpdateUserProfileRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.Email == "" {
+		return errors.New("\"email\" is required")
+	}
+	isValid, err := utils.IsValidEmail(_self.Email)
+	if err != nil {
+		return errors.New("validate \"email\" format failed")
+	}
+	if !isValid {
+		return errors.New("\"email\"'s format is not valid. (ex: \"andy@abc.xyz\")")
+	}
+
+	if _self.DisplayName == nil && _self.AvatarURL == nil && _self.Bio == nil && _self.Status == nil {
+		return errors.New("needs at least one of \"display_name\", \"avatar_url\", \"bio\" or \"status\"")
+	}
+	if _self.DisplayName != nil {
+		*_self.DisplayName = strings.TrimSpace(*_self.DisplayName)
+		if utf8.RuneCountInString(*_self.DisplayName) > MaxDisplayNameLength {
+			return fmt.Errorf("\"display_name\" must be at most %d characters", MaxDisplayNameLength)
+		}
+	}
+	if _self.AvatarURL != nil && *_self.AvatarURL != "" {
+		avatarURL, err := url.Parse(*_self.AvatarURL)
+		if err != nil || (avatarURL.Scheme != "http" && avatarURL.Scheme != "https") || avatarURL.Host == "" ||
+			len(*_self.AvatarURL) > MaxAvatarURLLength {
+			return errors.New("\"avatar_url\" is not valid. (ex: \"https://example.com/avatar.png\")")
+		}
+	}
+	if _self.Bio != nil && utf8.RuneCountInString(*_self.Bio) > MaxBioLength {
+		return fmt.Errorf("\"bio\" must be at most %d characters", MaxBioLength)
+	}
+	if _self.Status != nil && *_self.Status != UserStatusActive && *_self.Status != UserStatusAway && *_self.Status != UserStatusBusy {
+		return fmt.Errorf("\"status\" must be %q, %q or %q", UserStatusActive, UserStatusAway, UserStatusBusy)
+	}
+	return nil
+}
+
+type ChangeEmailRequest struct {
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+}
+
+func (_self *ChangeEmailRequest) Validate() error {
+	_self.OldEmail = utils.NormalizeEmail(_self.OldEmail)
+	_self.NewEmail = utils.NormalizeEmail(_self.NewEmail)
+	if _self.OldEmail == "" {
+		return errors.New("\"old_email\" is required")
+	}
+	if _self.NewEmail == "" {
+		return errors.New("\"new_email\" is required")
+	}
+	if _self.OldEmail == _self.NewEmail {
+		return errors.New("two email addresses must be different")
+	}
+
+	isValidOldEmail, oldErr := utils.IsValidEmail(_self.OldEmail)
+	if oldErr != nil {
+		return errors.New("validate \"old_email\" format failed")
+	}
+	if !isValidOldEmail {
+		return errors.New("\"old_email\" is not valid. (ex: \"andy@abc.xyz\")")
+	}
+
+	isValidNewEmail, newErr := utils.IsValidEmail(_self.NewEmail)
+	if newErr != nil {
+		return errors.New("validate \"new_email\" format failed")
+	}
+	if !isValidNewEmail {
+		return errors.New("\"new_email\" is not valid. (ex: \"andy@abc.xyz\")")
+	}
+	return nil
+}
+
+type UserSearchRequest struct {
+	Search string `json:"search"`
+	Limit  int    `json:"limit"`
+}
+
+func (_self *UserSearchRequest) Validate() error {
+	_self.Search = strings.ToLower(strings.TrimSpace(_self.Search))
+	if _self.Search == "" {
+		return errors.New("\"search\" is required")
+	}
+	if _self.Limit < 1 || _self.Limit > MaxUserSearchLimit {
+		return fmt.Errorf("\"limit\" must be between 1 and %d", MaxUserSearchLimit)
+	}
+	return nil
+}
+
+type UserProfileResponse struct {
+	Success bool        `json:"success"`
+	User    UserProfile `json:"user"`
+}
+
+type UserSearchResponse struct {
+	Success bool          `json:"success"`
+	Users   []UserProfile `json:"users"`
+	Count   int           `json:"count"`
+}
+
+// UserExport is the archive of everything stored about a user, returned by /user/{email}/export
+type UserExport struct {
+	ExportedAt      time.Time                `json:"exported_at"`
+	Profile         UserProfile              `json:"profile"`
+	Friends         []ExportedFriend         `json:"friends"`
+	Subscriptions   []string                 `json:"subscriptions"`
+	Subscribers     []string                 `json:"subscribers"`
+	Blocking        []string                 `json:"blocking"`
+	FriendRequests  []ExportedFriendRequest  `json:"friend_requests"`
+	Updates         []ExportedUpdate         `json:"updates"`
+	ReceivedUpdates []ExportedReceivedUpdate `json:"received_updates"`
+	Webhooks        []ExportedWebhook        `json:"webhooks"`
+	PreviousEmails  []ExportedEmailChange    `json:"previous_emails"`
+}
+
+type ExportedEmailChange struct {
+	Email     string    `json:"email"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type ExportedFriend struct {
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportedFriendRequest struct {
+	Requestor string    `json:"requestor"`
+	Target    string    `json:"target"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExportedUpdate struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	Mentions  []string  `json:"mentions"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportedReceivedUpdate struct {
+	ID        int        `json:"id"`
+	Sender    string     `json:"sender"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+// ExportedWebhook leaves the secret out of the archive
+type ExportedWebhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type SuccessResponse struct {
 	Success bool `json:"Success"`
 }
@@ -39,7 +234,33 @@ type UserServiceInput struct {
 	Email string `json:"email"`
 }
 
+type ChangeEmailServiceInput struct {
+	OldEmail string
+	NewEmail string
+}
+
+type UserProfileServiceInput struct {
+	Email       string
+	DisplayName *string
+	AvatarURL   *string
+	Bio         *string
+	Status      *string
+}
+
 //model repo
 type UserRepoInput struct {
 	Email string `json:"email"`
 }
+
+type ChangeEmailRepoInput struct {
+	OldEmail string
+	NewEmail string
+}
+
+type UserProfileRepoInput struct {
+	Email       string
+	DisplayName *string
+	AvatarURL   *string
+	Bio         *string
+	Status      *string
+}
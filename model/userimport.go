@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"mime"
+)
+
+// Content types accepted by the user import, one email address per line
+const (
+	UserImportCSV    = "text/csv"
+	UserImportNDJSON = "application/x-ndjson"
+)
+
+// What the user import does with the email addresses which already exist
+const (
+	UserImportSkipExisting   = "skip"
+	UserImportReportExisting = "report"
+)
+
+const (
+	// MaxUserImportErrors caps how many line errors an import summary lists, the counts go on
+	MaxUserImportErrors = 1000
+	// MaxUserImportLineLength stops an import on a line longer than this many bytes
+	MaxUserImportLineLength = 64 * 1024
+)
+
+// ErrUserImportContentType is returned for a stream which is neither CSV nor NDJSON
+var ErrUserImportContentType = errors.New("content type must be \"text/csv\" or \"application/x-ndjson\"")
+
+// UserImportChunkSize is how many email addresses an import inserts with one statement.
+// It is a variable so the size can be changed at startup.
+var UserImportChunkSize = 1000
+
+type UserImportRequest struct {
+	ContentType string
+	Existing    string
+}
+
+func (_self *UserImportRequest) Validate() error {
+	mediaType, _, err := mime.ParseMediaType(_self.ContentType)
+	if err != nil || (mediaType != UserImportCSV && mediaType != UserImportNDJSON) {
+		return ErrUserImportContentType
+	}
+	_self.ContentType = mediaType
+
+	if _self.Existing == "" {
+		_self.Existing = UserImportSkipExisting
+	}
+	if _self.Existing != UserImportSkipExisting && _self.Existing != UserImportReportExisting {
+		return errors.New("\"existing\" must be \"skip\" or \"report\"")
+	}
+	return nil
+}
+
+// UserImportLineError tells why the email address of a line was not imported
+type UserImportLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// UserImportResponse sums up an import. Skipped counts the email addresses which already existed,
+// including the ones repeated in the file, Errors lists the first MaxUserImportErrors line errors.
+type UserImportResponse struct {
+	Success bool                  `json:"success"`
+	Created int                   `json:"created"`
+	Skipped int                   `json:"skipped"`
+	Invalid int                   `json:"invalid"`
+	Errors  []UserImportLineError `json:"errors"`
+}
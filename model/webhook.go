@@ -0,0 +1,152 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+const (
+	WebhookOutboxStatusPending   = "pending"
+	WebhookOutboxStatusSending   = "sending"
+	WebhookOutboxStatusDelivered = "delivered"
+	WebhookOutboxStatusDead      = "dead"
+)
+
+const WebhookEventUpdateCreated = "update.created"
+
+type WebhookRequest struct {
+	Email  string `json:"email"`
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+func (_self *WebhookRequest) Validate() error {
+	_self.Email = utils.NormalizeEmail(_self.Email)
+	if _self.URL == "" {
+		return errors.New("\"url\" is required")
+	}
+	if _self.Secret == "" {
+		return errors.New("\"secret\" is required")
+	}
+	webhookURL, err := url.Parse(_self.URL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return errors.New("\"url\" is not valid. (ex: \"https://example.com/hook\")")
+	}
+	if _self.Email != "" {
+		isValidEmail, err := utils.IsValidEmail(_self.Email)
+		if err != nil {
+			return errors.New("validate \"email\" format failed")
+		}
+		if !isValidEmail {
+			return errors.New("\"email\" format is not valid. (ex: \"andy@abc.xyz\")")
+		}
+	}
+	return nil
+}
+
+type CreateWebhookResponse struct {
+	Success bool `json:"success"`
+	ID      int  `json:"id"`
+}
+
+// WebhookPayload is the JSON body POSTed to a webhook when an update is delivered
+type WebhookPayload struct {
+	Event      string   `json:"event"`
+	UpdateID   int      `json:"update_id"`
+	Sender     string   `json:"sender"`
+	Text       string   `json:"text"`
+	Recipients []string `json:"recipients"`
+}
+
+type WebhookDeadLetter struct {
+	ID        int             `json:"id"`
+	WebhookID int             `json:"webhook_id"`
+	URL       string          `json:"url"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+type WebhookDeadLettersResponse struct {
+	Success     bool                `json:"success"`
+	DeadLetters []WebhookDeadLetter `json:"dead_letters"`
+	Count       int                 `json:"count"`
+}
+
+//Service model
+type WebhookServiceInput struct {
+	UserID int
+	URL    string
+	Secret string
+}
+
+type WebhookUpdateInput struct {
+	UpdateID   int
+	Sender     string
+	Text       string
+	Recipients []string
+}
+
+//Repo model
+type WebhookRepoInput struct {
+	UserID int
+	URL    string
+	Secret string
+}
+
+// Webhook is a registered URL. UserEmail is empty for a global webhook.
+type Webhook struct {
+	ID        int
+	UserEmail string
+	URL       string
+	Secret    string
+}
+
+type WebhookOutboxEntry struct {
+	ID        int
+	WebhookID int
+	URL       string
+	Secret    string
+	Payload   string
+	Attempts  int
+}
+
+// NewWebhookOutboxEntries builds one outbox entry per webhook interested in the update.
+// Global webhooks get every recipient, a user's webhook only gets that user.
+func NewWebhookOutboxEntries(update WebhookUpdateInput, webhooks []Webhook) ([]WebhookOutboxEntry, error) {
+	entries := make([]WebhookOutboxEntry, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		recipients := update.Recipients
+		if webhook.UserEmail != "" {
+			recipients = []string{webhook.UserEmail}
+		}
+		payload, err := json.Marshal(WebhookPayload{
+			Event:      WebhookEventUpdateCreated,
+			UpdateID:   update.UpdateID,
+			Sender:     update.Sender,
+			Text:       update.Text,
+			Recipients: recipients,
+		})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, WebhookOutboxEntry{
+			WebhookID: webhook.ID,
+			Payload:   string(payload),
+		})
+	}
+	return entries, nil
+}
+
+type WebhookOutboxFailure struct {
+	ID            int
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	Dead          bool
+}
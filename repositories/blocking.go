@@ -1,30 +1,76 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 
 	"S3_FriendManagement_ThinhNguyen/model"
 )
 
 type IBlockingRepo interface {
-	CreateBlocking(input *model.BlockingRepoInput) error
-	IsExistedBlocking(requestorID int, targetID int) (bool, error)
+	CreateBlocking(ctx context.Context, input *model.BlockingRepoInput) error
+	CreateBlockings(ctx context.Context, inputs []model.BlockingRepoInput) ([]error, error)
+	DeleteBlocking(ctx context.Context, input *model.BlockingRepoInput) error
+	IsExistedBlocking(ctx context.Context, requestorID int, targetID int) (bool, error)
+	GetBlockingListByID(ctx context.Context, userID int) ([]int, error)
 }
 
 type BlockingRepo struct {
 	Db *sql.DB
 }
 
-func (_self BlockingRepo) CreateBlocking(blocking *model.BlockingRepoInput) error {
+// CreateBlocking inserts the block while holding the users, so no friend connection or subscription
+// can be created between the two in the meantime. It returns model.ErrBlockingExisted on a duplicate block.
+func (_self BlockingRepo) CreateBlocking(ctx context.Context, blocking *model.BlockingRepoInput) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockUserPair(ctx, tx, blocking.Requestor, blocking.Target, lockForBlocking); err != nil {
+		return err
+	}
+
 	query := `insert into blocks(requestorid, targetid) VALUES ($1, $2)`
-	_, err := _self.Db.Exec(query, blocking.Requestor, blocking.Target)
+	if _, err := tx.ExecContext(ctx, query, blocking.Requestor, blocking.Target); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrBlockingExisted
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+var blockingBatch = relationshipBatch{
+	insertQuery: `insert into blocks(requestorid, targetid)
+				  select * from unnest($1::int8[], $2::int8[])
+				  on conflict do nothing
+				  returning requestorid, targetid`,
+	lockMode:   lockForBlocking,
+	existedErr: model.ErrBlockingExisted,
+}
+
+// CreateBlockings inserts many blocks in one transaction while holding all their users.
+// It returns the outcome of each block: nil or model.ErrBlockingExisted.
+func (_self BlockingRepo) CreateBlockings(ctx context.Context, inputs []model.BlockingRepoInput) ([]error, error) {
+	pairs := make([][2]int, len(inputs))
+	for i, input := range inputs {
+		pairs[i] = [2]int{input.Requestor, input.Target}
+	}
+	return blockingBatch.create(ctx, _self.Db, pairs)
+}
+
+func (_self BlockingRepo) DeleteBlocking(ctx context.Context, blocking *model.BlockingRepoInput) error {
+	query := `delete from blocks where requestorid = $1 and targetid = $2`
+	_, err := _self.Db.ExecContext(ctx, query, blocking.Requestor, blocking.Target)
 	return err
 }
 
-func (_self BlockingRepo) IsExistedBlocking(requestorID int, targetID int) (bool, error) {
+func (_self BlockingRepo) IsExistedBlocking(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	query := `select exists(select true from blocks WHERE requestorID=$1 AND targetid=$2)`
 	var exist bool
-	err := _self.Db.QueryRow(query, requestorID, targetID).Scan(&exist)
+	err := _self.Db.QueryRowContext(ctx, query, requestorID, targetID).Scan(&exist)
 	if err != nil {
 		return true, err
 	}
@@ -33,3 +79,23 @@ func (_self BlockingRepo) IsExistedBlocking(requestorID int, targetID int) (bool
 	}
 	return false, nil
 }
+
+func (_self BlockingRepo) GetBlockingListByID(ctx context.Context, userID int) ([]int, error) {
+	query := `select targetid from blocks where requestorid = $1 order by id`
+
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blockingListID := make([]int, 0)
+	for rows.Next() {
+		var targetID int
+		if err := rows.Scan(&targetID); err != nil {
+			return nil, err
+		}
+		blockingListID = append(blockingListID, targetID)
+	}
+	return blockingListID, nil
+}
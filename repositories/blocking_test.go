@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -28,6 +29,16 @@ func TestBlockingRepo_CreateBlocking(t *testing.T) {
 			preparePath: "",
 			mockDB:      testhelpers.ConnectDBFailed(),
 		},
+		{
+			name: "Blocking existed",
+			input: &model.BlockingRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: model.ErrBlockingExisted,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
 		{
 			name: "Create success",
 			input: &model.BlockingRepoInput{
@@ -50,7 +61,7 @@ func TestBlockingRepo_CreateBlocking(t *testing.T) {
 			}
 
 			// When
-			err := blockingRepo.CreateBlocking(testCase.input)
+			err := blockingRepo.CreateBlocking(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -62,6 +73,27 @@ func TestBlockingRepo_CreateBlocking(t *testing.T) {
 	}
 }
 
+func TestBlockingRepo_CreateBlockings(t *testing.T) {
+	// Given
+	db := testhelpers.ConnectDB()
+	testhelpers.PrepareDBForTest(db, "../testhelpers/preparedata/datafortest")
+
+	blockingRepo := BlockingRepo{
+		Db: db,
+	}
+
+	// When
+	results, err := blockingRepo.CreateBlockings(context.Background(), []model.BlockingRepoInput{
+		{Requestor: 1, Target: 2},
+		{Requestor: 2, Target: 1},
+		{Requestor: 2, Target: 1},
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, []error{model.ErrBlockingExisted, nil, model.ErrBlockingExisted}, results)
+}
+
 func TestBlockingRepo_IsExistedBlocking(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -107,7 +139,117 @@ func TestBlockingRepo_IsExistedBlocking(t *testing.T) {
 			}
 
 			// When
-			result, err := blockingRepo.IsExistedBlocking(testCase.input[0], testCase.input[1])
+			result, err := blockingRepo.IsExistedBlocking(context.Background(), testCase.input[0], testCase.input[1])
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestBlockingRepo_DeleteBlocking(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		input                  *model.BlockingRepoInput
+		expectedBlockingListID []int
+		expectedErr            error
+		preparePath            string
+		mockDB                 *sql.DB
+	}{
+		{
+			name: "Delete failed with error",
+			input: &model.BlockingRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDB:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Delete success and target is no longer filtered from friend list",
+			input: &model.BlockingRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedBlockingListID: []int{},
+			expectedErr:            nil,
+			preparePath:            "../testhelpers/preparedata/datafortest",
+			mockDB:                 testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDB, testCase.preparePath)
+
+			blockingRepo := BlockingRepo{
+				Db: testCase.mockDB,
+			}
+
+			// When
+			err := blockingRepo.DeleteBlocking(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				friendRepo := FriendRepo{
+					Db: testCase.mockDB,
+				}
+				blockingListID, err := friendRepo.GetBlockingListByID(context.Background(), testCase.input.Requestor)
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedBlockingListID, blockingListID)
+			}
+		})
+	}
+}
+
+func TestBlockingRepo_GetBlockingListByID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          int
+		expectedResult []int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get blocking list failed with error",
+			input:          1,
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Get blocking list success",
+			input:          1,
+			expectedResult: []int{2},
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			blockingRepo := BlockingRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := blockingRepo.GetBlockingListByID(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
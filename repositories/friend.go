@@ -2,35 +2,117 @@ package repositories
 
 import (
 	"S3_FriendManagement_ThinhNguyen/model"
+	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+// friendsNoBlockQuery selects the friends of $1 which neither block nor are blocked by $1
+const friendsNoBlockQuery = `from friends f
+			  join useremails ue on ue.id = case when f.firstid = $1 then f.secondid else f.firstid end
+			  where (f.firstid = $1 or f.secondid = $1)
+			    and not exists(
+			    	select true from blocks b
+			    	where (b.requestorid = $1 and b.targetid = ue.id)
+			    	   or (b.requestorid = ue.id and b.targetid = $1)
+			    )`
+
 type IFriendRepo interface {
-	CreateFriend(*model.FriendsRepoInput) error
-	GetFriendListByID(int) ([]int, error)
-	GetBlockedListByID(int) ([]int, error)
-	GetBlockingListByID(int) ([]int, error)
-	IsBlockedByOtherEmail(int, int) (bool, error)
-	IsExistedFriend(int, int) (bool, error)
-	GetSubscriberList(int) ([]int, error)
-	GetEmailsFriendOrSubscribedWithNoBlocked(int) ([]int, error)
+	CreateFriend(context.Context, *model.FriendsRepoInput) error
+	CreateFriends(context.Context, []model.FriendsRepoInput) ([]error, error)
+	DeleteFriend(context.Context, *model.FriendsRepoInput) error
+	GetFriendListByID(context.Context, int) ([]int, error)
+	GetFriendLinksNoBlock(context.Context, []int) ([]model.FriendLink, error)
+	GetFriendSuggestions(context.Context, int, int) ([]model.FriendSuggestion, error)
+	GetFriendPageByID(context.Context, *model.FriendListRepoInput) ([]model.FriendListItem, error)
+	CountFriendsByID(context.Context, int) (int, error)
+	GetBlockedListByID(context.Context, int) ([]int, error)
+	GetBlockingListByID(context.Context, int) ([]int, error)
+	IsBlockedByOtherEmail(context.Context, int, int) (bool, error)
+	IsExistedFriend(context.Context, int, int) (bool, error)
+	GetEmailsFriendOrSubscribedWithNoBlocked(context.Context, int) ([]int, error)
 }
 
 type FriendRepo struct {
 	Db *sql.DB
 }
 
-func (_self FriendRepo) CreateFriend(friendsRepoInput *model.FriendsRepoInput) error {
+// CreateFriend checks blocking and inserts the friend connection in one transaction.
+// It returns model.ErrBlockedEachOther or model.ErrFriendExisted when another request got there first.
+func (_self FriendRepo) CreateFriend(ctx context.Context, friendsRepoInput *model.FriendsRepoInput) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createFriendTx(ctx, tx, friendsRepoInput.FirstID, friendsRepoInput.SecondID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// createFriendTx inserts a friend connection inside tx unless the users block each other
+func createFriendTx(ctx context.Context, tx *sql.Tx, firstID int, secondID int) error {
+	if err := lockUserPair(ctx, tx, firstID, secondID, lockForRelationship); err != nil {
+		return err
+	}
+
+	blocked, err := isBlockedEachOther(ctx, tx, firstID, secondID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return model.ErrBlockedEachOther
+	}
+
 	query := `insert into friends(firstid, secondid) values ($1, $2)`
-	_, err := _self.Db.Exec(query, friendsRepoInput.FirstID, friendsRepoInput.SecondID)
+	if _, err := tx.ExecContext(ctx, query, firstID, secondID); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrFriendExisted
+		}
+		return err
+	}
+	return nil
+}
+
+// friendBatch stores friend connections, which have no direction
+var friendBatch = relationshipBatch{
+	insertQuery: `insert into friends(firstid, secondid)
+				  select * from unnest($1::int8[], $2::int8[])
+				  on conflict do nothing
+				  returning firstid, secondid`,
+	lockMode:     lockForRelationship,
+	checkBlocked: true,
+	unordered:    true,
+	existedErr:   model.ErrFriendExisted,
+}
+
+// CreateFriends inserts many friend connections in one transaction, checking blocking for all of them with one query.
+// It returns the outcome of each connection: nil, model.ErrBlockedEachOther or model.ErrFriendExisted.
+func (_self FriendRepo) CreateFriends(ctx context.Context, inputs []model.FriendsRepoInput) ([]error, error) {
+	pairs := make([][2]int, len(inputs))
+	for i, input := range inputs {
+		pairs[i] = [2]int{input.FirstID, input.SecondID}
+	}
+	return friendBatch.create(ctx, _self.Db, pairs)
+}
+
+func (_self FriendRepo) DeleteFriend(ctx context.Context, friendsRepoInput *model.FriendsRepoInput) error {
+	query := `delete from friends
+			  where (firstid = $1 and secondid = $2)
+			     or (firstid = $2 and secondid = $1)`
+	_, err := _self.Db.ExecContext(ctx, query, friendsRepoInput.FirstID, friendsRepoInput.SecondID)
 	return err
 }
 
-func (_self FriendRepo) GetFriendListByID(userID int) ([]int, error) {
+func (_self FriendRepo) GetFriendListByID(ctx context.Context, userID int) ([]int, error) {
 	query := `select firstid, secondid from friends where firstid=$1 or secondid = $1`
 
 	var friendListID = make([]int, 0)
-	rows, err := _self.Db.Query(query, userID)
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +132,132 @@ func (_self FriendRepo) GetFriendListByID(userID int) ([]int, error) {
 	return friendListID, err
 }
 
-func (_self FriendRepo) GetBlockingListByID(userID int) ([]int, error) {
+// GetFriendLinksNoBlock returns the friends of each of userIDs which neither block nor are blocked by it,
+// ordered by UserID and then FriendID
+func (_self FriendRepo) GetFriendLinksNoBlock(ctx context.Context, userIDs []int) ([]model.FriendLink, error) {
+	query := `select u.id, ue.id
+			  from unnest($1::int8[]) as u(id)
+			  join friends f on f.firstid = u.id or f.secondid = u.id
+			  join useremails ue on ue.id = case when f.firstid = u.id then f.secondid else f.firstid end
+			  where not exists(
+			  	select true from blocks b
+			  	where (b.requestorid = u.id and b.targetid = ue.id)
+			  	   or (b.requestorid = ue.id and b.targetid = u.id)
+			  )
+			  order by u.id, ue.id`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]model.FriendLink, 0)
+	for rows.Next() {
+		var link model.FriendLink
+		if err := rows.Scan(&link.UserID, &link.FriendID); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// GetFriendSuggestions ranks the friends of friends of userID by their count of mutual friends, oldest user first on ties.
+// Connections blocked in either direction are not counted, and the user, its friends and the users blocking or
+// blocked by it are never suggested.
+func (_self FriendRepo) GetFriendSuggestions(ctx context.Context, userID int, limit int) ([]model.FriendSuggestion, error) {
+	query := `with userfriends as (select ue.id ` + friendsNoBlockQuery + `)
+			  select c.email, count(*) as mutualfriends
+			  from userfriends uf
+			  join friends f2 on f2.firstid = uf.id or f2.secondid = uf.id
+			  join useremails c on c.id = case when f2.firstid = uf.id then f2.secondid else f2.firstid end
+			  where c.id <> $1
+			    and c.id not in (select id from userfriends)
+			    and not exists(
+			    	select true from blocks b
+			    	where (b.requestorid = uf.id and b.targetid = c.id)
+			    	   or (b.requestorid = c.id and b.targetid = uf.id)
+			    	   or (b.requestorid = $1 and b.targetid = c.id)
+			    	   or (b.requestorid = c.id and b.targetid = $1)
+			    )
+			  group by c.id, c.email
+			  order by mutualfriends desc, c.id
+			  limit $2`
+	rows, err := _self.Db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]model.FriendSuggestion, 0)
+	for rows.Next() {
+		var suggestion model.FriendSuggestion
+		if err := rows.Scan(&suggestion.Email, &suggestion.MutualFriends); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, rows.Err()
+}
+
+// GetFriendPageByID returns up to Limit friends after Cursor, ordered by email or by friendship creation time
+func (_self FriendRepo) GetFriendPageByID(ctx context.Context, input *model.FriendListRepoInput) ([]model.FriendListItem, error) {
+	var rows *sql.Rows
+	var err error
+	if input.Sort == model.FriendListSortCreated {
+		after := time.Time{}
+		afterID := 0
+		if input.Cursor != nil {
+			after, afterID = input.Cursor.CreatedAt, input.Cursor.ID
+		}
+		query := `select ue.id, ue.email, f.createdat ` + friendsNoBlockQuery + `
+			    and (f.createdat, ue.id) > ($2, $3)
+			  order by f.createdat, ue.id
+			  limit $4`
+		rows, err = _self.Db.QueryContext(ctx, query, input.UserID, after, afterID, input.Limit)
+	} else {
+		after := ""
+		afterID := 0
+		if input.Cursor != nil {
+			after, afterID = input.Cursor.Email, input.Cursor.ID
+		}
+		query := `select ue.id, ue.email, f.createdat ` + friendsNoBlockQuery + `
+			    and (ue.email, ue.id) > ($2, $3)
+			  order by ue.email, ue.id
+			  limit $4`
+		rows, err = _self.Db.QueryContext(ctx, query, input.UserID, after, afterID, input.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	friends := make([]model.FriendListItem, 0)
+	for rows.Next() {
+		var friend model.FriendListItem
+		if err := rows.Scan(&friend.ID, &friend.Email, &friend.CreatedAt); err != nil {
+			return nil, err
+		}
+		friends = append(friends, friend)
+	}
+	return friends, rows.Err()
+}
+
+// CountFriendsByID counts the friends returned by GetFriendPageByID across all pages
+func (_self FriendRepo) CountFriendsByID(ctx context.Context, userID int) (int, error) {
+	query := `select count(*) ` + friendsNoBlockQuery
+	var count int
+	if err := _self.Db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (_self FriendRepo) GetBlockingListByID(ctx context.Context, userID int) ([]int, error) {
 	query := `select targetid from blocks where requestorid = $1`
 
 	var blockedListID = make([]int, 0)
-	rows, err := _self.Db.Query(query, userID)
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -69,11 +272,11 @@ func (_self FriendRepo) GetBlockingListByID(userID int) ([]int, error) {
 	return blockedListID, err
 }
 
-func (_self FriendRepo) GetBlockedListByID(userID int) ([]int, error) {
+func (_self FriendRepo) GetBlockedListByID(ctx context.Context, userID int) ([]int, error) {
 	query := `select requestorid from blocks where targetid = $1`
 
 	var blockingListID = make([]int, 0)
-	rows, err := _self.Db.Query(query, userID)
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -88,14 +291,14 @@ func (_self FriendRepo) GetBlockedListByID(userID int) ([]int, error) {
 	return blockingListID, err
 }
 
-func (_self FriendRepo) IsBlockedByOtherEmail(firstUserID int, secondUserID int) (bool, error) {
+func (_self FriendRepo) IsBlockedByOtherEmail(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
 	query := `select exists(select true from blocks WHERE (
     						    	requestorid in ($1, $2) 
 								    AND 
     						    	targetid in ($1, $2)
     						      ))`
 	var isBlocked bool
-	err := _self.Db.QueryRow(query, firstUserID, secondUserID).Scan(&isBlocked)
+	err := _self.Db.QueryRowContext(ctx, query, firstUserID, secondUserID).Scan(&isBlocked)
 	if err != nil {
 		return true, err
 	}
@@ -105,7 +308,7 @@ func (_self FriendRepo) IsBlockedByOtherEmail(firstUserID int, secondUserID int)
 	return false, nil
 }
 
-func (_self FriendRepo) IsExistedFriend(firstUserID int, secondUserID int) (bool, error) {
+func (_self FriendRepo) IsExistedFriend(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
 	query := `select exists(
     						select true 
     						from friends 
@@ -116,7 +319,7 @@ func (_self FriendRepo) IsExistedFriend(firstUserID int, secondUserID int) (bool
     						      )
     						)`
 	var existed bool
-	err := _self.Db.QueryRow(query, firstUserID, secondUserID).Scan(&existed)
+	err := _self.Db.QueryRowContext(ctx, query, firstUserID, secondUserID).Scan(&existed)
 	if err != nil {
 		return true, err
 	}
@@ -126,24 +329,7 @@ func (_self FriendRepo) IsExistedFriend(firstUserID int, secondUserID int) (bool
 	return false, nil
 }
 
-func (_self FriendRepo) GetSubscriberList(userID int) ([]int, error) {
-	query := `select requestorid from subscriptions where targetid=$1`
-	subscribers := make([]int, 0)
-	rows, err := _self.Db.Query(query, userID)
-	if err != nil {
-		return nil, err
-	}
-	for rows.Next() {
-		var id int
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		subscribers = append(subscribers, id)
-	}
-	return subscribers, nil
-}
-
-func (_self FriendRepo) GetEmailsFriendOrSubscribedWithNoBlocked(userID int) ([]int, error) {
+func (_self FriendRepo) GetEmailsFriendOrSubscribedWithNoBlocked(ctx context.Context, userID int) ([]int, error) {
 	query := `select distinct val.ID
 			  from
 				(
@@ -159,8 +345,8 @@ func (_self FriendRepo) GetEmailsFriendOrSubscribedWithNoBlocked(userID int) ([]
 					from
 						subscriptions s
 							join useremails ue
-								 on s.targetid = ue.id
-					where ue.id <> $1
+								 on s.requestorid = ue.id
+					where s.targetid = $1
 				) as val
 				where not exists(
 				    select 1
@@ -168,7 +354,7 @@ func (_self FriendRepo) GetEmailsFriendOrSubscribedWithNoBlocked(userID int) ([]
 				    where b.requestorid = val.id
 				    and b.targetid = $1
 				)`
-	rows, err := _self.Db.Query(query, userID)
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
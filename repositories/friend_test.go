@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"sync"
 	"testing"
 
 	"S3_FriendManagement_ThinhNguyen/model"
@@ -29,13 +31,171 @@ func TestFriendRepo_CreateFriend(t *testing.T) {
 			mockDB:      testhelpers.ConnectDBFailed(),
 		},
 		{
-			name: "Create friend connection success",
+			name: "Email addresses blocked each other",
+			input: &model.FriendsRepoInput{
+				FirstID:  1,
+				SecondID: 2,
+			},
+			expectedErr: model.ErrBlockedEachOther,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDB, testCase.preparePath)
+
+			friendRepo := FriendRepo{
+				Db: testCase.mockDB,
+			}
+
+			// When
+			err := friendRepo.CreateFriend(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFriendRepo_CreateFriendConcurrently(t *testing.T) {
+	// Given
+	db := testhelpers.ConnectDB()
+	testhelpers.PrepareDBForTest(db, "../testhelpers/preparedata/datafortest")
+
+	var firstID, secondID int
+	require.NoError(t, db.QueryRow(`insert into useremails(email) values ('first@race.com') returning id`).Scan(&firstID))
+	require.NoError(t, db.QueryRow(`insert into useremails(email) values ('second@race.com') returning id`).Scan(&secondID))
+
+	friendRepo := FriendRepo{
+		Db: db,
+	}
+
+	// When
+	const requests = 10
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			//Half of the requests name the users the other way round
+			input := &model.FriendsRepoInput{
+				FirstID:  firstID,
+				SecondID: secondID,
+			}
+			if i%2 == 1 {
+				input.FirstID, input.SecondID = secondID, firstID
+			}
+			errs <- friendRepo.CreateFriend(context.Background(), input)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	// Then
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		require.Equal(t, model.ErrFriendExisted, err)
+	}
+	require.Equal(t, 1, created)
+
+	var rows int
+	require.NoError(t, db.QueryRow(`select count(*) from friends
+		where least(firstid, secondid) = least($1, $2) and greatest(firstid, secondid) = greatest($1, $2)`,
+		firstID, secondID).Scan(&rows))
+	require.Equal(t, 1, rows)
+}
+
+func TestFriendRepo_CreateFriends(t *testing.T) {
+	// Given
+	db := testhelpers.ConnectDB()
+	testhelpers.PrepareDBForTest(db, "../testhelpers/preparedata/datafortest")
+
+	var thirdID, fourthID int
+	require.NoError(t, db.QueryRow(`insert into useremails(email) values ('third@batch.com') returning id`).Scan(&thirdID))
+	require.NoError(t, db.QueryRow(`insert into useremails(email) values ('fourth@batch.com') returning id`).Scan(&fourthID))
+
+	friendRepo := FriendRepo{
+		Db: db,
+	}
+
+	// When
+	results, err := friendRepo.CreateFriends(context.Background(), []model.FriendsRepoInput{
+		{FirstID: 1, SecondID: 2},
+		{FirstID: thirdID, SecondID: fourthID},
+		{FirstID: fourthID, SecondID: thirdID},
+		{FirstID: 1, SecondID: thirdID},
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, []error{model.ErrBlockedEachOther, nil, model.ErrFriendExisted, nil}, results)
+
+	// When
+	results, err = friendRepo.CreateFriends(context.Background(), []model.FriendsRepoInput{
+		{FirstID: thirdID, SecondID: 1},
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, []error{model.ErrFriendExisted}, results)
+
+	// When
+	_, err = FriendRepo{Db: testhelpers.ConnectDBFailed()}.CreateFriends(context.Background(), []model.FriendsRepoInput{
+		{FirstID: thirdID, SecondID: fourthID},
+	})
+
+	// Then
+	require.EqualError(t, err, "pq: password authentication failed for user \"postgrespassword=000000\"")
+}
+
+func TestFriendRepo_DeleteFriend(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       *model.FriendsRepoInput
+		expectedErr error
+		preparePath string
+		mockDB      *sql.DB
+	}{
+		{
+			name: "Delete failed with error",
+			input: &model.FriendsRepoInput{
+				FirstID:  1,
+				SecondID: 2,
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDB:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Delete friend connection success",
 			input: &model.FriendsRepoInput{
 				FirstID:  1,
 				SecondID: 2,
 			},
 			expectedErr: nil,
-			preparePath: "./testhelpers/preparedata/datafortest",
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+		{
+			name: "Delete friend connection in reverse order success",
+			input: &model.FriendsRepoInput{
+				FirstID:  2,
+				SecondID: 1,
+			},
+			expectedErr: nil,
+			preparePath: "../testhelpers/preparedata/datafortest",
 			mockDB:      testhelpers.ConnectDB(),
 		},
 	}
@@ -50,13 +210,16 @@ func TestFriendRepo_CreateFriend(t *testing.T) {
 			}
 
 			// When
-			err := friendRepo.CreateFriend(testCase.input)
+			err := friendRepo.DeleteFriend(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
 				require.EqualError(t, err, testCase.expectedErr.Error())
 			} else {
 				require.NoError(t, err)
+				existed, err := friendRepo.IsExistedFriend(context.Background(), testCase.input.FirstID, testCase.input.SecondID)
+				require.NoError(t, err)
+				require.False(t, existed)
 			}
 		})
 	}
@@ -107,7 +270,7 @@ func TestFriendRepo_IsExistedFriend(t *testing.T) {
 			}
 
 			// When
-			result, err := friendRepo.IsExistedFriend(testCase.input[0], testCase.input[1])
+			result, err := friendRepo.IsExistedFriend(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -165,7 +328,7 @@ func TestFriendRepo_IsBlockedByOtherEmail(t *testing.T) {
 			}
 
 			// When
-			result, err := friendRepo.IsBlockedByOtherEmail(testCase.input[0], testCase.input[1])
+			result, err := friendRepo.IsBlockedByOtherEmail(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -215,7 +378,7 @@ func TestFriendRepo_GetFriendListByID(t *testing.T) {
 			}
 
 			// When
-			result, err := friendRepo.GetFriendListByID(testCase.input)
+			result, err := friendRepo.GetFriendListByID(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedError != nil {
@@ -228,28 +391,216 @@ func TestFriendRepo_GetFriendListByID(t *testing.T) {
 	}
 }
 
-func TestFriendRepo_GetBlockedListByID(t *testing.T) {
+func TestFriendRepo_GetFriendLinksNoBlock(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []int
+		expectedResult []model.FriendLink
+		expectedError  error
+		preparePath    string
+		extraData      string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get friend links failed with error",
+			input:          []int{1},
+			expectedResult: nil,
+			expectedError:  errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Get friend links of a level, skipping the blocked connection",
+			input:          []int{1, 3},
+			expectedResult: []model.FriendLink{{UserID: 1, FriendID: 3}, {UserID: 3, FriendID: 1}, {UserID: 3, FriendID: 2}},
+			expectedError:  nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			extraData: `insert into useremails(email) values ('kate@example.com');
+						insert into friends(firstid, secondid) values (1, 3), (2, 3);`,
+			mockDb: testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+
+			friendRepo := FriendRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := friendRepo.GetFriendLinksNoBlock(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendRepo_GetFriendSuggestions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []int
+		expectedResult []model.FriendSuggestion
+		expectedError  error
+		preparePath    string
+		extraData      string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get friend suggestions failed with error",
+			input:          []int{1, 10},
+			expectedResult: nil,
+			expectedError:  errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:  "Get friend suggestions ranked by mutual friends, skipping blocked users",
+			input: []int{1, 10},
+			expectedResult: []model.FriendSuggestion{
+				{Email: "mary@example.com", MutualFriends: 2},
+				{Email: "lee@example.com", MutualFriends: 1},
+			},
+			expectedError: nil,
+			preparePath:   "../testhelpers/preparedata/datafortest",
+			extraData: `insert into useremails(email) values ('kate@example.com'), ('john@example.com'), ('mary@example.com'),
+							('lee@example.com'), ('ann@example.com');
+						insert into friends(firstid, secondid) values (1, 3), (1, 4), (3, 5), (4, 5), (2, 3), (4, 6), (3, 7);
+						insert into blocks(requestorid, targetid) values (7, 3);`,
+			mockDb: testhelpers.ConnectDB(),
+		},
+		{
+			name:           "Get friend suggestions with limit",
+			input:          []int{1, 1},
+			expectedResult: []model.FriendSuggestion{{Email: "mary@example.com", MutualFriends: 2}},
+			expectedError:  nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			extraData: `insert into useremails(email) values ('kate@example.com'), ('john@example.com'), ('mary@example.com'),
+							('lee@example.com');
+						insert into friends(firstid, secondid) values (1, 3), (1, 4), (3, 5), (4, 5), (4, 6);`,
+			mockDb: testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+
+			friendRepo := FriendRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := friendRepo.GetFriendSuggestions(context.Background(), testCase.input[0], testCase.input[1])
+
+			// Then
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendRepo_GetFriendPageByID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *model.FriendListRepoInput
+		expectedResult []model.FriendListItem
+		expectedError  error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name: "Get friend page failed with error",
+			input: &model.FriendListRepoInput{
+				UserID: 1,
+				Limit:  10,
+				Sort:   model.FriendListSortEmail,
+			},
+			expectedResult: nil,
+			expectedError:  errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Blocked friends are left out",
+			input: &model.FriendListRepoInput{
+				UserID: 1,
+				Limit:  10,
+				Sort:   model.FriendListSortCreated,
+			},
+			expectedResult: []model.FriendListItem{},
+			expectedError:  nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			friendRepo := FriendRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := friendRepo.GetFriendPageByID(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendRepo_CountFriendsByID(t *testing.T) {
 	testCases := []struct {
 		name           string
 		input          int
-		expectedResult []int
-		expectedErr    error
+		expectedResult int
+		expectedError  error
 		preparePath    string
 		mockDb         *sql.DB
 	}{
 		{
-			name:           "Get blocked list failed with error",
+			name:           "Count friends failed with error",
 			input:          1,
-			expectedResult: nil,
-			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			expectedResult: 0,
+			expectedError:  errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
 			preparePath:    "",
 			mockDb:         testhelpers.ConnectDBFailed(),
 		},
 		{
-			name:           "Get blocked list success",
+			name:           "Blocked friends are not counted",
 			input:          2,
-			expectedResult: []int{1},
-			expectedErr:    nil,
+			expectedResult: 0,
+			expectedError:  nil,
 			preparePath:    "../testhelpers/preparedata/datafortest",
 			mockDb:         testhelpers.ConnectDB(),
 		},
@@ -265,11 +616,11 @@ func TestFriendRepo_GetBlockedListByID(t *testing.T) {
 			}
 
 			// When
-			result, err := friendRepo.GetBlockedListByID(testCase.input)
+			result, err := friendRepo.CountFriendsByID(context.Background(), testCase.input)
 
 			// Then
-			if testCase.expectedErr != nil {
-				require.EqualError(t, err, testCase.expectedErr.Error())
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, testCase.expectedResult, result)
@@ -278,7 +629,7 @@ func TestFriendRepo_GetBlockedListByID(t *testing.T) {
 	}
 }
 
-func TestFriendRepo_GetBlockingListByID(t *testing.T) {
+func TestFriendRepo_GetBlockedListByID(t *testing.T) {
 	testCases := []struct {
 		name           string
 		input          int
@@ -288,7 +639,7 @@ func TestFriendRepo_GetBlockingListByID(t *testing.T) {
 		mockDb         *sql.DB
 	}{
 		{
-			name:           "Get blocking list failed with error",
+			name:           "Get blocked list failed with error",
 			input:          1,
 			expectedResult: nil,
 			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
@@ -296,9 +647,9 @@ func TestFriendRepo_GetBlockingListByID(t *testing.T) {
 			mockDb:         testhelpers.ConnectDBFailed(),
 		},
 		{
-			name:           "Get blocking list success",
-			input:          1,
-			expectedResult: []int{2},
+			name:           "Get blocked list success",
+			input:          2,
+			expectedResult: []int{1},
 			expectedErr:    nil,
 			preparePath:    "../testhelpers/preparedata/datafortest",
 			mockDb:         testhelpers.ConnectDB(),
@@ -315,7 +666,7 @@ func TestFriendRepo_GetBlockingListByID(t *testing.T) {
 			}
 
 			// When
-			result, err := friendRepo.GetBlockingListByID(testCase.input)
+			result, err := friendRepo.GetBlockedListByID(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -328,7 +679,7 @@ func TestFriendRepo_GetBlockingListByID(t *testing.T) {
 	}
 }
 
-func TestFriendRepo_GetSubscriberList(t *testing.T) {
+func TestFriendRepo_GetBlockingListByID(t *testing.T) {
 	testCases := []struct {
 		name           string
 		input          int
@@ -338,7 +689,7 @@ func TestFriendRepo_GetSubscriberList(t *testing.T) {
 		mockDb         *sql.DB
 	}{
 		{
-			name:           "Get failed with error",
+			name:           "Get blocking list failed with error",
 			input:          1,
 			expectedResult: nil,
 			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
@@ -346,7 +697,7 @@ func TestFriendRepo_GetSubscriberList(t *testing.T) {
 			mockDb:         testhelpers.ConnectDBFailed(),
 		},
 		{
-			name:           "Get success",
+			name:           "Get blocking list success",
 			input:          1,
 			expectedResult: []int{2},
 			expectedErr:    nil,
@@ -365,7 +716,7 @@ func TestFriendRepo_GetSubscriberList(t *testing.T) {
 			}
 
 			// When
-			result, err := friendRepo.GetSubscriberList(testCase.input)
+			result, err := friendRepo.GetBlockingListByID(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -385,6 +736,7 @@ func TestFriendRepo_GetEmailsFriendOrSubscribedWithNoBlocked(t *testing.T) {
 		expectedResult []int
 		expectedErr    error
 		preparePath    string
+		extraData      string
 		mockDb         *sql.DB
 	}{
 		{
@@ -411,19 +763,33 @@ func TestFriendRepo_GetEmailsFriendOrSubscribedWithNoBlocked(t *testing.T) {
 			preparePath:    "../testhelpers/preparedata/datafortest",
 			mockDb:         testhelpers.ConnectDB(),
 		},
+		{
+			name:           "Get success with subscribers only",
+			input:          2,
+			expectedResult: []int{3},
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			extraData: `insert into useremails(email) values ('kate@example.com'), ('john@example.com');
+						insert into subscriptions(requestorid, targetid) values (3, 2), (3, 4);`,
+			mockDb: testhelpers.ConnectDB(),
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			// Given
 			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
 
 			friendRepo := FriendRepo{
 				Db: testCase.mockDb,
 			}
 
 			// When
-			result, err := friendRepo.GetEmailsFriendOrSubscribedWithNoBlocked(testCase.input)
+			result, err := friendRepo.GetEmailsFriendOrSubscribedWithNoBlocked(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+)
+
+type IFriendRequestRepo interface {
+	CreateFriendRequest(context.Context, *model.FriendRequestRepoInput) error
+	UpdateFriendRequestStatus(context.Context, *model.FriendRequestRepoInput) error
+	AcceptFriendRequest(context.Context, *model.FriendRequestRepoInput) error
+	IsPendingFriendRequest(context.Context, int, int) (bool, error)
+	GetIncomingFriendRequests(context.Context, int) ([]int, error)
+	GetOutgoingFriendRequests(context.Context, int) ([]int, error)
+}
+
+type FriendRequestRepo struct {
+	Db *sql.DB
+}
+
+// CreateFriendRequest inserts a pending request. It returns model.ErrFriendRequestSent when another request
+// got there first, the database keeps one pending request per pair of users.
+func (_self FriendRequestRepo) CreateFriendRequest(ctx context.Context, friendRequest *model.FriendRequestRepoInput) error {
+	query := `insert into friendrequests(requestorid, targetid, status) values ($1, $2, $3)`
+	_, err := _self.Db.ExecContext(ctx, query, friendRequest.Requestor, friendRequest.Target, model.FriendRequestStatusPending)
+	if isUniqueViolation(err) {
+		return model.ErrFriendRequestSent
+	}
+	return err
+}
+
+// UpdateFriendRequestStatus moves the pending request from requestor to target into the given status.
+// It returns model.ErrFriendRequestClosed when another request closed it first.
+func (_self FriendRequestRepo) UpdateFriendRequestStatus(ctx context.Context, friendRequest *model.FriendRequestRepoInput) error {
+	query := `update friendrequests
+			  set status = $3, updatedat = now()
+			  where requestorid = $1
+			    and targetid = $2
+			    and status = $4`
+	result, err := _self.Db.ExecContext(ctx, query, friendRequest.Requestor, friendRequest.Target, friendRequest.Status, model.FriendRequestStatusPending)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return model.ErrFriendRequestClosed
+	}
+	return nil
+}
+
+// AcceptFriendRequest closes the pending request and creates the friend connection in one transaction
+func (_self FriendRequestRepo) AcceptFriendRequest(ctx context.Context, friendRequest *model.FriendRequestRepoInput) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `update friendrequests
+			  set status = $3, updatedat = now()
+			  where requestorid = $1
+			    and targetid = $2
+			    and status = $4`
+	result, err := tx.ExecContext(ctx, query, friendRequest.Requestor, friendRequest.Target, model.FriendRequestStatusAccepted, model.FriendRequestStatusPending)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return model.ErrFriendRequestClosed
+	}
+
+	if err := createFriendTx(ctx, tx, friendRequest.Requestor, friendRequest.Target); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (_self FriendRequestRepo) IsPendingFriendRequest(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	query := `select exists(select true from friendrequests where requestorid=$1 and targetid=$2 and status=$3)`
+	var existed bool
+	err := _self.Db.QueryRowContext(ctx, query, requestorID, targetID, model.FriendRequestStatusPending).Scan(&existed)
+	if err != nil {
+		return true, err
+	}
+	if existed {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (_self FriendRequestRepo) GetIncomingFriendRequests(ctx context.Context, userID int) ([]int, error) {
+	query := `select requestorid from friendrequests where targetid=$1 and status=$2 order by createdat`
+	return _self.getUserIDs(ctx, query, userID)
+}
+
+func (_self FriendRequestRepo) GetOutgoingFriendRequests(ctx context.Context, userID int) ([]int, error) {
+	query := `select targetid from friendrequests where requestorid=$1 and status=$2 order by createdat`
+	return _self.getUserIDs(ctx, query, userID)
+}
+
+func (_self FriendRequestRepo) getUserIDs(ctx context.Context, query string, userID int) ([]int, error) {
+	rows, err := _self.Db.QueryContext(ctx, query, userID, model.FriendRequestStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
@@ -0,0 +1,372 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFriendRequestRepo_CreateFriendRequest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       *model.FriendRequestRepoInput
+		expectedErr error
+		preparePath string
+		extraData   string
+		mockDB      *sql.DB
+	}{
+		{
+			name: "Create failed with error",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDB:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Pending request existed in the other direction",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: model.ErrFriendRequestSent,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+		{
+			name: "Create success",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    3,
+			},
+			expectedErr: nil,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			extraData:   `insert into useremails(email) values ('kate@example.com');`,
+			mockDB:      testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDB, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDB.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+
+			friendRequestRepo := FriendRequestRepo{
+				Db: testCase.mockDB,
+			}
+
+			// When
+			err := friendRequestRepo.CreateFriendRequest(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFriendRequestRepo_UpdateFriendRequestStatus(t *testing.T) {
+	testCases := []struct {
+		name            string
+		input           *model.FriendRequestRepoInput
+		expectedPending bool
+		expectedErr     error
+		preparePath     string
+		mockDB          *sql.DB
+	}{
+		{
+			name: "Update failed with error",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 2,
+				Target:    1,
+				Status:    model.FriendRequestStatusAccepted,
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDB:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Update pending request to rejected success",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 2,
+				Target:    1,
+				Status:    model.FriendRequestStatusRejected,
+			},
+			expectedPending: false,
+			expectedErr:     nil,
+			preparePath:     "../testhelpers/preparedata/datafortest",
+			mockDB:          testhelpers.ConnectDB(),
+		},
+		{
+			name: "Pending friend request does not exist",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    2,
+				Status:    model.FriendRequestStatusCancelled,
+			},
+			expectedErr: model.ErrFriendRequestClosed,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDB, testCase.preparePath)
+
+			friendRequestRepo := FriendRequestRepo{
+				Db: testCase.mockDB,
+			}
+
+			// When
+			err := friendRequestRepo.UpdateFriendRequestStatus(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				pending, err := friendRequestRepo.IsPendingFriendRequest(context.Background(), testCase.input.Requestor, testCase.input.Target)
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedPending, pending)
+			}
+		})
+	}
+}
+
+func TestFriendRequestRepo_AcceptFriendRequest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       *model.FriendRequestRepoInput
+		expectedErr error
+		preparePath string
+		mockDB      *sql.DB
+	}{
+		{
+			name: "Accept failed with error",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 2,
+				Target:    1,
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDB:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Pending friend request does not exist",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: model.ErrFriendRequestClosed,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+		{
+			name: "Email addresses blocked each other",
+			input: &model.FriendRequestRepoInput{
+				Requestor: 2,
+				Target:    1,
+			},
+			expectedErr: model.ErrBlockedEachOther,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDB, testCase.preparePath)
+
+			friendRequestRepo := FriendRequestRepo{
+				Db: testCase.mockDB,
+			}
+
+			// When
+			err := friendRequestRepo.AcceptFriendRequest(context.Background(), testCase.input)
+
+			// Then
+			require.EqualError(t, err, testCase.expectedErr.Error())
+			if testCase.preparePath != "" {
+				//The transaction was rolled back so the request is still pending
+				pending, err := friendRequestRepo.IsPendingFriendRequest(context.Background(), 2, 1)
+				require.NoError(t, err)
+				require.True(t, pending)
+			}
+		})
+	}
+}
+
+func TestFriendRequestRepo_IsPendingFriendRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []int
+		expectedResult bool
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Check pending failed with error",
+			input:          []int{2, 1},
+			expectedResult: true,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Pending request existed",
+			input:          []int{2, 1},
+			expectedResult: true,
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+		{
+			name:           "Pending request in the other direction is not exist",
+			input:          []int{1, 2},
+			expectedResult: false,
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			friendRequestRepo := FriendRequestRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := friendRequestRepo.IsPendingFriendRequest(context.Background(), testCase.input[0], testCase.input[1])
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendRequestRepo_GetIncomingFriendRequests(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          int
+		expectedResult []int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get incoming failed with error",
+			input:          1,
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Get incoming success",
+			input:          1,
+			expectedResult: []int{2},
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			friendRequestRepo := FriendRequestRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := friendRequestRepo.GetIncomingFriendRequests(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendRequestRepo_GetOutgoingFriendRequests(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          int
+		expectedResult []int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get outgoing failed with error",
+			input:          2,
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Get outgoing success",
+			input:          2,
+			expectedResult: []int{1},
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			friendRequestRepo := FriendRequestRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := friendRequestRepo.GetOutgoingFriendRequests(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
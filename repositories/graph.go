@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/lib/pq"
+)
+
+type IGraphRepo interface {
+	ExportGraph(ctx context.Context, writer model.GraphWriter) error
+	ImportGraph(ctx context.Context, graph *model.Graph) error
+}
+
+type GraphRepo struct {
+	Db *sql.DB
+}
+
+// ExportGraph streams every user and relationship from one snapshot of the database into writer,
+// row by row, so the graph is never held in memory
+func (_self GraphRepo) ExportGraph(ctx context.Context, writer model.GraphWriter) error {
+	tx, err := _self.Db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exportedAt time.Time
+	if err := tx.QueryRowContext(ctx, `select now()`).Scan(&exportedAt); err != nil {
+		return err
+	}
+	if err := writer.Begin(exportedAt); err != nil {
+		return err
+	}
+	if err := exportGraphUsers(ctx, tx, writer); err != nil {
+		return err
+	}
+	query := `select firstid, secondid, createdat from friends order by id`
+	if err := exportGraphEdges(ctx, tx, writer, model.GraphRelationFriends, query, true); err != nil {
+		return err
+	}
+	query = `select requestorid, targetid from subscriptions order by id`
+	if err := exportGraphEdges(ctx, tx, writer, model.GraphRelationSubscriptions, query, false); err != nil {
+		return err
+	}
+	query = `select requestorid, targetid from blocks order by id`
+	if err := exportGraphEdges(ctx, tx, writer, model.GraphRelationBlocks, query, false); err != nil {
+		return err
+	}
+	return writer.End()
+}
+
+func exportGraphUsers(ctx context.Context, tx *sql.Tx, writer model.GraphWriter) error {
+	query := `select id, email, displayname, avatarurl, bio, status, createdat from useremails order by id`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user model.GraphUser
+		if err := rows.Scan(&user.ID, &user.Email, &user.DisplayName, &user.AvatarURL, &user.Bio, &user.Status, &user.CreatedAt); err != nil {
+			return err
+		}
+		if err := writer.User(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// exportGraphEdges writes the edges selected by query as the relation, withCreatedAt tells it selects their creation time too
+func exportGraphEdges(ctx context.Context, tx *sql.Tx, writer model.GraphWriter, relation string, query string, withCreatedAt bool) error {
+	if err := writer.Relation(relation); err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var edge model.GraphEdge
+		if !withCreatedAt {
+			if err := rows.Scan(&edge.Source, &edge.Target); err != nil {
+				return err
+			}
+		} else {
+			var createdAt time.Time
+			if err := rows.Scan(&edge.Source, &edge.Target, &createdAt); err != nil {
+				return err
+			}
+			edge.CreatedAt = &createdAt
+		}
+		if err := writer.Edge(edge); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ImportGraph inserts a graph checked by model.Graph.Validate into an empty database, in one transaction.
+// The users get new IDs and the edges are remapped to them. It returns model.ErrGraphDatabaseNotEmpty
+// when there already are users.
+func (_self GraphRepo) ImportGraph(ctx context.Context, graph *model.Graph) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	//Hold off every other insert until the import is done
+	if _, err := tx.ExecContext(ctx, `lock table useremails in exclusive mode`); err != nil {
+		return err
+	}
+	var hasUsers bool
+	if err := tx.QueryRowContext(ctx, `select exists(select 1 from useremails)`).Scan(&hasUsers); err != nil {
+		return err
+	}
+	if hasUsers {
+		return model.ErrGraphDatabaseNotEmpty
+	}
+
+	newIDs, err := importGraphUsers(ctx, tx, graph.Users)
+	if err != nil {
+		return err
+	}
+
+	query := `insert into friends(firstid, secondid, createdat)
+			  select e.source, e.target, coalesce(nullif(e.createdat, '')::timestamp, now())
+			  from unnest($1::int8[], $2::int8[], $3::text[]) as e(source, target, createdat)`
+	if err := importGraphEdges(ctx, tx, query, graph.Friends, newIDs, true); err != nil {
+		return err
+	}
+	query = `insert into subscriptions(requestorid, targetid) select * from unnest($1::int8[], $2::int8[])`
+	if err := importGraphEdges(ctx, tx, query, graph.Subscriptions, newIDs, false); err != nil {
+		return err
+	}
+	query = `insert into blocks(requestorid, targetid) select * from unnest($1::int8[], $2::int8[])`
+	if err := importGraphEdges(ctx, tx, query, graph.Blocks, newIDs, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// importGraphUsers inserts the users with one statement and returns their new IDs, keyed by their ID in the graph
+func importGraphUsers(ctx context.Context, tx *sql.Tx, users []model.GraphUser) (map[int]int, error) {
+	newIDs := make(map[int]int, len(users))
+	if len(users) == 0 {
+		return newIDs, nil
+	}
+
+	emails := make([]string, len(users))
+	displayNames := make([]string, len(users))
+	avatarURLs := make([]string, len(users))
+	bios := make([]string, len(users))
+	statuses := make([]string, len(users))
+	createdAts := make([]string, len(users))
+	graphIDs := make(map[string]int, len(users))
+	for i, user := range users {
+		emails[i], displayNames[i], avatarURLs[i], bios[i], statuses[i] = user.Email, user.DisplayName, user.AvatarURL, user.Bio, user.Status
+		createdAts[i] = formatGraphTime(user.CreatedAt)
+		graphIDs[user.Email] = user.ID
+	}
+
+	query := `insert into useremails(email, displayname, avatarurl, bio, status, createdat)
+			  select u.email, u.displayname, u.avatarurl, u.bio, u.status, coalesce(nullif(u.createdat, '')::timestamp, now())
+			  from unnest($1::varchar[], $2::varchar[], $3::varchar[], $4::varchar[], $5::varchar[], $6::text[])
+			  	as u(email, displayname, avatarurl, bio, status, createdat)
+			  returning id, email`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(emails), pq.Array(displayNames), pq.Array(avatarURLs),
+		pq.Array(bios), pq.Array(statuses), pq.Array(createdAts))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var email string
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		newIDs[graphIDs[email]] = id
+	}
+	return newIDs, rows.Err()
+}
+
+// importGraphEdges inserts the edges with one statement after remapping their users to newIDs
+func importGraphEdges(ctx context.Context, tx *sql.Tx, query string, edges []model.GraphEdge, newIDs map[int]int, withCreatedAt bool) error {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	sources := make([]int, len(edges))
+	targets := make([]int, len(edges))
+	createdAts := make([]string, len(edges))
+	for i, edge := range edges {
+		sources[i], targets[i] = newIDs[edge.Source], newIDs[edge.Target]
+		if edge.CreatedAt != nil {
+			createdAts[i] = formatGraphTime(*edge.CreatedAt)
+		}
+	}
+
+	args := []interface{}{pq.Array(sources), pq.Array(targets)}
+	if withCreatedAt {
+		args = append(args, pq.Array(createdAts))
+	}
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// formatGraphTime writes a time for a timestamp column, the zero time is left empty so the column gets now()
+func formatGraphTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
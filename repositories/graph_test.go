@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/graphio"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphRepo_ExportGraph(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectedErr error
+		preparePath string
+		mockDb      *sql.DB
+	}{
+		{
+			name:        "Export graph failed with error",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "Export graph success",
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			graphRepo := GraphRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := exportGraph(graphRepo)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Users, 2)
+			require.Equal(t, 1, result.Users[0].ID)
+			require.Equal(t, "abc@xyz.com", result.Users[0].Email)
+			require.Equal(t, "Andy", result.Users[0].DisplayName)
+			require.Equal(t, "xyz@abc.com", result.Users[1].Email)
+			require.Len(t, result.Friends, 1)
+			require.Equal(t, 1, result.Friends[0].Source)
+			require.Equal(t, 2, result.Friends[0].Target)
+			require.NotNil(t, result.Friends[0].CreatedAt)
+			require.Equal(t, []model.GraphEdge{{Source: 2, Target: 1}}, result.Subscriptions)
+			require.Equal(t, []model.GraphEdge{{Source: 1, Target: 2}}, result.Blocks)
+		})
+	}
+}
+
+func TestGraphRepo_ImportGraph(t *testing.T) {
+	createdAt := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	graph := &model.Graph{
+		Users: []model.GraphUser{
+			{ID: 7, Email: "abc@xyz.com", DisplayName: "Andy", Status: model.UserStatusAway, CreatedAt: createdAt},
+			{ID: 9, Email: "xyz@abc.com", Status: model.UserStatusActive},
+			{ID: 12, Email: "mno@abc.com", Status: model.UserStatusActive},
+		},
+		Friends:       []model.GraphEdge{{Source: 9, Target: 7, CreatedAt: &createdAt}},
+		Subscriptions: []model.GraphEdge{{Source: 12, Target: 7}},
+		Blocks:        []model.GraphEdge{{Source: 7, Target: 12}},
+	}
+	testCases := []struct {
+		name        string
+		expectedErr error
+		preparePath string
+		mockDb      *sql.DB
+	}{
+		{
+			name:        "Import graph failed with error",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "The database is not empty",
+			expectedErr: model.ErrGraphDatabaseNotEmpty,
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:        "Import graph success",
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/emptydata",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			graphRepo := GraphRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			err := graphRepo.ImportGraph(context.Background(), graph)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := exportGraph(graphRepo)
+			require.NoError(t, err)
+			require.Len(t, result.Users, 3)
+			require.Equal(t, 1, result.Users[0].ID)
+			require.Equal(t, "abc@xyz.com", result.Users[0].Email)
+			require.Equal(t, "Andy", result.Users[0].DisplayName)
+			require.Equal(t, model.UserStatusAway, result.Users[0].Status)
+			require.Equal(t, createdAt, result.Users[0].CreatedAt.UTC())
+			require.Equal(t, "xyz@abc.com", result.Users[1].Email)
+			require.Equal(t, 2, result.Users[1].ID)
+			require.Len(t, result.Friends, 1)
+			require.Equal(t, 2, result.Friends[0].Source)
+			require.Equal(t, 1, result.Friends[0].Target)
+			require.Equal(t, createdAt, result.Friends[0].CreatedAt.UTC())
+			require.Equal(t, []model.GraphEdge{{Source: 3, Target: 1}}, result.Subscriptions)
+			require.Equal(t, []model.GraphEdge{{Source: 1, Target: 3}}, result.Blocks)
+		})
+	}
+}
+
+// exportGraph streams the export into JSON and reads it back
+func exportGraph(graphRepo GraphRepo) (*model.Graph, error) {
+	var buffer bytes.Buffer
+	if err := graphRepo.ExportGraph(context.Background(), graphio.NewJSONWriter(&buffer)); err != nil {
+		return nil, err
+	}
+	return graphio.ReadJSON(&buffer)
+}
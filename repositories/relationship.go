@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// Lock modes taken on the two useremails rows of a relationship.
+// Friend and subscription inserts share the rows, a block insert conflicts with both
+// so a block cannot land between their block check and their insert.
+const (
+	lockForRelationship = "for share"
+	lockForBlocking     = "for no key update"
+)
+
+// isUniqueViolation reports whether err comes from a unique constraint of postgres
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
+
+// lockUserPair locks the rows of both users until tx ends, always in ID order to avoid deadlocks
+func lockUserPair(ctx context.Context, tx *sql.Tx, firstID int, secondID int, mode string) error {
+	query := `select id from useremails where id in ($1, $2) order by id ` + mode
+	rows, err := tx.QueryContext(ctx, query, firstID, secondID)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// isBlockedEachOther checks blocks in either direction inside tx
+func isBlockedEachOther(ctx context.Context, tx *sql.Tx, firstID int, secondID int) (bool, error) {
+	query := `select exists(
+			  	select true from blocks
+			  	where (requestorid = $1 and targetid = $2)
+			  	   or (requestorid = $2 and targetid = $1)
+			  )`
+	var blocked bool
+	err := tx.QueryRowContext(ctx, query, firstID, secondID).Scan(&blocked)
+	return blocked, err
+}
+
+// lockUsers locks the rows of all users until tx ends, always in ID order to avoid deadlocks
+func lockUsers(ctx context.Context, tx *sql.Tx, userIDs []int, mode string) error {
+	query := `select id from useremails where id = any($1) order by id ` + mode
+	rows, err := tx.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// relationshipBatch describes how a batch of relationships is stored in one table
+type relationshipBatch struct {
+	// insertQuery inserts unnest($1, $2) with one statement, skipping existing rows and returning the inserted IDs
+	insertQuery  string
+	lockMode     string
+	checkBlocked bool
+	// unordered tells a pair and its reverse are the same relationship
+	unordered  bool
+	existedErr error
+}
+
+// create inserts the pairs in one transaction and returns the outcome of each of them:
+// nil when inserted, model.ErrBlockedEachOther or the existedErr of the batch otherwise.
+// A pair repeated in the batch is inserted once, the repeats end up as existing.
+func (_self relationshipBatch) create(ctx context.Context, db *sql.DB, pairs [][2]int) ([]error, error) {
+	results := make([]error, len(pairs))
+	if len(pairs) == 0 {
+		return results, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userIDs := make([]int, 0, 2*len(pairs))
+	for _, pair := range pairs {
+		userIDs = append(userIDs, pair[0], pair[1])
+	}
+	if err := lockUsers(ctx, tx, userIDs, _self.lockMode); err != nil {
+		return nil, err
+	}
+
+	blocked := make(map[int]bool)
+	if _self.checkBlocked {
+		if blocked, err = blockedPairs(ctx, tx, pairs); err != nil {
+			return nil, err
+		}
+	}
+
+	//Keep the first position of each pair to insert
+	positions := make(map[[2]int]int)
+	firstIDs := make([]int, 0, len(pairs))
+	secondIDs := make([]int, 0, len(pairs))
+	for i, pair := range pairs {
+		if blocked[i] {
+			results[i] = model.ErrBlockedEachOther
+			continue
+		}
+		key := _self.key(pair[0], pair[1])
+		if _, ok := positions[key]; ok {
+			results[i] = _self.existedErr
+			continue
+		}
+		positions[key] = i
+		firstIDs = append(firstIDs, pair[0])
+		secondIDs = append(secondIDs, pair[1])
+	}
+	if len(positions) == 0 {
+		return results, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, _self.insertQuery, pq.Array(firstIDs), pq.Array(secondIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inserted := make(map[[2]int]bool)
+	for rows.Next() {
+		var firstID, secondID int
+		if err := rows.Scan(&firstID, &secondID); err != nil {
+			return nil, err
+		}
+		inserted[_self.key(firstID, secondID)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for key, i := range positions {
+		if !inserted[key] {
+			results[i] = _self.existedErr
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// key identifies the relationship of two users within the batch
+func (_self relationshipBatch) key(firstID int, secondID int) [2]int {
+	if _self.unordered && firstID > secondID {
+		return [2]int{secondID, firstID}
+	}
+	return [2]int{firstID, secondID}
+}
+
+// blockedPairs returns the positions of the pairs whose users block each other, checked inside tx with one query
+func blockedPairs(ctx context.Context, tx *sql.Tx, pairs [][2]int) (map[int]bool, error) {
+	firstIDs := make([]int, len(pairs))
+	secondIDs := make([]int, len(pairs))
+	for i, pair := range pairs {
+		firstIDs[i], secondIDs[i] = pair[0], pair[1]
+	}
+
+	query := `select p.position - 1
+			  from unnest($1::int8[], $2::int8[]) with ordinality as p(firstid, secondid, position)
+			  where exists(
+			  	select true from blocks b
+			  	where (b.requestorid = p.firstid and b.targetid = p.secondid)
+			  	   or (b.requestorid = p.secondid and b.targetid = p.firstid)
+			  )`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(firstIDs), pq.Array(secondIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := make(map[int]bool)
+	for rows.Next() {
+		var position int
+		if err := rows.Scan(&position); err != nil {
+			return nil, err
+		}
+		blocked[position] = true
+	}
+	return blocked, rows.Err()
+}
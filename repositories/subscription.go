@@ -1,31 +1,87 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 
 	"S3_FriendManagement_ThinhNguyen/model"
 )
 
 type ISubscriptionRepo interface {
-	CreateSubscription(*model.SubscriptionRepoInput) error
-	IsExistedSubscription(int, int) (bool, error)
-	IsBlockedByOtherEmail(int, int) (bool, error)
+	CreateSubscription(context.Context, *model.SubscriptionRepoInput) error
+	CreateSubscriptions(context.Context, []model.SubscriptionRepoInput) ([]error, error)
+	DeleteSubscription(context.Context, *model.SubscriptionRepoInput) error
+	IsExistedSubscription(context.Context, int, int) (bool, error)
+	IsBlockedByOtherEmail(context.Context, int, int) (bool, error)
+	GetSubscriberList(context.Context, int) ([]int, error)
+	GetFollowingList(context.Context, int) ([]int, error)
 }
 
 type SubscriptionRepo struct {
 	Db *sql.DB
 }
 
-func (_self SubscriptionRepo) CreateSubscription(model *model.SubscriptionRepoInput) error {
+// CreateSubscription checks blocking and inserts the subscription in one transaction.
+// It returns model.ErrBlockedEachOther or model.ErrSubscriptionExisted when another request got there first.
+func (_self SubscriptionRepo) CreateSubscription(ctx context.Context, subscription *model.SubscriptionRepoInput) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockUserPair(ctx, tx, subscription.Requestor, subscription.Target, lockForRelationship); err != nil {
+		return err
+	}
+
+	blocked, err := isBlockedEachOther(ctx, tx, subscription.Requestor, subscription.Target)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return model.ErrBlockedEachOther
+	}
+
 	query := `insert into subscriptions(requestorid, targetid) VALUES ($1, $2)`
-	_, err := _self.Db.Exec(query, model.Requestor, model.Target)
+	if _, err := tx.ExecContext(ctx, query, subscription.Requestor, subscription.Target); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrSubscriptionExisted
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+var subscriptionBatch = relationshipBatch{
+	insertQuery: `insert into subscriptions(requestorid, targetid)
+				  select * from unnest($1::int8[], $2::int8[])
+				  on conflict do nothing
+				  returning requestorid, targetid`,
+	lockMode:     lockForRelationship,
+	checkBlocked: true,
+	existedErr:   model.ErrSubscriptionExisted,
+}
+
+// CreateSubscriptions inserts many subscriptions in one transaction, checking blocking for all of them with one query.
+// It returns the outcome of each subscription: nil, model.ErrBlockedEachOther or model.ErrSubscriptionExisted.
+func (_self SubscriptionRepo) CreateSubscriptions(ctx context.Context, inputs []model.SubscriptionRepoInput) ([]error, error) {
+	pairs := make([][2]int, len(inputs))
+	for i, input := range inputs {
+		pairs[i] = [2]int{input.Requestor, input.Target}
+	}
+	return subscriptionBatch.create(ctx, _self.Db, pairs)
+}
+
+func (_self SubscriptionRepo) DeleteSubscription(ctx context.Context, model *model.SubscriptionRepoInput) error {
+	query := `delete from subscriptions where requestorid = $1 and targetid = $2`
+	_, err := _self.Db.ExecContext(ctx, query, model.Requestor, model.Target)
 	return err
 }
 
-func (_self SubscriptionRepo) IsExistedSubscription(requestorID int, targetID int) (bool, error) {
+func (_self SubscriptionRepo) IsExistedSubscription(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	query := `select exists(select true from subscriptions where requestorid=$1 AND targetid=$2)`
 	var exist bool
-	err := _self.Db.QueryRow(query, requestorID, targetID).Scan(&exist)
+	err := _self.Db.QueryRowContext(ctx, query, requestorID, targetID).Scan(&exist)
 	if err != nil {
 		return true, err
 	}
@@ -35,10 +91,10 @@ func (_self SubscriptionRepo) IsExistedSubscription(requestorID int, targetID in
 	return false, nil
 }
 
-func (_self SubscriptionRepo) IsBlockedByOtherEmail(requestorID int, targetID int) (bool, error) {
+func (_self SubscriptionRepo) IsBlockedByOtherEmail(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	query := `select exists(select true from blocks where (requestorid=$1 and targetid=$2) or (requestorid=$2 and targetid=$1))`
 	var isBlock bool
-	err := _self.Db.QueryRow(query, requestorID, targetID).Scan(&isBlock)
+	err := _self.Db.QueryRowContext(ctx, query, requestorID, targetID).Scan(&isBlock)
 	if err != nil {
 		return true, err
 	}
@@ -47,3 +103,41 @@ func (_self SubscriptionRepo) IsBlockedByOtherEmail(requestorID int, targetID in
 	}
 	return false, nil
 }
+
+func (_self SubscriptionRepo) GetSubscriberList(ctx context.Context, userID int) ([]int, error) {
+	query := `select requestorid from subscriptions where targetid=$1`
+	subscribers := make([]int, 0)
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		subscribers = append(subscribers, id)
+	}
+	return subscribers, rows.Err()
+}
+
+func (_self SubscriptionRepo) GetFollowingList(ctx context.Context, userID int) ([]int, error) {
+	query := `select targetid from subscriptions where requestorid=$1`
+	targets := make([]int, 0)
+	rows, err := _self.Db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		targets = append(targets, id)
+	}
+	return targets, rows.Err()
+}
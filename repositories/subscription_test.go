@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -29,12 +30,12 @@ func TestSubscriptionRepo_CreateSubscription(t *testing.T) {
 			mockDB:      testhelpers.ConnectDBFailed(),
 		},
 		{
-			name: "Create subscription success",
+			name: "Email addresses blocked each other",
 			input: &model.SubscriptionRepoInput{
 				Requestor: 1,
 				Target:    2,
 			},
-			expectedErr: nil,
+			expectedErr: model.ErrBlockedEachOther,
 			preparePath: "../testhelpers/preparedata/datafortest",
 			mockDB:      testhelpers.ConnectDB(),
 		},
@@ -50,7 +51,7 @@ func TestSubscriptionRepo_CreateSubscription(t *testing.T) {
 			}
 
 			// When
-			err := subscriptionRepo.CreateSubscription(testCase.input)
+			err := subscriptionRepo.CreateSubscription(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -62,6 +63,31 @@ func TestSubscriptionRepo_CreateSubscription(t *testing.T) {
 	}
 }
 
+func TestSubscriptionRepo_CreateSubscriptions(t *testing.T) {
+	// Given
+	db := testhelpers.ConnectDB()
+	testhelpers.PrepareDBForTest(db, "../testhelpers/preparedata/datafortest")
+
+	var thirdID int
+	require.NoError(t, db.QueryRow(`insert into useremails(email) values ('third@batch.com') returning id`).Scan(&thirdID))
+
+	subscriptionRepo := SubscriptionRepo{
+		Db: db,
+	}
+
+	// When
+	results, err := subscriptionRepo.CreateSubscriptions(context.Background(), []model.SubscriptionRepoInput{
+		{Requestor: 1, Target: 2},
+		{Requestor: 1, Target: thirdID},
+		{Requestor: thirdID, Target: 1},
+		{Requestor: 1, Target: thirdID},
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, []error{model.ErrBlockedEachOther, nil, nil, model.ErrSubscriptionExisted}, results)
+}
+
 func TestSubscriptionRepo_IsExistedSubscription(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -107,7 +133,7 @@ func TestSubscriptionRepo_IsExistedSubscription(t *testing.T) {
 			}
 
 			// When
-			result, err := subscriptionRepo.IsExistedSubscription(testCase.input[0], testCase.input[1])
+			result, err := subscriptionRepo.IsExistedSubscription(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -165,7 +191,162 @@ func TestSubscriptionRepo_IsBlockedByOtherEmail(t *testing.T) {
 			}
 
 			// When
-			result, err := subscriptionRepo.IsBlockedByOtherEmail(testCase.input[0], testCase.input[1])
+			result, err := subscriptionRepo.IsBlockedByOtherEmail(context.Background(), testCase.input[0], testCase.input[1])
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestSubscriptionRepo_GetSubscriberList(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          int
+		expectedResult []int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get failed with error",
+			input:          1,
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Get success",
+			input:          1,
+			expectedResult: []int{2},
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			subscriptionRepo := SubscriptionRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := subscriptionRepo.GetSubscriberList(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestSubscriptionRepo_DeleteSubscription(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       *model.SubscriptionRepoInput
+		expectedErr error
+		preparePath string
+		mockDB      *sql.DB
+	}{
+		{
+			name: "Delete failed with error",
+			input: &model.SubscriptionRepoInput{
+				Requestor: 2,
+				Target:    1,
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDB:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Delete success",
+			input: &model.SubscriptionRepoInput{
+				Requestor: 2,
+				Target:    1,
+			},
+			expectedErr: nil,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDB, testCase.preparePath)
+
+			subscriptionRepo := SubscriptionRepo{
+				Db: testCase.mockDB,
+			}
+
+			// When
+			err := subscriptionRepo.DeleteSubscription(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				existed, err := subscriptionRepo.IsExistedSubscription(context.Background(), testCase.input.Requestor, testCase.input.Target)
+				require.NoError(t, err)
+				require.False(t, existed)
+			}
+		})
+	}
+}
+
+func TestSubscriptionRepo_GetFollowingList(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          int
+		expectedResult []int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "Get failed with error",
+			input:          2,
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath:    "",
+			mockDb:         testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Get success",
+			input:          2,
+			expectedResult: []int{1},
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			subscriptionRepo := SubscriptionRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := subscriptionRepo.GetFollowingList(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
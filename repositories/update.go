@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/utils"
+	"github.com/lib/pq"
+)
+
+type IUpdateRepo interface {
+	CreateUpdate(ctx context.Context, input *model.UpdateRepoInput) (int, error)
+	GetFeedByID(ctx context.Context, input *model.FeedRepoInput) ([]model.FeedItem, error)
+	MarkUpdatesAsRead(ctx context.Context, userID int, updateIDs []int) error
+}
+
+type UpdateRepo struct {
+	Db *sql.DB
+}
+
+// CreateUpdate stores the update, one delivery row per registered recipient and the webhook outbox entries
+// of the update in one transaction
+func (_self UpdateRepo) CreateUpdate(ctx context.Context, input *model.UpdateRepoInput) (int, error) {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var updateID int
+	query := `insert into updates(senderid, text, mentions) values ($1, $2, $3) returning id`
+	if err := tx.QueryRowContext(ctx, query, input.SenderID, input.Text, pq.Array(input.Mentions)).Scan(&updateID); err != nil {
+		return 0, err
+	}
+
+	query = `insert into updatedeliveries(updateid, recipientid)
+			 select $1, id from useremails where email = any($2)`
+	if _, err := tx.ExecContext(ctx, query, updateID, pq.Array(utils.NormalizeEmails(input.Recipients))); err != nil {
+		return 0, err
+	}
+
+	webhooks, err := getWebhooksForRecipients(ctx, tx, input.Recipients)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := model.NewWebhookOutboxEntries(model.WebhookUpdateInput{
+		UpdateID:   updateID,
+		Sender:     input.Sender,
+		Text:       input.Text,
+		Recipients: input.Recipients,
+	}, webhooks)
+	if err != nil {
+		return 0, err
+	}
+	if err := createOutboxEntries(ctx, tx, entries); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return updateID, nil
+}
+
+// GetFeedByID returns the updates delivered to the user newest-first, leaving out senders the user blocks
+func (_self UpdateRepo) GetFeedByID(ctx context.Context, input *model.FeedRepoInput) ([]model.FeedItem, error) {
+	query := `select u.id, ue.email, u.text, u.createdat, d.readat is not null
+			  from updatedeliveries d
+			  join updates u on u.id = d.updateid
+			  join useremails ue on ue.id = u.senderid
+			  where d.recipientid = $1
+			    and not exists(
+			    	select true from blocks b
+			    	where b.requestorid = $1 and b.targetid = u.senderid
+			    )`
+	args := []interface{}{input.UserID, input.Limit}
+	if input.Cursor != nil {
+		query += ` and (u.createdat, u.id) < ($3, $4)`
+		args = append(args, input.Cursor.CreatedAt, input.Cursor.ID)
+	}
+	query += ` order by u.createdat desc, u.id desc limit $2`
+
+	rows, err := _self.Db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feed := make([]model.FeedItem, 0)
+	for rows.Next() {
+		var item model.FeedItem
+		if err := rows.Scan(&item.UpdateID, &item.Sender, &item.Text, &item.CreatedAt, &item.Read); err != nil {
+			return nil, err
+		}
+		feed = append(feed, item)
+	}
+	return feed, rows.Err()
+}
+
+// MarkUpdatesAsRead sets the read time of the user's deliveries which have not been read yet
+func (_self UpdateRepo) MarkUpdatesAsRead(ctx context.Context, userID int, updateIDs []int) error {
+	query := `update updatedeliveries set readat = now()
+			  where recipientid = $1 and updateid = any($2) and readat is null`
+	_, err := _self.Db.ExecContext(ctx, query, userID, pq.Array(updateIDs))
+	return err
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRepo_CreateUpdate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *model.UpdateRepoInput
+		expectedResult int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name: "Create update failed with error",
+			input: &model.UpdateRepoInput{
+				SenderID:   1,
+				Text:       "hello",
+				Mentions:   []string{},
+				Recipients: []string{"xyz@abc.com"},
+			},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name: "Create update success",
+			input: &model.UpdateRepoInput{
+				SenderID:   2,
+				Text:       "hello abc@xyz.com",
+				Mentions:   []string{"abc@xyz.com"},
+				Recipients: []string{"abc@xyz.com", "unknown@xyz.com"},
+			},
+			expectedResult: 3,
+			expectedErr:    nil,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			updateRepo := UpdateRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := updateRepo.CreateUpdate(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+
+				var deliveries int
+				err := testCase.mockDb.QueryRow(`select count(*) from updatedeliveries where updateid = $1`, result).Scan(&deliveries)
+				require.NoError(t, err)
+				require.Equal(t, 1, deliveries)
+			}
+		})
+	}
+}
+
+func TestUpdateRepo_GetFeedByID(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *model.FeedRepoInput
+		expectedResult []model.FeedItem
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:        "Get feed failed with error",
+			input:       &model.FeedRepoInput{UserID: 2, Limit: 10},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:  "Get feed success",
+			input: &model.FeedRepoInput{UserID: 2, Limit: 10},
+			expectedResult: []model.FeedItem{
+				{UpdateID: 1, Sender: "abc@xyz.com", Text: "hello xyz@abc.com"},
+			},
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDb:      testhelpers.ConnectDB(),
+		},
+		{
+			name:           "Updates from blocked senders are left out",
+			input:          &model.FeedRepoInput{UserID: 1, Limit: 10},
+			expectedResult: []model.FeedItem{},
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			updateRepo := UpdateRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := updateRepo.GetFeedByID(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Len(t, result, len(testCase.expectedResult))
+				for i := range result {
+					result[i].CreatedAt = testCase.expectedResult[i].CreatedAt
+				}
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUpdateRepo_MarkUpdatesAsRead(t *testing.T) {
+	testCases := []struct {
+		name        string
+		userID      int
+		updateIDs   []int
+		expectedErr error
+		preparePath string
+		mockDb      *sql.DB
+	}{
+		{
+			name:        "Mark as read failed with error",
+			userID:      2,
+			updateIDs:   []int{1},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "Mark as read success",
+			userID:      2,
+			updateIDs:   []int{1},
+			expectedErr: nil,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDb:      testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			updateRepo := UpdateRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			err := updateRepo.MarkUpdatesAsRead(context.Background(), testCase.userID, testCase.updateIDs)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+
+				feed, err := updateRepo.GetFeedByID(context.Background(), &model.FeedRepoInput{UserID: testCase.userID, Limit: 10})
+				require.NoError(t, err)
+				require.Len(t, feed, 1)
+				require.True(t, feed[0].Read)
+			}
+		})
+	}
+}
@@ -1,36 +1,137 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"strings"
+	"time"
 
 	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/utils"
+	"github.com/lib/pq"
 )
 
 type IUserRepo interface {
-	CreateUser(*model.UserRepoInput) error
-	IsExistedUser(string) (bool, error)
-	GetUserIDByEmail(string) (int, error)
-	GetUserIDsByEmails(emails []string) ([]int, error)
-	GetEmailListByIDs(userIDs []int) ([]string, error)
-	CheckInvalidEmails([]string) ([]string, error)
+	CreateUser(context.Context, *model.UserRepoInput) error
+	CreateUsers(ctx context.Context, emails []string) (int, error)
+	IsExistedUser(context.Context, string) (bool, error)
+	GetUserIDByEmail(context.Context, string) (int, error)
+	GetUserIDsByEmails(ctx context.Context, emails []string) ([]int, error)
+	GetUserIDMapByEmails(ctx context.Context, emails []string) (map[string]int, error)
+	GetEmailListByIDs(ctx context.Context, userIDs []int) ([]string, error)
+	GetEmailMapByIDs(ctx context.Context, userIDs []int) (map[int]string, error)
+	CheckInvalidEmails(context.Context, []string) ([]string, error)
+	GetUserProfile(ctx context.Context, email string) (*model.UserProfile, error)
+	GetUserProfilesByEmails(ctx context.Context, emails []string) ([]model.UserProfile, error)
+	UpdateUserProfile(ctx context.Context, input *model.UserProfileRepoInput) (*model.UserProfile, error)
+	SearchUsers(ctx context.Context, search string, limit int) ([]model.UserProfile, error)
+	ChangeEmail(ctx context.Context, input *model.ChangeEmailRepoInput) error
+	DeleteUser(ctx context.Context, email string) (bool, error)
+	ExportUser(ctx context.Context, email string) (*model.UserExport, error)
 }
 
+// userProfileColumns are the useremails columns read by scanUserProfile, in its order
+const userProfileColumns = `ue.email, ue.displayname, ue.avatarurl, ue.bio, ue.status, ue.createdat`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUserProfile(row rowScanner) (*model.UserProfile, error) {
+	profile := &model.UserProfile{}
+	err := row.Scan(&profile.Email, &profile.DisplayName, &profile.AvatarURL, &profile.Bio, &profile.Status, &profile.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func scanUserProfiles(rows *sql.Rows) ([]model.UserProfile, error) {
+	profiles := make([]model.UserProfile, 0)
+	for rows.Next() {
+		profile, err := scanUserProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+	return profiles, rows.Err()
+}
+
+// EmailGracePeriod is how long GetUserIDByEmail still finds a user by an address it changed away from.
+// The address cannot be taken by another user meanwhile. Zero turns the resolution of previous addresses off.
+var EmailGracePeriod time.Duration
+
+// emailReservedQuery is true when the address e.email was changed away from within the grace period $2
+const emailReservedQuery = `exists(
+			  	select true from emailhistory eh
+			  	where eh.email = e.email and eh.changedat > now() - make_interval(secs => $2)
+			  )`
+
 type UserRepo struct {
 	Db *sql.DB
 }
 
-func (_self UserRepo) CreateUser(userRepoInput *model.UserRepoInput) error {
-	query := `insert into useremails(email) values ($1)`
-	_, err := _self.Db.Exec(query, userRepoInput.Email)
-	return err
+// CreateUser returns model.ErrUserExisted when the email exists or still finds its previous user
+func (_self UserRepo) CreateUser(ctx context.Context, userRepoInput *model.UserRepoInput) error {
+	query := `insert into useremails(email)
+			  select e.email from (select $1::varchar as email) e
+			  where not ` + emailReservedQuery
+	result, err := _self.Db.ExecContext(ctx, query, utils.NormalizeEmail(userRepoInput.Email), EmailGracePeriod.Seconds())
+	if isUniqueViolation(err) {
+		return model.ErrUserExisted
+	}
+	if err != nil {
+		return err
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if created == 0 {
+		return model.ErrUserExisted
+	}
+	return nil
 }
 
-func (_self UserRepo) GetUserIDByEmail(email string) (int, error) {
+// CreateUsers inserts the emails with one statement, so they are inserted or not as a whole.
+// The emails which already exist or still find their previous user are skipped, it returns how many users were created.
+func (_self UserRepo) CreateUsers(ctx context.Context, emails []string) (int, error) {
+	if len(emails) == 0 {
+		return 0, nil
+	}
+
+	query := `insert into useremails(email)
+			  select e.email from unnest($1::varchar[]) as e(email)
+			  where not ` + emailReservedQuery + `
+			  on conflict do nothing`
+	result, err := _self.Db.ExecContext(ctx, query, pq.Array(utils.NormalizeEmails(emails)), EmailGracePeriod.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	created, err := result.RowsAffected()
+	return int(created), err
+}
+
+// GetUserIDByEmail returns the ID of the user holding email, or of the user who held it within EmailGracePeriod.
+// It returns 0 when there is none.
+func (_self UserRepo) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
 	query := `select id from useremails where email=$1`
+	args := []interface{}{utils.NormalizeEmail(email)}
+	if EmailGracePeriod > 0 {
+		//The current holder of an address wins over a user who held it before
+		query = `select userid from (
+				 	select id as userid, 0 as priority, now() as changedat from useremails where email = $1
+				 	union all
+				 	select userid, 1, changedat from emailhistory
+				 	where email = $1 and changedat > now() - make_interval(secs => $2)
+				 ) candidates
+				 order by priority, changedat desc
+				 limit 1`
+		args = append(args, EmailGracePeriod.Seconds())
+	}
 	var userID int
-	err := _self.Db.QueryRow(query, email).Scan(&userID)
+	err := _self.Db.QueryRowContext(ctx, query, args...).Scan(&userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return 0, nil
@@ -40,10 +141,10 @@ func (_self UserRepo) GetUserIDByEmail(email string) (int, error) {
 	return userID, nil
 }
 
-func (_self UserRepo) IsExistedUser(email string) (bool, error) {
+func (_self UserRepo) IsExistedUser(ctx context.Context, email string) (bool, error) {
 	query := `select exists (select true from useremails where email=$1)`
 	var existed bool
-	err := _self.Db.QueryRow(query, email).Scan(&existed)
+	err := _self.Db.QueryRowContext(ctx, query, utils.NormalizeEmail(email)).Scan(&existed)
 	if err != nil {
 		return false, err
 	}
@@ -53,20 +154,17 @@ func (_self UserRepo) IsExistedUser(email string) (bool, error) {
 	return false, nil
 }
 
-func (_self UserRepo) GetEmailListByIDs(userIDs []int) ([]string, error) {
+func (_self UserRepo) GetEmailListByIDs(ctx context.Context, userIDs []int) ([]string, error) {
 	if len(userIDs) == 0 {
 		return []string{}, nil
 	}
 
-	IDList := make([]string, len(userIDs))
-	for i, id := range userIDs {
-		IDList[i] = fmt.Sprintf("%v", id)
-	}
-	query := fmt.Sprintf(`select email from useremails where id in (%v)`, strings.Join(IDList, ","))
-	rows, err := _self.Db.Query(query)
+	query := `select email from useremails where id = any($1) order by email`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(userIDs))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	emailList := make([]string, 0)
 	for rows.Next() {
@@ -76,23 +174,45 @@ func (_self UserRepo) GetEmailListByIDs(userIDs []int) ([]string, error) {
 		}
 		emailList = append(emailList, email)
 	}
-	return emailList, nil
+	return emailList, rows.Err()
+}
+
+// GetEmailMapByIDs returns the email of each existing UserID, keyed by UserID
+func (_self UserRepo) GetEmailMapByIDs(ctx context.Context, userIDs []int) (map[int]string, error) {
+	emailMap := make(map[int]string)
+	if len(userIDs) == 0 {
+		return emailMap, nil
+	}
+
+	query := `select id, email from useremails where id = any($1)`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var email string
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		emailMap[id] = email
+	}
+	return emailMap, rows.Err()
 }
 
-func (_self UserRepo) GetUserIDsByEmails(emails []string) ([]int, error) {
+func (_self UserRepo) GetUserIDsByEmails(ctx context.Context, emails []string) ([]int, error) {
 	if len(emails) == 0 {
 		return []int{}, nil
 	}
 
-	emailList := make([]string, len(emails))
-	for i, email := range emails {
-		emailList[i] = fmt.Sprintf("%v", email)
-	}
-	query := fmt.Sprintf(`select ID from useremails where email in ('%v')`, strings.Join(emailList, "','"))
-	rows, err := _self.Db.Query(query)
+	query := `select id from useremails where email = any($1)`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(utils.NormalizeEmails(emails)))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	IDList := make([]int, 0)
 	for rows.Next() {
@@ -102,30 +222,64 @@ func (_self UserRepo) GetUserIDsByEmails(emails []string) ([]int, error) {
 		}
 		IDList = append(IDList, id)
 	}
-	return IDList, nil
+	return IDList, rows.Err()
 }
 
-func (_self UserRepo) CheckInvalidEmails(emails []string) ([]string, error) {
+// GetUserIDMapByEmails returns the UserID of each existing email, keyed by normalized email.
+// Like GetUserIDByEmail, an address changed away from within EmailGracePeriod finds its previous user.
+func (_self UserRepo) GetUserIDMapByEmails(ctx context.Context, emails []string) (map[string]int, error) {
+	IDMap := make(map[string]int)
 	if len(emails) == 0 {
-		return []string{}, nil
+		return IDMap, nil
+	}
+
+	query := `select coalesce(ue.id, h.userid), e.email
+			  from unnest($1::varchar[]) as e(email)
+			  left join useremails ue on ue.email = e.email
+			  left join lateral (
+			  	select eh.userid from emailhistory eh
+			  	where eh.email = e.email and eh.changedat > now() - make_interval(secs => $2)
+			  	order by eh.changedat desc
+			  	limit 1
+			  ) h on true
+			  where ue.id is not null or h.userid is not null`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(utils.NormalizeEmails(emails)), EmailGracePeriod.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var email string
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		IDMap[email] = id
 	}
-	emailList := make([]string, len(emails))
-	for i, email := range emails {
-		emailList[i] = fmt.Sprintf("%v", email)
+	return IDMap, rows.Err()
+}
+
+// CheckInvalidEmails returns the normalized emails which do not exist and do not find a previous user,
+// in the order they were given
+func (_self UserRepo) CheckInvalidEmails(ctx context.Context, emails []string) ([]string, error) {
+	if len(emails) == 0 {
+		return []string{}, nil
 	}
-	query := fmt.Sprintf(`select email
-								from (
-							 		values ('%v')
-								)as e(email)
-								where not exists(
-									select 1
-									from useremails ue
-									where ue.email = e.email
-								)`, strings.Join(emailList, "'),('"))
-	rows, err := _self.Db.Query(query)
+	query := `select e.email
+			  from unnest($1::varchar[]) with ordinality as e(email, position)
+			  where not exists(
+			  	select 1
+			  	from useremails ue
+			  	where ue.email = e.email
+			  )
+			    and not ` + emailReservedQuery + `
+			  order by e.position`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(utils.NormalizeEmails(emails)), EmailGracePeriod.Seconds())
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	Emails := make([]string, 0)
 	for rows.Next() {
@@ -135,5 +289,385 @@ func (_self UserRepo) CheckInvalidEmails(emails []string) ([]string, error) {
 		}
 		Emails = append(Emails, email)
 	}
-	return Emails, nil
+	return Emails, rows.Err()
+}
+
+// GetUserProfile returns the profile of email, or nil when the email does not exist
+func (_self UserRepo) GetUserProfile(ctx context.Context, email string) (*model.UserProfile, error) {
+	query := `select ` + userProfileColumns + ` from useremails ue where ue.email = $1`
+	profile, err := scanUserProfile(_self.Db.QueryRowContext(ctx, query, utils.NormalizeEmail(email)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return profile, err
+}
+
+// GetUserProfilesByEmails returns the profiles of the existing emails, in the order they were given
+func (_self UserRepo) GetUserProfilesByEmails(ctx context.Context, emails []string) ([]model.UserProfile, error) {
+	if len(emails) == 0 {
+		return []model.UserProfile{}, nil
+	}
+	query := `select ` + userProfileColumns + `
+			  from unnest($1::varchar[]) with ordinality as e(email, position)
+			  join useremails ue on ue.email = e.email
+			  order by e.position`
+	rows, err := _self.Db.QueryContext(ctx, query, pq.Array(utils.NormalizeEmails(emails)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUserProfiles(rows)
+}
+
+// UpdateUserProfile sets the fields of input which are not nil and returns the new profile,
+// or nil when the email does not exist
+func (_self UserRepo) UpdateUserProfile(ctx context.Context, input *model.UserProfileRepoInput) (*model.UserProfile, error) {
+	query := `update useremails ue
+			  set displayname = coalesce($2, ue.displayname),
+			      avatarurl = coalesce($3, ue.avatarurl),
+			      bio = coalesce($4, ue.bio),
+			      status = coalesce($5, ue.status)
+			  where ue.email = $1
+			  returning ` + userProfileColumns
+	row := _self.Db.QueryRowContext(ctx, query, utils.NormalizeEmail(input.Email), input.DisplayName, input.AvatarURL, input.Bio, input.Status)
+	profile, err := scanUserProfile(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return profile, err
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUsers returns up to limit users whose email or display name starts with search, ignoring case
+func (_self UserRepo) SearchUsers(ctx context.Context, search string, limit int) ([]model.UserProfile, error) {
+	query := `select ` + userProfileColumns + `
+			  from useremails ue
+			  where ue.email like $1 or lower(ue.displayname) like $1
+			  order by ue.email
+			  limit $2`
+	pattern := likeEscaper.Replace(strings.ToLower(search)) + "%"
+	rows, err := _self.Db.QueryContext(ctx, query, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUserProfiles(rows)
+}
+
+// ChangeEmail moves the user of OldEmail to NewEmail, keeping its ID and so its relationships,
+// and records OldEmail in the history of the user. NewEmail cannot be an address another user
+// changed away from within EmailGracePeriod.
+func (_self UserRepo) ChangeEmail(ctx context.Context, input *model.ChangeEmailRepoInput) error {
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	query := `select id from useremails where email = $1 for update`
+	if err := tx.QueryRowContext(ctx, query, utils.NormalizeEmail(input.OldEmail)).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return model.ErrUserNotFound
+		}
+		return err
+	}
+
+	var reserved bool
+	query = `select exists(
+			 	select true from emailhistory
+			 	where email = $1 and userid <> $2 and changedat > now() - make_interval(secs => $3)
+			 )`
+	err = tx.QueryRowContext(ctx, query, utils.NormalizeEmail(input.NewEmail), userID, EmailGracePeriod.Seconds()).Scan(&reserved)
+	if err != nil {
+		return err
+	}
+	if reserved {
+		return model.ErrUserExisted
+	}
+
+	query = `update useremails set email = $2 where id = $1`
+	if _, err := tx.ExecContext(ctx, query, userID, utils.NormalizeEmail(input.NewEmail)); err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrUserExisted
+		}
+		return err
+	}
+
+	query = `insert into emailhistory(userid, email) values ($1, $2)`
+	if _, err := tx.ExecContext(ctx, query, userID, utils.NormalizeEmail(input.OldEmail)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// userDeleteQueries remove everything which refers to the user $1 before the user itself, children first
+var userDeleteQueries = []string{
+	`delete from updatedeliveries
+	 where recipientid = $1 or updateid in (select id from updates where senderid = $1)`,
+	//Deliveries of the updates of the user, to any webhook, and the user among the recipients of the other ones
+	`delete from webhookoutbox
+	 where webhookid in (select id from webhooks where userid = $1)
+	    or (payload::jsonb ->> 'update_id')::int8 in (select id from updates where senderid = $1)`,
+	`update webhookoutbox o
+	 set payload = jsonb_set(o.payload::jsonb, '{recipients}', (
+	     	select coalesce(jsonb_agg(r.email), '[]'::jsonb)
+	     	from jsonb_array_elements(o.payload::jsonb -> 'recipients') as r(email)
+	     	where r.email #>> '{}' <> ue.email
+	     ))::text,
+	     updatedat = now()
+	 from useremails ue
+	 where ue.id = $1 and o.payload::jsonb -> 'recipients' ? ue.email`,
+	`delete from updates where senderid = $1`,
+	`delete from webhooks where userid = $1`,
+	`delete from friendrequests where requestorid = $1 or targetid = $1`,
+	`delete from friends where firstid = $1 or secondid = $1`,
+	`delete from subscriptions where requestorid = $1 or targetid = $1`,
+	`delete from blocks where requestorid = $1 or targetid = $1`,
+	`delete from emailhistory where userid = $1`,
+	`delete from useremails where id = $1`,
+}
+
+// DeleteUser removes the user and all of its data in one transaction. It returns false when the email does not exist.
+func (_self UserRepo) DeleteUser(ctx context.Context, email string) (bool, error) {
+	email = utils.NormalizeEmail(email)
+	tx, err := _self.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	//Lock the user so no relationship can be added to it while it is deleted
+	var userID int
+	query := `select id from useremails where email = $1 for update`
+	if err := tx.QueryRowContext(ctx, query, email).Scan(&userID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, query := range userDeleteQueries {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return false, err
+		}
+	}
+
+	//The user is not mentioned by the updates which are kept any more
+	query = `update updates set mentions = array_remove(mentions, $1::varchar) where $1 = any(mentions)`
+	if _, err := tx.ExecContext(ctx, query, email); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExportUser collects everything stored about the user from one snapshot of the database.
+// It returns nil when the email does not exist.
+func (_self UserRepo) ExportUser(ctx context.Context, email string) (*model.UserExport, error) {
+	tx, err := _self.Db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	export := &model.UserExport{}
+	query := `select ue.id, ` + userProfileColumns + `, now() from useremails ue where ue.email = $1`
+	profile := &export.Profile
+	err = tx.QueryRowContext(ctx, query, utils.NormalizeEmail(email)).Scan(&userID,
+		&profile.Email, &profile.DisplayName, &profile.AvatarURL, &profile.Bio, &profile.Status, &profile.CreatedAt, &export.ExportedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if export.Friends, err = exportFriends(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	query = `select ue.email from subscriptions s join useremails ue on ue.id = s.targetid
+			 where s.requestorid = $1 order by ue.email`
+	if export.Subscriptions, err = exportEmails(ctx, tx, query, userID); err != nil {
+		return nil, err
+	}
+	query = `select ue.email from subscriptions s join useremails ue on ue.id = s.requestorid
+			 where s.targetid = $1 order by ue.email`
+	if export.Subscribers, err = exportEmails(ctx, tx, query, userID); err != nil {
+		return nil, err
+	}
+	query = `select ue.email from blocks b join useremails ue on ue.id = b.targetid
+			 where b.requestorid = $1 order by ue.email`
+	if export.Blocking, err = exportEmails(ctx, tx, query, userID); err != nil {
+		return nil, err
+	}
+	if export.FriendRequests, err = exportFriendRequests(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	if export.Updates, err = exportUpdates(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	if export.ReceivedUpdates, err = exportReceivedUpdates(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	if export.Webhooks, err = exportWebhooks(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	if export.PreviousEmails, err = exportPreviousEmails(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func exportEmails(ctx context.Context, tx *sql.Tx, query string, userID int) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := make([]string, 0)
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+func exportFriends(ctx context.Context, tx *sql.Tx, userID int) ([]model.ExportedFriend, error) {
+	query := `select ue.email, f.createdat
+			  from friends f
+			  join useremails ue on ue.id = case when f.firstid = $1 then f.secondid else f.firstid end
+			  where f.firstid = $1 or f.secondid = $1
+			  order by ue.email`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	friends := make([]model.ExportedFriend, 0)
+	for rows.Next() {
+		var friend model.ExportedFriend
+		if err := rows.Scan(&friend.Email, &friend.CreatedAt); err != nil {
+			return nil, err
+		}
+		friends = append(friends, friend)
+	}
+	return friends, rows.Err()
+}
+
+func exportFriendRequests(ctx context.Context, tx *sql.Tx, userID int) ([]model.ExportedFriendRequest, error) {
+	query := `select requestor.email, target.email, fr.status, fr.createdat, fr.updatedat
+			  from friendrequests fr
+			  join useremails requestor on requestor.id = fr.requestorid
+			  join useremails target on target.id = fr.targetid
+			  where fr.requestorid = $1 or fr.targetid = $1
+			  order by fr.createdat, fr.id`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]model.ExportedFriendRequest, 0)
+	for rows.Next() {
+		var request model.ExportedFriendRequest
+		if err := rows.Scan(&request.Requestor, &request.Target, &request.Status, &request.CreatedAt, &request.UpdatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+func exportUpdates(ctx context.Context, tx *sql.Tx, userID int) ([]model.ExportedUpdate, error) {
+	query := `select id, text, mentions, createdat from updates where senderid = $1 order by createdat, id`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := make([]model.ExportedUpdate, 0)
+	for rows.Next() {
+		update := model.ExportedUpdate{Mentions: []string{}}
+		if err := rows.Scan(&update.ID, &update.Text, pq.Array(&update.Mentions), &update.CreatedAt); err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
+func exportReceivedUpdates(ctx context.Context, tx *sql.Tx, userID int) ([]model.ExportedReceivedUpdate, error) {
+	query := `select u.id, ue.email, u.text, u.createdat, d.readat
+			  from updatedeliveries d
+			  join updates u on u.id = d.updateid
+			  join useremails ue on ue.id = u.senderid
+			  where d.recipientid = $1
+			  order by u.createdat, u.id`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := make([]model.ExportedReceivedUpdate, 0)
+	for rows.Next() {
+		var update model.ExportedReceivedUpdate
+		if err := rows.Scan(&update.ID, &update.Sender, &update.Text, &update.CreatedAt, &update.ReadAt); err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
+func exportWebhooks(ctx context.Context, tx *sql.Tx, userID int) ([]model.ExportedWebhook, error) {
+	query := `select id, url, createdat from webhooks where userid = $1 order by id`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]model.ExportedWebhook, 0)
+	for rows.Next() {
+		var webhook model.ExportedWebhook
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func exportPreviousEmails(ctx context.Context, tx *sql.Tx, userID int) ([]model.ExportedEmailChange, error) {
+	query := `select email, changedat from emailhistory where userid = $1 order by changedat, id`
+	rows, err := tx.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]model.ExportedEmailChange, 0)
+	for rows.Next() {
+		var change model.ExportedEmailChange
+		if err := rows.Scan(&change.Email, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
 }
@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/testhelpers"
@@ -15,6 +17,9 @@ func TestUserRepo_CreateUser(t *testing.T) {
 		name        string
 		input       *model.UserRepoInput
 		expectedErr error
+		gracePeriod time.Duration
+		extraData   string
+		preparePath string
 		mockDB      *sql.DB
 	}{
 		{
@@ -28,9 +33,40 @@ func TestUserRepo_CreateUser(t *testing.T) {
 		{
 			name: "Create user success",
 			input: &model.UserRepoInput{
-				Email: "xyz@abc.com",
+				Email: "new@abc.com",
 			},
 			expectedErr: nil,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+		{
+			name: "Email only differs by case from an existing one",
+			input: &model.UserRepoInput{
+				Email: " XYZ@abc.com",
+			},
+			expectedErr: model.ErrUserExisted,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+		{
+			name: "Email changed away from within the grace period",
+			input: &model.UserRepoInput{
+				Email: "old@abc.com",
+			},
+			gracePeriod: time.Hour,
+			extraData:   `insert into emailhistory(userid, email) values (2, 'old@abc.com');`,
+			expectedErr: model.ErrUserExisted,
+			preparePath: "../testhelpers/preparedata/datafortest",
+			mockDB:      testhelpers.ConnectDB(),
+		},
+		{
+			name: "Email changed away from without grace period",
+			input: &model.UserRepoInput{
+				Email: "old@abc.com",
+			},
+			extraData:   `insert into emailhistory(userid, email) values (2, 'old@abc.com');`,
+			expectedErr: nil,
+			preparePath: "../testhelpers/preparedata/datafortest",
 			mockDB:      testhelpers.ConnectDB(),
 		},
 	}
@@ -39,19 +75,103 @@ func TestUserRepo_CreateUser(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			// Given
 			dbMock := testCase.mockDB
+			if testCase.preparePath != "" {
+				testhelpers.PrepareDBForTest(dbMock, testCase.preparePath)
+			}
+			if testCase.extraData != "" {
+				_, err := dbMock.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+			EmailGracePeriod = testCase.gracePeriod
+			defer func() { EmailGracePeriod = 0 }()
 
 			UserRepo := UserRepo{
 				Db: dbMock,
 			}
 
 			// When
-			err := UserRepo.CreateUser(testCase.input)
+			err := UserRepo.CreateUser(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserRepo_CreateUsers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedResult int
+		expectedErr    error
+		gracePeriod    time.Duration
+		extraData      string
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "No emails",
+			input:          []string{},
+			expectedResult: 0,
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "",
+		},
+		{
+			name:        "Create failed with error",
+			input:       []string{"new@xyz.com"},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+			preparePath: "",
+		},
+		{
+			name:           "Existing emails are skipped",
+			input:          []string{"ABC@xyz.com", "new@xyz.com", "other@xyz.com"},
+			expectedResult: 2,
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Emails changed away from within the grace period are skipped",
+			input:          []string{"old@abc.com", "new@xyz.com"},
+			expectedResult: 1,
+			expectedErr:    nil,
+			gracePeriod:    time.Hour,
+			extraData:      `insert into emailhistory(userid, email) values (2, 'old@abc.com');`,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+			EmailGracePeriod = testCase.gracePeriod
+			defer func() { EmailGracePeriod = 0 }()
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			created, err := userRepo.CreateUsers(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
 				require.EqualError(t, err, testCase.expectedErr.Error())
 			} else {
 				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, created)
 			}
 		})
 	}
@@ -82,6 +202,14 @@ func TestUserRepo_IsExistedUser(t *testing.T) {
 			mockDb:         testhelpers.ConnectDB(),
 			preparePath:    "../testhelpers/preparedata/datafortest",
 		},
+		{
+			name:           "User existed with another case",
+			input:          " ABC@xyz.com",
+			expectedResult: true,
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
 		{
 			name:           "User not exist",
 			input:          "abcd@xyz.com",
@@ -104,7 +232,7 @@ func TestUserRepo_IsExistedUser(t *testing.T) {
 			}
 
 			// When
-			result, err := userRepo.IsExistedUser(testCase.input)
+			result, err := userRepo.IsExistedUser(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -150,6 +278,14 @@ func TestUserRepo_GetUserIDByEmail(t *testing.T) {
 			mockDb:         testhelpers.ConnectDB(),
 			preparePath:    "../testhelpers/preparedata/datafortest",
 		},
+		{
+			name:           "Get UserID by email with another case",
+			input:          "Abc@XYZ.com",
+			expectedResult: 1,
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -162,7 +298,7 @@ func TestUserRepo_GetUserIDByEmail(t *testing.T) {
 			}
 
 			// When
-			result, err := userRepo.GetUserIDByEmail(testCase.input)
+			result, err := userRepo.GetUserIDByEmail(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -220,7 +356,7 @@ func TestUserRepo_GetEmailListByIDs(t *testing.T) {
 			}
 
 			// When
-			result, err := userRepo.GetEmailListByIDs(testCase.input)
+			result, err := userRepo.GetEmailListByIDs(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -233,6 +369,64 @@ func TestUserRepo_GetEmailListByIDs(t *testing.T) {
 	}
 }
 
+func TestUserRepo_GetEmailMapByIDs(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []int
+		expectedResult map[int]string
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "No data UserIDs input",
+			input:          []int{},
+			expectedResult: map[int]string{},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "",
+		},
+		{
+			name:           "Failed with error",
+			input:          []int{1},
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:         testhelpers.ConnectDBFailed(),
+			preparePath:    "",
+		},
+		{
+			name:           "Get email map from UserID list success",
+			input:          []int{1, 2, 99},
+			expectedResult: map[int]string{1: "abc@xyz.com", 2: "xyz@abc.com"},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.GetEmailMapByIDs(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
 func TestUserRepo_GetUserIDsByEmails(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -266,6 +460,14 @@ func TestUserRepo_GetUserIDsByEmails(t *testing.T) {
 			mockDb:         testhelpers.ConnectDB(),
 			preparePath:    "../testhelpers/preparedata/datafortest",
 		},
+		{
+			name:           "Injected email is only compared as a value",
+			input:          []string{"abc@xyz.com') or ('1'='1"},
+			expectedResult: []int{},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -278,7 +480,7 @@ func TestUserRepo_GetUserIDsByEmails(t *testing.T) {
 			}
 
 			// When
-			result, err := userRepo.GetUserIDsByEmails(testCase.input)
+			result, err := userRepo.GetUserIDsByEmails(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -290,3 +492,664 @@ func TestUserRepo_GetUserIDsByEmails(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepo_GetUserIDMapByEmails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedResult map[string]int
+		expectedErr    error
+		gracePeriod    time.Duration
+		extraData      string
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "No data emailsInput",
+			input:          []string{},
+			expectedResult: map[string]int{},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "",
+		},
+		{
+			name:           "Failed with error",
+			input:          []string{"abc@xyz.com"},
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:         testhelpers.ConnectDBFailed(),
+			preparePath:    "",
+		},
+		{
+			name:           "Unknown emails are left out",
+			input:          []string{"ABC@xyz.com", "xyz@abc.com", "new@abc.com"},
+			expectedResult: map[string]int{"abc@xyz.com": 1, "xyz@abc.com": 2},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Emails changed away from within the grace period find their previous user",
+			input:          []string{"abc@xyz.com", "old@abc.com"},
+			expectedResult: map[string]int{"abc@xyz.com": 1, "old@abc.com": 2},
+			expectedErr:    nil,
+			gracePeriod:    time.Hour,
+			extraData:      `insert into emailhistory(userid, email) values (2, 'old@abc.com');`,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Emails changed away from are left out without grace period",
+			input:          []string{"abc@xyz.com", "old@abc.com"},
+			expectedResult: map[string]int{"abc@xyz.com": 1},
+			expectedErr:    nil,
+			extraData:      `insert into emailhistory(userid, email) values (2, 'old@abc.com');`,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+			EmailGracePeriod = testCase.gracePeriod
+			defer func() { EmailGracePeriod = 0 }()
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.GetUserIDMapByEmails(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserRepo_CheckInvalidEmails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedResult []string
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "No data emailsInput",
+			input:          []string{},
+			expectedResult: []string{},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "",
+		},
+		{
+			name:           "Failed with error",
+			input:          []string{"abc@xyz.com"},
+			expectedResult: nil,
+			expectedErr:    errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:         testhelpers.ConnectDBFailed(),
+			preparePath:    "",
+		},
+		{
+			name:           "Unknown emails in the given order",
+			input:          []string{"zzz@abc.com", "abc@xyz.com", "aaa@abc.com"},
+			expectedResult: []string{"zzz@abc.com", "aaa@abc.com"},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Injected email is only compared as a value",
+			input:          []string{"abc@xyz.com'),('xyz@abc.com", "abc@xyz.com"},
+			expectedResult: []string{"abc@xyz.com'),('xyz@abc.com"},
+			expectedErr:    nil,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.CheckInvalidEmails(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+// withoutCreatedAt clears CreatedAt, which is set by the database, so profiles can be compared
+func withoutCreatedAt(profiles []model.UserProfile) []model.UserProfile {
+	for index := range profiles {
+		profiles[index].CreatedAt = time.Time{}
+	}
+	return profiles
+}
+
+func TestUserRepo_GetUserProfile(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedResult *model.UserProfile
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:        "Get profile failed with error",
+			input:       "abc@xyz.com",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "The user does not exist",
+			input:       "mlk@xyz.com",
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Get profile success",
+			input:          "ABC@xyz.com",
+			expectedResult: &model.UserProfile{Email: "abc@xyz.com", DisplayName: "Andy", Bio: "hello", Status: model.UserStatusActive},
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.GetUserProfile(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				if result != nil {
+					require.False(t, result.CreatedAt.IsZero())
+					result.CreatedAt = time.Time{}
+				}
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserRepo_GetUserProfilesByEmails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedResult []model.UserProfile
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:           "No emails",
+			input:          []string{},
+			expectedResult: []model.UserProfile{},
+			mockDb:         testhelpers.ConnectDB(),
+		},
+		{
+			name:        "Get profiles failed with error",
+			input:       []string{"abc@xyz.com"},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:  "Get profiles in the given order, skipping unknown emails",
+			input: []string{"xyz@abc.com", "mlk@xyz.com", "abc@xyz.com"},
+			expectedResult: []model.UserProfile{
+				{Email: "xyz@abc.com", Status: model.UserStatusActive},
+				{Email: "abc@xyz.com", DisplayName: "Andy", Bio: "hello", Status: model.UserStatusActive},
+			},
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.GetUserProfilesByEmails(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, withoutCreatedAt(result))
+			}
+		})
+	}
+}
+
+func TestUserRepo_UpdateUserProfile(t *testing.T) {
+	displayName := "Kate"
+	status := model.UserStatusBusy
+	testCases := []struct {
+		name           string
+		input          *model.UserProfileRepoInput
+		expectedResult *model.UserProfile
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:        "Update profile failed with error",
+			input:       &model.UserProfileRepoInput{Email: "abc@xyz.com", Status: &status},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "The user does not exist",
+			input:       &model.UserProfileRepoInput{Email: "mlk@xyz.com", Status: &status},
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Only the given fields change",
+			input:          &model.UserProfileRepoInput{Email: "abc@xyz.com", DisplayName: &displayName, Status: &status},
+			expectedResult: &model.UserProfile{Email: "abc@xyz.com", DisplayName: "Kate", Bio: "hello", Status: model.UserStatusBusy},
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.UpdateUserProfile(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				if result != nil {
+					result.CreatedAt = time.Time{}
+				}
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserRepo_SearchUsers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		search         string
+		expectedResult []model.UserProfile
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:        "Search failed with error",
+			search:      "abc",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:   "Search by email prefix",
+			search: "XYZ",
+			expectedResult: []model.UserProfile{
+				{Email: "xyz@abc.com", Status: model.UserStatusActive},
+			},
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:   "Search by display name prefix",
+			search: "and",
+			expectedResult: []model.UserProfile{
+				{Email: "abc@xyz.com", DisplayName: "Andy", Bio: "hello", Status: model.UserStatusActive},
+			},
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Wildcards are matched literally",
+			search:         "%",
+			expectedResult: []model.UserProfile{},
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.SearchUsers(context.Background(), testCase.search, model.DefaultUserSearchLimit)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, withoutCreatedAt(result))
+			}
+		})
+	}
+}
+
+func TestUserRepo_DeleteUser(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedResult bool
+		expectedErr    error
+		preparePath    string
+		extraData      string
+		mockDb         *sql.DB
+	}{
+		{
+			name:        "Delete user failed with error",
+			input:       "abc@xyz.com",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "The user does not exist",
+			input:          "mlk@xyz.com",
+			expectedResult: false,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:           "Delete the user with all of its data",
+			input:          "abc@xyz.com",
+			expectedResult: true,
+			mockDb:         testhelpers.ConnectDB(),
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			//A global webhook and one of the other user, with deliveries of both updates
+			extraData: `insert into webhooks(userid, url, secret) values (null, 'https://example.com/all', 's'), (2, 'https://example.com/xyz', 's');
+						insert into webhookoutbox(webhookid, payload) values
+							(1, '{"event":"update.created","update_id":1,"sender":"abc@xyz.com","text":"hello xyz@abc.com","recipients":["xyz@abc.com"]}'),
+							(2, '{"event":"update.created","update_id":1,"sender":"abc@xyz.com","text":"hello xyz@abc.com","recipients":["xyz@abc.com"]}'),
+							(1, '{"event":"update.created","update_id":2,"sender":"xyz@abc.com","text":"hello","recipients":["abc@xyz.com","kate@example.com"]}');`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.DeleteUser(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.expectedResult, result)
+			if !result {
+				return
+			}
+
+			//Nothing refers to the user any more, the other user keeps its own data
+			var remaining int
+			query := `select (select count(*) from useremails where id = 1)
+						   + (select count(*) from friends where firstid = 1 or secondid = 1)
+						   + (select count(*) from subscriptions where requestorid = 1 or targetid = 1)
+						   + (select count(*) from blocks where requestorid = 1 or targetid = 1)
+						   + (select count(*) from friendrequests where requestorid = 1 or targetid = 1)
+						   + (select count(*) from updates where senderid = 1)
+						   + (select count(*) from updatedeliveries where recipientid = 1 or updateid = 1)`
+			require.NoError(t, testCase.mockDb.QueryRow(query).Scan(&remaining))
+			require.Equal(t, 0, remaining)
+
+			//Webhook deliveries no longer carry the user, the ones of its updates are gone
+			rows, err := testCase.mockDb.Query(`select payload from webhookoutbox order by id`)
+			require.NoError(t, err)
+			payloads := make([]string, 0)
+			for rows.Next() {
+				var payload string
+				require.NoError(t, rows.Scan(&payload))
+				payloads = append(payloads, payload)
+			}
+			require.NoError(t, rows.Close())
+			if testCase.extraData != "" {
+				require.Len(t, payloads, 1)
+				require.JSONEq(t, `{"event":"update.created","update_id":2,"sender":"xyz@abc.com","text":"hello","recipients":["kate@example.com"]}`, payloads[0])
+			}
+			existed, err := userRepo.IsExistedUser(context.Background(), "xyz@abc.com")
+			require.NoError(t, err)
+			require.True(t, existed)
+		})
+	}
+}
+
+func TestUserRepo_ExportUser(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expectedNil bool
+		expectedErr error
+		preparePath string
+		mockDb      *sql.DB
+	}{
+		{
+			name:        "Export user failed with error",
+			input:       "abc@xyz.com",
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "The user does not exist",
+			input:       "mlk@xyz.com",
+			expectedNil: true,
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:        "Export user success",
+			input:       "abc@xyz.com",
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := userRepo.ExportUser(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			if testCase.expectedNil {
+				require.Nil(t, result)
+				return
+			}
+			require.Equal(t, "abc@xyz.com", result.Profile.Email)
+			require.Len(t, result.Friends, 1)
+			require.Equal(t, "xyz@abc.com", result.Friends[0].Email)
+			require.Equal(t, []string{}, result.Subscriptions)
+			require.Equal(t, []string{"xyz@abc.com"}, result.Subscribers)
+			require.Equal(t, []string{"xyz@abc.com"}, result.Blocking)
+			require.Len(t, result.FriendRequests, 1)
+			require.Equal(t, model.FriendRequestStatusPending, result.FriendRequests[0].Status)
+			require.Len(t, result.Updates, 1)
+			require.Equal(t, "hello xyz@abc.com", result.Updates[0].Text)
+			require.Len(t, result.ReceivedUpdates, 1)
+			require.Equal(t, "xyz@abc.com", result.ReceivedUpdates[0].Sender)
+			require.Nil(t, result.ReceivedUpdates[0].ReadAt)
+			require.Equal(t, []model.ExportedWebhook{}, result.Webhooks)
+			require.Equal(t, []model.ExportedEmailChange{}, result.PreviousEmails)
+		})
+	}
+}
+
+func TestUserRepo_ChangeEmail(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       *model.ChangeEmailRepoInput
+		gracePeriod time.Duration
+		extraData   string
+		expectedErr error
+		preparePath string
+		mockDb      *sql.DB
+	}{
+		{
+			name:        "Change email failed with error",
+			input:       &model.ChangeEmailRepoInput{OldEmail: "abc@xyz.com", NewEmail: "new@xyz.com"},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:        "Old email does not exist",
+			input:       &model.ChangeEmailRepoInput{OldEmail: "mlk@xyz.com", NewEmail: "new@xyz.com"},
+			expectedErr: model.ErrUserNotFound,
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:        "New email is taken",
+			input:       &model.ChangeEmailRepoInput{OldEmail: "abc@xyz.com", NewEmail: "xyz@abc.com"},
+			expectedErr: model.ErrUserExisted,
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:        "New email was changed away from by another user within the grace period",
+			input:       &model.ChangeEmailRepoInput{OldEmail: "abc@xyz.com", NewEmail: "old@abc.com"},
+			gracePeriod: time.Hour,
+			extraData:   `insert into emailhistory(userid, email) values (2, 'old@abc.com');`,
+			expectedErr: model.ErrUserExisted,
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:        "Change email without grace period",
+			input:       &model.ChangeEmailRepoInput{OldEmail: "abc@xyz.com", NewEmail: "new@xyz.com"},
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+		{
+			name:        "Change email with grace period",
+			input:       &model.ChangeEmailRepoInput{OldEmail: "abc@xyz.com", NewEmail: "new@xyz.com"},
+			gracePeriod: time.Hour,
+			mockDb:      testhelpers.ConnectDB(),
+			preparePath: "../testhelpers/preparedata/datafortest",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+			if testCase.extraData != "" {
+				_, err := testCase.mockDb.Exec(testCase.extraData)
+				require.NoError(t, err)
+			}
+			EmailGracePeriod = testCase.gracePeriod
+			defer func() { EmailGracePeriod = 0 }()
+
+			userRepo := UserRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			err := userRepo.ChangeEmail(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+
+			//The user keeps its ID, the old email only finds it within the grace period
+			newID, err := userRepo.GetUserIDByEmail(context.Background(), testCase.input.NewEmail)
+			require.NoError(t, err)
+			require.Equal(t, 1, newID)
+			oldID, err := userRepo.GetUserIDByEmail(context.Background(), testCase.input.OldEmail)
+			require.NoError(t, err)
+			if testCase.gracePeriod > 0 {
+				require.Equal(t, 1, oldID)
+			} else {
+				require.Equal(t, 0, oldID)
+			}
+			existed, err := userRepo.IsExistedUser(context.Background(), testCase.input.OldEmail)
+			require.NoError(t, err)
+			require.False(t, existed)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/utils"
+	"github.com/lib/pq"
+)
+
+type IWebhookRepo interface {
+	CreateWebhook(ctx context.Context, input *model.WebhookRepoInput) (int, error)
+	GetWebhooksForRecipients(ctx context.Context, emails []string) ([]model.Webhook, error)
+	ClaimDueOutboxEntries(ctx context.Context, limit int, claimTimeout time.Duration) ([]model.WebhookOutboxEntry, error)
+	MarkOutboxDelivered(ctx context.Context, id int) error
+	MarkOutboxFailed(ctx context.Context, failure *model.WebhookOutboxFailure) error
+	GetDeadLetters(ctx context.Context) ([]model.WebhookDeadLetter, error)
+}
+
+type WebhookRepo struct {
+	Db *sql.DB
+}
+
+// CreateWebhook registers a webhook. A UserID of 0 registers a global webhook.
+func (_self WebhookRepo) CreateWebhook(ctx context.Context, input *model.WebhookRepoInput) (int, error) {
+	userID := sql.NullInt64{Int64: int64(input.UserID), Valid: input.UserID != 0}
+	query := `insert into webhooks(userid, url, secret) values ($1, $2, $3) returning id`
+	var id int
+	if err := _self.Db.QueryRowContext(ctx, query, userID, input.URL, input.Secret).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetWebhooksForRecipients returns the global webhooks and the webhooks registered by any of the emails
+func (_self WebhookRepo) GetWebhooksForRecipients(ctx context.Context, emails []string) ([]model.Webhook, error) {
+	return getWebhooksForRecipients(ctx, _self.Db, emails)
+}
+
+// queryer is a *sql.DB or a *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func getWebhooksForRecipients(ctx context.Context, db queryer, emails []string) ([]model.Webhook, error) {
+	query := `select w.id, coalesce(ue.email, ''), w.url, w.secret
+			  from webhooks w
+			  left join useremails ue on ue.id = w.userid
+			  where w.userid is null or ue.email = any($1)
+			  order by w.id`
+	rows, err := db.QueryContext(ctx, query, pq.Array(utils.NormalizeEmails(emails)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]model.Webhook, 0)
+	for rows.Next() {
+		var webhook model.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.UserEmail, &webhook.URL, &webhook.Secret); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// createOutboxEntries queues the entries in the transaction of what they report, with one statement
+func createOutboxEntries(ctx context.Context, tx *sql.Tx, entries []model.WebhookOutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	webhookIDs := make([]int, len(entries))
+	payloads := make([]string, len(entries))
+	for i, entry := range entries {
+		webhookIDs[i], payloads[i] = entry.WebhookID, entry.Payload
+	}
+
+	query := `insert into webhookoutbox(webhookid, payload) select * from unnest($1::int8[], $2::text[])`
+	_, err := tx.ExecContext(ctx, query, pq.Array(webhookIDs), pq.Array(payloads))
+	return err
+}
+
+// ClaimDueOutboxEntries marks the due deliveries as sending and returns them, oldest first. Entries claimed by
+// another dispatcher are skipped, and a claim which is neither delivered nor failed within claimTimeout is due again.
+func (_self WebhookRepo) ClaimDueOutboxEntries(ctx context.Context, limit int, claimTimeout time.Duration) ([]model.WebhookOutboxEntry, error) {
+	query := `update webhookoutbox o
+			  set status = $1, nextattemptat = now() + $3 * interval '1 second', updatedat = now()
+			  from webhooks w
+			  where w.id = o.webhookid and o.id in (
+			  	select id from webhookoutbox
+			  	where status in ($1, $2) and nextattemptat <= now()
+			  	order by nextattemptat, id
+			  	limit $4
+			  	for update skip locked
+			  )
+			  returning o.id, o.webhookid, w.url, w.secret, o.payload, o.attempts`
+	rows, err := _self.Db.QueryContext(ctx, query, model.WebhookOutboxStatusSending, model.WebhookOutboxStatusPending,
+		claimTimeout.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]model.WebhookOutboxEntry, 0)
+	for rows.Next() {
+		var entry model.WebhookOutboxEntry
+		if err := rows.Scan(&entry.ID, &entry.WebhookID, &entry.URL, &entry.Secret, &entry.Payload, &entry.Attempts); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+func (_self WebhookRepo) MarkOutboxDelivered(ctx context.Context, id int) error {
+	query := `update webhookoutbox set status = $2, attempts = attempts + 1, updatedat = now() where id = $1`
+	_, err := _self.Db.ExecContext(ctx, query, id, model.WebhookOutboxStatusDelivered)
+	return err
+}
+
+// MarkOutboxFailed records a failed attempt and either schedules the next one or moves the entry to dead letters
+func (_self WebhookRepo) MarkOutboxFailed(ctx context.Context, failure *model.WebhookOutboxFailure) error {
+	status := model.WebhookOutboxStatusPending
+	if failure.Dead {
+		status = model.WebhookOutboxStatusDead
+	}
+	query := `update webhookoutbox
+			  set status = $2, attempts = $3, nextattemptat = $4, lasterror = $5, updatedat = now()
+			  where id = $1`
+	_, err := _self.Db.ExecContext(ctx, query, failure.ID, status, failure.Attempts, failure.NextAttemptAt, failure.LastError)
+	return err
+}
+
+func (_self WebhookRepo) GetDeadLetters(ctx context.Context) ([]model.WebhookDeadLetter, error) {
+	query := `select o.id, o.webhookid, w.url, o.payload, o.attempts, coalesce(o.lasterror, ''), o.updatedat
+			  from webhookoutbox o
+			  join webhooks w on w.id = o.webhookid
+			  where o.status = $1
+			  order by o.updatedat desc, o.id desc`
+	rows, err := _self.Db.QueryContext(ctx, query, model.WebhookOutboxStatusDead)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters := make([]model.WebhookDeadLetter, 0)
+	for rows.Next() {
+		var deadLetter model.WebhookDeadLetter
+		var payload string
+		if err := rows.Scan(&deadLetter.ID, &deadLetter.WebhookID, &deadLetter.URL, &payload,
+			&deadLetter.Attempts, &deadLetter.LastError, &deadLetter.FailedAt); err != nil {
+			return nil, err
+		}
+		deadLetter.Payload = []byte(payload)
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, rows.Err()
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepo_CreateWebhook(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *model.WebhookRepoInput
+		expectedResult int
+		expectedErr    error
+		preparePath    string
+		mockDb         *sql.DB
+	}{
+		{
+			name:        "Create webhook failed with error",
+			input:       &model.WebhookRepoInput{URL: "https://example.com/hook", Secret: "s3cr3t"},
+			expectedErr: errors.New("pq: password authentication failed for user \"postgrespassword=000000\""),
+			preparePath: "",
+			mockDb:      testhelpers.ConnectDBFailed(),
+		},
+		{
+			name:           "Create user webhook success",
+			input:          &model.WebhookRepoInput{UserID: 2, URL: "https://example.com/hook", Secret: "s3cr3t"},
+			expectedResult: 1,
+			preparePath:    "../testhelpers/preparedata/datafortest",
+			mockDb:         testhelpers.ConnectDB(),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			testhelpers.PrepareDBForTest(testCase.mockDb, testCase.preparePath)
+
+			webhookRepo := WebhookRepo{
+				Db: testCase.mockDb,
+			}
+
+			// When
+			result, err := webhookRepo.CreateWebhook(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+
+				webhooks, err := webhookRepo.GetWebhooksForRecipients(context.Background(), []string{"xyz@abc.com"})
+				require.NoError(t, err)
+				require.Equal(t, []model.Webhook{
+					{ID: 1, UserEmail: "xyz@abc.com", URL: "https://example.com/hook", Secret: "s3cr3t"},
+				}, webhooks)
+			}
+		})
+	}
+}
+
+func TestWebhookRepo_Outbox(t *testing.T) {
+	// Given
+	db := testhelpers.ConnectDB()
+	testhelpers.PrepareDBForTest(db, "../testhelpers/preparedata/datafortest")
+	webhookRepo := WebhookRepo{
+		Db: db,
+	}
+	updateRepo := UpdateRepo{
+		Db: db,
+	}
+	globalID, err := webhookRepo.CreateWebhook(context.Background(), &model.WebhookRepoInput{URL: "https://example.com/hook", Secret: "s3cr3t"})
+	require.NoError(t, err)
+	userID, err := webhookRepo.CreateWebhook(context.Background(), &model.WebhookRepoInput{UserID: 1, URL: "https://example.com/abc", Secret: "s3cr3t"})
+	require.NoError(t, err)
+
+	// When
+	updateID, err := updateRepo.CreateUpdate(context.Background(), &model.UpdateRepoInput{
+		SenderID:   2,
+		Sender:     "xyz@abc.com",
+		Text:       "hello",
+		Mentions:   []string{},
+		Recipients: []string{"abc@xyz.com", "unknown@xyz.com"},
+	})
+	require.NoError(t, err)
+	entries, err := webhookRepo.ClaimDueOutboxEntries(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+	claimedAgain, err := webhookRepo.ClaimDueOutboxEntries(context.Background(), 10, time.Minute)
+	require.NoError(t, err)
+
+	// Then
+	require.Empty(t, claimedAgain, "claimed entries are not due until the claim expires")
+	require.Len(t, entries, 2)
+	require.Equal(t, globalID, entries[0].WebhookID)
+	require.JSONEq(t, fmt.Sprintf(`{"event":"update.created","update_id":%d,"sender":"xyz@abc.com","text":"hello","recipients":["abc@xyz.com","unknown@xyz.com"]}`, updateID), entries[0].Payload)
+	require.Equal(t, userID, entries[1].WebhookID)
+	require.JSONEq(t, fmt.Sprintf(`{"event":"update.created","update_id":%d,"sender":"xyz@abc.com","text":"hello","recipients":["abc@xyz.com"]}`, updateID), entries[1].Payload)
+
+	err = webhookRepo.MarkOutboxDelivered(context.Background(), entries[1].ID)
+	require.NoError(t, err)
+	err = webhookRepo.MarkOutboxFailed(context.Background(), &model.WebhookOutboxFailure{
+		ID:        entries[0].ID,
+		Attempts:  1,
+		LastError: "webhook responded with status 500",
+		Dead:      true,
+	})
+	require.NoError(t, err)
+
+	deadLetters, err := webhookRepo.GetDeadLetters(context.Background())
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, "https://example.com/hook", deadLetters[0].URL)
+	require.Equal(t, "webhook responded with status 500", deadLetters[0].LastError)
+}
+
+func TestWebhookRepo_ClaimDueOutboxEntries_ExpiredClaim(t *testing.T) {
+	// Given
+	db := testhelpers.ConnectDB()
+	testhelpers.PrepareDBForTest(db, "../testhelpers/preparedata/datafortest")
+	webhookRepo := WebhookRepo{
+		Db: db,
+	}
+	webhookID, err := webhookRepo.CreateWebhook(context.Background(), &model.WebhookRepoInput{URL: "https://example.com/hook", Secret: "s3cr3t"})
+	require.NoError(t, err)
+	_, err = db.Exec(`insert into webhookoutbox(webhookid, payload, status, nextattemptat) values ($1, '{"update_id":1}', 'sending', now() - interval '1 minute')`, webhookID)
+	require.NoError(t, err)
+
+	// When
+	entries, err := webhookRepo.ClaimDueOutboxEntries(context.Background(), 10, time.Minute)
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.JSONEq(t, `{"update_id":1}`, entries[0].Payload)
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"S3_FriendManagement_ThinhNguyen/events"
 	"S3_FriendManagement_ThinhNguyen/handlers"
 	"S3_FriendManagement_ThinhNguyen/repositories"
 	"S3_FriendManagement_ThinhNguyen/services"
@@ -9,11 +10,18 @@ import (
 	"net/http"
 )
 
+// EventsEnabled mounts the /events routes, it is turned off by EVENTS_ENABLED=false
+var EventsEnabled = true
+
 func CreateRoutes(db *sql.DB) *chi.Mux {
 	r := chi.NewRouter()
 
+	//In-process hub shared by the handlers publishing events and the event stream
+	eventHub := events.NewEventHub(events.DefaultBufferSize)
+
 	//Routes for user
 	r.Route("/user", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/user")))
 		UserHandler := handlers.UserHandler{
 			IUserService: services.UserService{
 				IUserRepo: repositories.UserRepo{
@@ -22,10 +30,30 @@ func CreateRoutes(db *sql.DB) *chi.Mux {
 			},
 		}
 		r.MethodFunc(http.MethodPost, "/", UserHandler.CreateUser)
+		r.MethodFunc(http.MethodGet, "/", UserHandler.SearchUsers)
+		r.MethodFunc(http.MethodPost, "/change-email", UserHandler.ChangeEmail)
+		r.MethodFunc(http.MethodGet, "/{email}", UserHandler.GetUser)
+		r.MethodFunc(http.MethodPatch, "/{email}", UserHandler.UpdateUser)
+		r.MethodFunc(http.MethodDelete, "/{email}", UserHandler.DeleteUser)
+		r.MethodFunc(http.MethodGet, "/{email}/export", UserHandler.ExportUser)
+	})
+
+	//Route for user import, the file is streamed so it only gets a timeout configured for "/user/import"
+	r.Route("/user/import", func(r chi.Router) {
+		r.Use(Timeout(RouteTimeouts["/user/import"]))
+		UserHandler := handlers.UserHandler{
+			IUserService: services.UserService{
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+		}
+		r.MethodFunc(http.MethodPost, "/", UserHandler.ImportUsers)
 	})
 
 	//Routes for Friend
 	r.Route("/friend", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/friend")))
 		FriendHandler := handlers.FriendHandler{
 			IUserService: services.UserService{
 				IUserRepo: repositories.UserRepo{
@@ -40,14 +68,49 @@ func CreateRoutes(db *sql.DB) *chi.Mux {
 					Db: db,
 				},
 			},
+			IEventHub: eventHub,
 		}
 		r.MethodFunc(http.MethodPost, "/", FriendHandler.CreateFriend)
+		r.MethodFunc(http.MethodPost, "/batch", FriendHandler.CreateFriends)
+		r.MethodFunc(http.MethodDelete, "/", FriendHandler.DeleteFriend)
 		r.MethodFunc(http.MethodGet, "/friends", FriendHandler.GetFriendListByEmail)
 		r.MethodFunc(http.MethodGet, "/common-friends", FriendHandler.GetCommonFriendListByEmails)
+		r.MethodFunc(http.MethodGet, "/suggestions", FriendHandler.GetFriendSuggestions)
+		r.MethodFunc(http.MethodGet, "/path", FriendHandler.GetFriendPath)
 		r.MethodFunc(http.MethodGet, "/emails-receive-update", FriendHandler.GetEmailsReceiveUpdate)
 	})
+	//Routes for Friend request
+	r.Route("/friend-request", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/friend-request")))
+		friendRequestHandler := handlers.FriendRequestHandler{
+			IUserService: services.UserService{
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+			IFriendRequestService: services.FriendRequestService{
+				IFriendRequestRepo: repositories.FriendRequestRepo{
+					Db: db,
+				},
+				IFriendRepo: repositories.FriendRepo{
+					Db: db,
+				},
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+			IEventHub: eventHub,
+		}
+		r.MethodFunc(http.MethodPost, "/", friendRequestHandler.CreateFriendRequest)
+		r.MethodFunc(http.MethodPost, "/accept", friendRequestHandler.AcceptFriendRequest)
+		r.MethodFunc(http.MethodPost, "/reject", friendRequestHandler.RejectFriendRequest)
+		r.MethodFunc(http.MethodPost, "/cancel", friendRequestHandler.CancelFriendRequest)
+		r.MethodFunc(http.MethodGet, "/incoming", friendRequestHandler.GetIncomingFriendRequests)
+		r.MethodFunc(http.MethodGet, "/outgoing", friendRequestHandler.GetOutgoingFriendRequests)
+	})
 	//Routes for Subscription
 	r.Route("/subscription", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/subscription")))
 		subscriptionHandler := handlers.SubscriptionHandler{
 			IUserService: services.UserService{
 				IUserRepo: repositories.UserRepo{
@@ -58,12 +121,21 @@ func CreateRoutes(db *sql.DB) *chi.Mux {
 				ISubscriptionRepo: repositories.SubscriptionRepo{
 					Db: db,
 				},
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
 			},
+			IEventHub: eventHub,
 		}
 		r.MethodFunc(http.MethodPost, "/", subscriptionHandler.CreateSubscription)
+		r.MethodFunc(http.MethodPost, "/batch", subscriptionHandler.CreateSubscriptions)
+		r.MethodFunc(http.MethodDelete, "/", subscriptionHandler.DeleteSubscription)
+		r.MethodFunc(http.MethodGet, "/subscribers", subscriptionHandler.GetSubscriberList)
+		r.MethodFunc(http.MethodGet, "/following", subscriptionHandler.GetFollowingList)
 	})
 	//Routes for Blocking
 	r.Route("/block", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/block")))
 		blockHandler := handlers.BlockHandler{
 			IUserService: services.UserService{
 				IUserRepo: repositories.UserRepo{
@@ -74,9 +146,105 @@ func CreateRoutes(db *sql.DB) *chi.Mux {
 				IBlockingRepo: repositories.BlockingRepo{
 					Db: db,
 				},
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
 			},
+			IEventHub: eventHub,
 		}
 		r.MethodFunc(http.MethodPost, "/", blockHandler.CreateBlocking)
+		r.MethodFunc(http.MethodPost, "/batch", blockHandler.CreateBlockings)
+		r.MethodFunc(http.MethodDelete, "/", blockHandler.DeleteBlocking)
+		r.MethodFunc(http.MethodGet, "/list", blockHandler.GetBlockingList)
 	})
+	//Routes for Update
+	r.Route("/update", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/update")))
+		updateHandler := handlers.UpdateHandler{
+			IUserService: services.UserService{
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+			IFriendServices: services.FriendService{
+				IFriendRepo: repositories.FriendRepo{
+					Db: db,
+				},
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+			IUpdateService: services.UpdateService{
+				IUpdateRepo: repositories.UpdateRepo{
+					Db: db,
+				},
+			},
+			IEventHub: eventHub,
+		}
+		r.MethodFunc(http.MethodPost, "/", updateHandler.CreateUpdate)
+	})
+	//Routes for Feed
+	r.Route("/feed", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/feed")))
+		feedHandler := handlers.UpdateHandler{
+			IUserService: services.UserService{
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+			IUpdateService: services.UpdateService{
+				IUpdateRepo: repositories.UpdateRepo{
+					Db: db,
+				},
+			},
+		}
+		r.MethodFunc(http.MethodGet, "/", feedHandler.GetFeed)
+		r.MethodFunc(http.MethodPost, "/read", feedHandler.MarkFeedAsRead)
+	})
+	//Routes for Webhook
+	r.Route("/webhook", func(r chi.Router) {
+		r.Use(Timeout(routeTimeout("/webhook")))
+		webhookHandler := handlers.WebhookHandler{
+			IUserService: services.UserService{
+				IUserRepo: repositories.UserRepo{
+					Db: db,
+				},
+			},
+			IWebhookService: services.WebhookService{
+				IWebhookRepo: repositories.WebhookRepo{
+					Db: db,
+				},
+			},
+		}
+		r.MethodFunc(http.MethodPost, "/", webhookHandler.CreateWebhook)
+		r.MethodFunc(http.MethodGet, "/dead-letters", webhookHandler.GetDeadLetters)
+	})
+	//Routes for Graph, the export is streamed so it only gets a timeout configured for "/graph"
+	r.Route("/graph", func(r chi.Router) {
+		r.Use(Timeout(RouteTimeouts["/graph"]))
+		graphHandler := handlers.GraphHandler{
+			IGraphService: services.GraphService{
+				IGraphRepo: repositories.GraphRepo{
+					Db: db,
+				},
+			},
+		}
+		r.MethodFunc(http.MethodGet, "/export", graphHandler.ExportGraph)
+	})
+	//Routes for Events
+	if EventsEnabled {
+		r.Route("/events", func(r chi.Router) {
+			//No timeout, the stream stays open until the client leaves
+			eventsHandler := handlers.EventsHandler{
+				IUserService: services.UserService{
+					IUserRepo: repositories.UserRepo{
+						Db: db,
+					},
+				},
+				IEventHub: eventHub,
+			}
+			r.MethodFunc(http.MethodGet, "/stream", eventsHandler.StreamEvents)
+		})
+	}
 	return r
 }
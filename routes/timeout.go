@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRouteTimeout applies to every route group without an entry in RouteTimeouts
+var DefaultRouteTimeout = 10 * time.Second
+
+// RouteTimeouts overrides the timeout of a route group, keyed by its prefix (ex: "/friend").
+// A zero duration turns the timeout off for that group.
+var RouteTimeouts = map[string]time.Duration{}
+
+// routeTimeout returns the timeout configured for the route group of prefix
+func routeTimeout(prefix string) time.Duration {
+	if timeout, ok := RouteTimeouts[prefix]; ok {
+		return timeout
+	}
+	return DefaultRouteTimeout
+}
+
+// Timeout cancels the request context after timeout. The database calls of the request stop with it and the client gets
+// 504, or 503 when the request was cancelled before its deadline. The handler writes into a buffer so a late response
+// cannot mix with the timeout one.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			writer := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(writer, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				//A handler returning because of the expired context is answered as a timeout too
+				if ctx.Err() == nil {
+					writer.flushTo(w)
+					return
+				}
+			case <-ctx.Done():
+			}
+
+			writer.mutex.Lock()
+			defer writer.mutex.Unlock()
+			writer.timedOut = true
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				http.Error(w, "request timed out", http.StatusGatewayTimeout)
+				return
+			}
+			http.Error(w, "request cancelled", http.StatusServiceUnavailable)
+		})
+	}
+}
+
+// timeoutWriter buffers the response of a handler running under Timeout
+type timeoutWriter struct {
+	mutex    sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (_self *timeoutWriter) Header() http.Header {
+	return _self.header
+}
+
+func (_self *timeoutWriter) Write(data []byte) (int, error) {
+	_self.mutex.Lock()
+	defer _self.mutex.Unlock()
+	if _self.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if _self.code == 0 {
+		_self.code = http.StatusOK
+	}
+	return _self.body.Write(data)
+}
+
+func (_self *timeoutWriter) WriteHeader(code int) {
+	_self.mutex.Lock()
+	defer _self.mutex.Unlock()
+	if _self.timedOut || _self.code != 0 {
+		return
+	}
+	_self.code = code
+}
+
+func (_self *timeoutWriter) flushTo(w http.ResponseWriter) {
+	_self.mutex.Lock()
+	defer _self.mutex.Unlock()
+	for key, values := range _self.header {
+		w.Header()[key] = values
+	}
+	if _self.code == 0 {
+		_self.code = http.StatusOK
+	}
+	w.WriteHeader(_self.code)
+	w.Write(_self.body.Bytes())
+}
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		timeout              time.Duration
+		cancelRequest        bool
+		handler              http.HandlerFunc
+		expectedResponseBody string
+		expectedStatus       int
+	}{
+		{
+			name:    "Handler finishes in time",
+			timeout: time.Second,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("{\"success\":true}\n"))
+			},
+			expectedResponseBody: "{\"success\":true}\n",
+			expectedStatus:       http.StatusCreated,
+		},
+		{
+			name:    "Handler runs past the deadline",
+			timeout: 10 * time.Millisecond,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				http.Error(w, "pq: canceling statement due to user request", http.StatusInternalServerError)
+			},
+			expectedResponseBody: "request timed out\n",
+			expectedStatus:       http.StatusGatewayTimeout,
+		},
+		{
+			name:          "Request cancelled before the deadline",
+			timeout:       time.Second,
+			cancelRequest: true,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			expectedResponseBody: "request cancelled\n",
+			expectedStatus:       http.StatusServiceUnavailable,
+		},
+		{
+			name:    "Timeout turned off",
+			timeout: 0,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline := r.Context().Deadline()
+				if hasDeadline {
+					http.Error(w, "unexpected deadline", http.StatusInternalServerError)
+					return
+				}
+				w.Write([]byte("ok"))
+			},
+			expectedResponseBody: "ok",
+			expectedStatus:       http.StatusOK,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if testCase.cancelRequest {
+				cancel()
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/friend/friends", nil)
+			require.NoError(t, err)
+
+			// When
+			responseRecorder := httptest.NewRecorder()
+			Timeout(testCase.timeout)(testCase.handler).ServeHTTP(responseRecorder, req)
+
+			// Then
+			require.Equal(t, testCase.expectedStatus, responseRecorder.Code)
+			require.Equal(t, testCase.expectedResponseBody, responseRecorder.Body.String())
+		})
+	}
+}
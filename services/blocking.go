@@ -1,30 +1,71 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/repositories"
 )
 
 type IBlockingService interface {
-	CreateBlocking(*model.BlockingServiceInput) error
-	IsExistedBlocking(int, int) (bool, error)
+	CreateBlocking(context.Context, *model.BlockingServiceInput) error
+	CreateBlockings(context.Context, []model.BlockingServiceInput) ([]error, error)
+	DeleteBlocking(context.Context, *model.BlockingServiceInput) error
+	IsExistedBlocking(context.Context, int, int) (bool, error)
+	GetBlockingList(context.Context, int) ([]string, error)
 }
 
 type BlockingService struct {
 	IBlockingRepo repositories.IBlockingRepo
+	IUserRepo     repositories.IUserRepo
+}
+
+func (_self BlockingService) CreateBlocking(ctx context.Context, blocking *model.BlockingServiceInput) error {
+	//Create repo input model
+	blockingRepoInputModel := &model.BlockingRepoInput{
+		Requestor: blocking.Requestor,
+		Target:    blocking.Target,
+	}
+	err := _self.IBlockingRepo.CreateBlocking(ctx, blockingRepoInputModel)
+	return err
+}
+
+// CreateBlockings creates many blocks at once and returns the outcome of each of them
+func (_self BlockingService) CreateBlockings(ctx context.Context, inputs []model.BlockingServiceInput) ([]error, error) {
+	//Create repo input models
+	repoInputs := make([]model.BlockingRepoInput, len(inputs))
+	for i, input := range inputs {
+		repoInputs[i] = model.BlockingRepoInput{
+			Requestor: input.Requestor,
+			Target:    input.Target,
+		}
+	}
+	results, err := _self.IBlockingRepo.CreateBlockings(ctx, repoInputs)
+	return results, err
 }
 
-func (_self BlockingService) CreateBlocking(blocking *model.BlockingServiceInput) error {
+func (_self BlockingService) DeleteBlocking(ctx context.Context, blocking *model.BlockingServiceInput) error {
 	//Create repo input model
 	blockingRepoInputModel := &model.BlockingRepoInput{
 		Requestor: blocking.Requestor,
 		Target:    blocking.Target,
 	}
-	err := _self.IBlockingRepo.CreateBlocking(blockingRepoInputModel)
+	err := _self.IBlockingRepo.DeleteBlocking(ctx, blockingRepoInputModel)
 	return err
 }
 
-func (_self BlockingService) IsExistedBlocking(requestorID int, targetID int) (bool, error) {
-	exist, err := _self.IBlockingRepo.IsExistedBlocking(requestorID, targetID)
+func (_self BlockingService) IsExistedBlocking(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	exist, err := _self.IBlockingRepo.IsExistedBlocking(ctx, requestorID, targetID)
 	return exist, err
 }
+
+func (_self BlockingService) GetBlockingList(ctx context.Context, userID int) ([]string, error) {
+	//Get UserIDs blocked by the user
+	targetIDs, err := _self.IBlockingRepo.GetBlockingListByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emails, err := _self.IUserRepo.GetEmailListByIDs(ctx, targetIDs)
+	return emails, err
+}
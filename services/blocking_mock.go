@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockBlockingRepo struct {
 	mock.Mock
 }
 
-func (_self mockBlockingRepo) CreateBlocking(blocking *model.BlockingRepoInput) error {
+func (_self mockBlockingRepo) CreateBlocking(ctx context.Context, blocking *model.BlockingRepoInput) error {
 	args := _self.Called(blocking)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,17 @@ func (_self mockBlockingRepo) CreateBlocking(blocking *model.BlockingRepoInput)
 	return r
 }
 
-func (_self mockBlockingRepo) IsExistedBlocking(requestorID int, targetID int) (bool, error) {
+func (_self mockBlockingRepo) CreateBlockings(ctx context.Context, inputs []model.BlockingRepoInput) ([]error, error) {
+	args := _self.Called(inputs)
+	r0 := args.Get(0).([]error)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockBlockingRepo) IsExistedBlocking(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	args := _self.Called(requestorID, targetID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -27,3 +39,22 @@ func (_self mockBlockingRepo) IsExistedBlocking(requestorID int, targetID int) (
 	}
 	return r0, r1
 }
+
+func (_self mockBlockingRepo) DeleteBlocking(ctx context.Context, blocking *model.BlockingRepoInput) error {
+	args := _self.Called(blocking)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockBlockingRepo) GetBlockingListByID(ctx context.Context, userID int) ([]int, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
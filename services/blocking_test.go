@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -56,7 +57,7 @@ func TestBlockingService_CreateBlocking(t *testing.T) {
 			}
 
 			// Then
-			err := service.CreateBlocking(testCase.input)
+			err := service.CreateBlocking(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -68,6 +69,61 @@ func TestBlockingService_CreateBlocking(t *testing.T) {
 	}
 }
 
+func TestBlockingService_CreateBlockings(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []model.BlockingServiceInput
+		expectedResult []error
+		expectedErr    error
+		mockRepoInput  []model.BlockingRepoInput
+		mockRepoResult []error
+		mockRepoError  error
+	}{
+		{
+			name:           "Create blockings failed with error",
+			input:          []model.BlockingServiceInput{{Requestor: 1, Target: 2}},
+			expectedResult: nil,
+			expectedErr:    errors.New("create blockings failed with error"),
+			mockRepoInput:  []model.BlockingRepoInput{{Requestor: 1, Target: 2}},
+			mockRepoResult: nil,
+			mockRepoError:  errors.New("create blockings failed with error"),
+		},
+		{
+			name:           "Create blockings success",
+			input:          []model.BlockingServiceInput{{Requestor: 1, Target: 2}, {Requestor: 3, Target: 4}},
+			expectedResult: []error{nil, model.ErrBlockingExisted},
+			expectedErr:    nil,
+			mockRepoInput:  []model.BlockingRepoInput{{Requestor: 1, Target: 2}, {Requestor: 3, Target: 4}},
+			mockRepoResult: []error{nil, model.ErrBlockingExisted},
+			mockRepoError:  nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockBlockingRepo := new(mockBlockingRepo)
+			mockBlockingRepo.On("CreateBlockings", testCase.mockRepoInput).
+				Return(testCase.mockRepoResult, testCase.mockRepoError)
+
+			service := BlockingService{
+				IBlockingRepo: mockBlockingRepo,
+			}
+
+			// When
+			result, err := service.CreateBlockings(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
 func TestBlockingService_IsExistedBlocking(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -110,7 +166,7 @@ func TestBlockingService_IsExistedBlocking(t *testing.T) {
 			}
 
 			// When
-			result, err := service.IsExistedBlocking(testCase.input[0], testCase.input[1])
+			result, err := service.IsExistedBlocking(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -122,3 +178,154 @@ func TestBlockingService_IsExistedBlocking(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockingService_DeleteBlocking(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *model.BlockingServiceInput
+		expectedErr   error
+		mockRepoInput *model.BlockingRepoInput
+		mockRepoError error
+	}{
+		{
+			name: "Delete blocking failed with error",
+			input: &model.BlockingServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: errors.New("delete blocking failed with error"),
+			mockRepoInput: &model.BlockingRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			mockRepoError: errors.New("delete blocking failed with error"),
+		},
+		{
+			name: "Delete blocking success",
+			input: &model.BlockingServiceInput{
+				Requestor: 3,
+				Target:    4,
+			},
+			expectedErr: nil,
+			mockRepoInput: &model.BlockingRepoInput{
+				Requestor: 3,
+				Target:    4,
+			},
+			mockRepoError: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockBlockingRepo := new(mockBlockingRepo)
+			mockBlockingRepo.On("DeleteBlocking", testCase.mockRepoInput).
+				Return(testCase.mockRepoError)
+
+			service := BlockingService{
+				IBlockingRepo: mockBlockingRepo,
+			}
+
+			// When
+			err := service.DeleteBlocking(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBlockingService_GetBlockingList(t *testing.T) {
+	type mockGetBlockingListByID struct {
+		input  int
+		result []int
+		err    error
+	}
+	type mockGetEmailListByIDs struct {
+		input  []int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name                string
+		input               int
+		expectedResult      []string
+		expectedErr         error
+		mockGetBlockingList mockGetBlockingListByID
+		mockGetEmailList    mockGetEmailListByIDs
+	}{
+		{
+			name:        "Get blocking list failed with error",
+			input:       1,
+			expectedErr: errors.New("get blocking list failed with error"),
+			mockGetBlockingList: mockGetBlockingListByID{
+				input:  1,
+				result: nil,
+				err:    errors.New("get blocking list failed with error"),
+			},
+		},
+		{
+			name:        "Get email list failed with error",
+			input:       1,
+			expectedErr: errors.New("get email list failed with error"),
+			mockGetBlockingList: mockGetBlockingListByID{
+				input:  1,
+				result: []int{2},
+				err:    nil,
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{2},
+				result: nil,
+				err:    errors.New("get email list failed with error"),
+			},
+		},
+		{
+			name:           "Get blocking list success",
+			input:          1,
+			expectedResult: []string{"xyz@abc.com"},
+			expectedErr:    nil,
+			mockGetBlockingList: mockGetBlockingListByID{
+				input:  1,
+				result: []int{2},
+				err:    nil,
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{2},
+				result: []string{"xyz@abc.com"},
+				err:    nil,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockBlockingRepo := new(mockBlockingRepo)
+			mockUserRepo := new(mockUserRepo)
+			mockBlockingRepo.On("GetBlockingListByID", testCase.mockGetBlockingList.input).
+				Return(testCase.mockGetBlockingList.result, testCase.mockGetBlockingList.err)
+			mockUserRepo.On("GetEmailListByIDs", testCase.mockGetEmailList.input).
+				Return(testCase.mockGetEmailList.result, testCase.mockGetEmailList.err)
+
+			service := BlockingService{
+				IBlockingRepo: mockBlockingRepo,
+				IUserRepo:     mockUserRepo,
+			}
+
+			// When
+			result, err := service.GetBlockingList(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
@@ -1,18 +1,25 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/repositories"
 	"S3_FriendManagement_ThinhNguyen/utils"
 )
 
 type IFriendService interface {
-	CreateFriend(*model.FriendsServiceInput) error
-	GetCommonFriendListByID([]int) ([]string, error)
-	GetFriendListByID(int) ([]string, error)
-	IsBlockedByOtherEmail(int, int) (bool, error)
-	IsExistedFriend(int, int) (bool, error)
-	GetEmailsReceiveUpdate(int, string) ([]string, error)
+	CreateFriend(context.Context, *model.FriendsServiceInput) error
+	CreateFriends(context.Context, []model.FriendsServiceInput) ([]error, error)
+	DeleteFriend(context.Context, *model.FriendsServiceInput) error
+	GetCommonFriendListByID(context.Context, []int) ([]string, error)
+	GetFriendListByID(context.Context, int) ([]string, error)
+	GetFriendListPageByID(context.Context, *model.FriendListServiceInput) (*model.FriendListPage, error)
+	GetFriendSuggestions(context.Context, int, int) ([]model.FriendSuggestion, error)
+	GetFriendPath(context.Context, int, int, int) ([]string, error)
+	IsBlockedByOtherEmail(context.Context, int, int) (bool, error)
+	IsExistedFriend(context.Context, int, int) (bool, error)
+	GetEmailsReceiveUpdate(context.Context, int, string) ([]string, error)
 }
 
 type FriendService struct {
@@ -20,7 +27,35 @@ type FriendService struct {
 	IUserRepo   repositories.IUserRepo
 }
 
-func (_self FriendService) CreateFriend(friendsServiceInput *model.FriendsServiceInput) error {
+func (_self FriendService) CreateFriend(ctx context.Context, friendsServiceInput *model.FriendsServiceInput) error {
+	//convert to repo input model
+	friendsRepoInput := &model.FriendsRepoInput{
+		FirstID:  friendsServiceInput.FirstID,
+		SecondID: friendsServiceInput.SecondID,
+	}
+
+	//Call repo
+	err := _self.IFriendRepo.CreateFriend(ctx, friendsRepoInput)
+	return err
+}
+
+// CreateFriends creates many friend connections at once and returns the outcome of each of them
+func (_self FriendService) CreateFriends(ctx context.Context, inputs []model.FriendsServiceInput) ([]error, error) {
+	//convert to repo input models
+	repoInputs := make([]model.FriendsRepoInput, len(inputs))
+	for i, input := range inputs {
+		repoInputs[i] = model.FriendsRepoInput{
+			FirstID:  input.FirstID,
+			SecondID: input.SecondID,
+		}
+	}
+
+	//Call repo
+	results, err := _self.IFriendRepo.CreateFriends(ctx, repoInputs)
+	return results, err
+}
+
+func (_self FriendService) DeleteFriend(ctx context.Context, friendsServiceInput *model.FriendsServiceInput) error {
 	//convert to repo input model
 	friendsRepoInput := &model.FriendsRepoInput{
 		FirstID:  friendsServiceInput.FirstID,
@@ -28,31 +63,85 @@ func (_self FriendService) CreateFriend(friendsServiceInput *model.FriendsServic
 	}
 
 	//Call repo
-	err := _self.IFriendRepo.CreateFriend(friendsRepoInput)
+	err := _self.IFriendRepo.DeleteFriend(ctx, friendsRepoInput)
 	return err
 }
 
-func (_self FriendService) GetFriendListByID(userID int) ([]string, error) {
+func (_self FriendService) GetFriendListByID(ctx context.Context, userID int) ([]string, error) {
+	//Get friend connections with no blocked
+	friendIDsNoBlock, _, err := _self.getFriendIDsNoBlock(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	friendEmails, err := _self.IUserRepo.GetEmailListByIDs(ctx, friendIDsNoBlock)
+	if err != nil {
+		return nil, err
+	}
+	return friendEmails, err
+}
+
+// GetFriendListPageByID returns one page of friends with no blocked, plus the cursor of the next page if there is one
+func (_self FriendService) GetFriendListPageByID(ctx context.Context, input *model.FriendListServiceInput) (*model.FriendListPage, error) {
+	//Fetch one extra friend to know whether there is a next page
+	friends, err := _self.IFriendRepo.GetFriendPageByID(ctx, &model.FriendListRepoInput{
+		UserID: input.UserID,
+		Limit:  input.Limit + 1,
+		Sort:   input.Sort,
+		Cursor: input.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := _self.IFriendRepo.CountFriendsByID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.FriendListPage{
+		Friends: make([]string, 0, len(friends)),
+		Total:   total,
+	}
+	if len(friends) > input.Limit {
+		friends = friends[:input.Limit]
+		last := friends[len(friends)-1]
+		page.NextCursor = model.EncodeFriendListCursor(model.FriendListCursor{
+			Sort:      input.Sort,
+			Email:     last.Email,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+	for _, friend := range friends {
+		page.Friends = append(page.Friends, friend.Email)
+	}
+	return page, nil
+}
+
+// getFriendIDsNoBlock returns the friend UserIDs of userID which are not blocked in either direction,
+// along with every UserID blocking or blocked by userID
+func (_self FriendService) getFriendIDsNoBlock(ctx context.Context, userID int) ([]int, map[int]bool, error) {
 	blockList := make(map[int]bool)
 	//Get all friend connection
-	friendIDs, err := _self.IFriendRepo.GetFriendListByID(userID)
+	friendIDs, err := _self.IFriendRepo.GetFriendListByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	//Get blocked UserIDs
-	blockedIDs, err := _self.IFriendRepo.GetBlockedListByID(userID)
+	blockedIDs, err := _self.IFriendRepo.GetBlockedListByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	for _, id := range blockedIDs {
 		blockList[id] = true
 	}
 
 	//Get blocking UserIDs
-	blockingIDs, err := _self.IFriendRepo.GetBlockingListByID(userID)
+	blockingIDs, err := _self.IFriendRepo.GetBlockingListByID(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	for _, id := range blockingIDs {
 		blockList[id] = true
@@ -65,57 +154,117 @@ func (_self FriendService) GetFriendListByID(userID int) ([]string, error) {
 			friendIDsNoBlock = append(friendIDsNoBlock, id)
 		}
 	}
+	return friendIDsNoBlock, blockList, nil
+}
+
+func (_self FriendService) GetFriendSuggestions(ctx context.Context, userID int, limit int) ([]model.FriendSuggestion, error) {
+	return _self.IFriendRepo.GetFriendSuggestions(ctx, userID, limit)
+}
+
+// GetFriendPath returns the emails on the shortest friend chain from fromID to toID,
+// or an empty list when the two users are not connected within maxDepth friend connections
+func (_self FriendService) GetFriendPath(ctx context.Context, fromID int, toID int, maxDepth int) ([]string, error) {
+	//Breadth-first search from fromID with one query per level, remembering how each UserID was reached
+	previous := map[int]int{fromID: 0}
+	currentLevel := []int{fromID}
+	found := false
+	for depth := 0; depth < maxDepth && len(currentLevel) > 0 && !found; depth++ {
+		links, err := _self.IFriendRepo.GetFriendLinksNoBlock(ctx, currentLevel)
+		if err != nil {
+			return nil, err
+		}
+		nextLevel := make([]int, 0)
+		for _, link := range links {
+			if _, visited := previous[link.FriendID]; visited {
+				continue
+			}
+			previous[link.FriendID] = link.UserID
+			if link.FriendID == toID {
+				found = true
+				break
+			}
+			nextLevel = append(nextLevel, link.FriendID)
+		}
+		currentLevel = nextLevel
+	}
+	if !found {
+		return []string{}, nil
+	}
+
+	//Walk back from toID to fromID
+	pathIDs := make([]int, 0)
+	for id := toID; id != fromID; id = previous[id] {
+		pathIDs = append([]int{id}, pathIDs...)
+	}
+	pathIDs = append([]int{fromID}, pathIDs...)
 
-	friendEmails, err := _self.IUserRepo.GetEmailListByIDs(friendIDsNoBlock)
+	//Get emails on the path
+	emailMap, err := _self.IUserRepo.GetEmailMapByIDs(ctx, pathIDs)
 	if err != nil {
 		return nil, err
 	}
-	return friendEmails, err
+	path := make([]string, len(pathIDs))
+	for i, id := range pathIDs {
+		path[i] = emailMap[id]
+	}
+	return path, nil
 }
 
-func (_self FriendService) IsBlockedByOtherEmail(firstUserID int, secondUserID int) (bool, error) {
-	isBlocked, err := _self.IFriendRepo.IsBlockedByOtherEmail(firstUserID, secondUserID)
+func (_self FriendService) IsBlockedByOtherEmail(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	isBlocked, err := _self.IFriendRepo.IsBlockedByOtherEmail(ctx, firstUserID, secondUserID)
 	return isBlocked, err
 }
 
-func (_self FriendService) IsExistedFriend(firstUserID int, secondUserID int) (bool, error) {
-	existed, err := _self.IFriendRepo.IsExistedFriend(firstUserID, secondUserID)
+func (_self FriendService) IsExistedFriend(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	existed, err := _self.IFriendRepo.IsExistedFriend(ctx, firstUserID, secondUserID)
 	return existed, err
 }
 
-func (_self FriendService) GetCommonFriendListByID(userIDList []int) ([]string, error) {
-	firstFriends, err := _self.GetFriendListByID(userIDList[0])
-	if err != nil {
-		return nil, err
+func (_self FriendService) GetCommonFriendListByID(ctx context.Context, userIDList []int) ([]string, error) {
+	commonFriends := make([]string, 0)
+	if len(userIDList) == 0 {
+		return commonFriends, nil
 	}
-	secondFriends, err := _self.GetFriendListByID(userIDList[1])
+
+	firstFriends, err := _self.GetFriendListByID(ctx, userIDList[0])
 	if err != nil {
 		return nil, err
 	}
+	commonFriends = append(commonFriends, firstFriends...)
 
-	//Get common friends
-	commonFriends := make([]string, 0)
-	commonMap := make(map[string]bool)
-	for _, firstEmail := range firstFriends {
-		commonMap[firstEmail] = true
-	}
+	//Keep only the friends shared with every other user
+	for _, userID := range userIDList[1:] {
+		if len(commonFriends) == 0 {
+			break
+		}
+		friends, err := _self.GetFriendListByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		friendMap := make(map[string]bool)
+		for _, email := range friends {
+			friendMap[email] = true
+		}
 
-	for _, secondEmail := range secondFriends {
-		if _, ok := commonMap[secondEmail]; ok {
-			commonFriends = append(commonFriends, secondEmail)
+		remainingFriends := make([]string, 0)
+		for _, email := range commonFriends {
+			if friendMap[email] {
+				remainingFriends = append(remainingFriends, email)
+			}
 		}
+		commonFriends = remainingFriends
 	}
 
 	return commonFriends, nil
 }
 
-func (_self FriendService) GetEmailsReceiveUpdate(senderID int, text string) ([]string, error) {
+func (_self FriendService) GetEmailsReceiveUpdate(ctx context.Context, senderID int, text string) ([]string, error) {
 	result := make([]string, 0)
 	resultIDs := make([]int, 0)
 	existedResultIDsMap := make(map[int]bool)
 	existedEmailsMap := make(map[string]bool)
 	//Get friend connections and subscribers with no blocked
-	friendSubscriberIDs, err := _self.IFriendRepo.GetEmailsFriendOrSubscribedWithNoBlocked(senderID)
+	friendSubscriberIDs, err := _self.IFriendRepo.GetEmailsFriendOrSubscribedWithNoBlocked(ctx, senderID)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +274,7 @@ func (_self FriendService) GetEmailsReceiveUpdate(senderID int, text string) ([]
 	}
 
 	//Get emails to return
-	emails, err := _self.IUserRepo.GetEmailListByIDs(resultIDs)
+	emails, err := _self.IUserRepo.GetEmailListByIDs(ctx, resultIDs)
 	if err != nil {
 		return nil, err
 	}
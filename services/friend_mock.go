@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockFriendRepo struct {
 	mock.Mock
 }
 
-func (_self mockFriendRepo) CreateFriend(friendsRepoInput *model.FriendsRepoInput) error {
+func (_self mockFriendRepo) CreateFriend(ctx context.Context, friendsRepoInput *model.FriendsRepoInput) error {
 	args := _self.Called(friendsRepoInput)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,26 @@ func (_self mockFriendRepo) CreateFriend(friendsRepoInput *model.FriendsRepoInpu
 	return r
 }
 
-func (_self mockFriendRepo) GetFriendListByID(userID int) ([]int, error) {
+func (_self mockFriendRepo) CreateFriends(ctx context.Context, inputs []model.FriendsRepoInput) ([]error, error) {
+	args := _self.Called(inputs)
+	r0 := args.Get(0).([]error)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRepo) DeleteFriend(ctx context.Context, friendsRepoInput *model.FriendsRepoInput) error {
+	args := _self.Called(friendsRepoInput)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRepo) GetFriendListByID(ctx context.Context, userID int) ([]int, error) {
 	args := _self.Called(userID)
 	r0 := args.Get(0).([]int)
 	var r1 error
@@ -28,9 +49,39 @@ func (_self mockFriendRepo) GetFriendListByID(userID int) ([]int, error) {
 	return r0, r1
 }
 
-func (_self mockFriendRepo) GetBlockedListByID(userID int) ([]int, error) {
+func (_self mockFriendRepo) GetFriendLinksNoBlock(ctx context.Context, userIDs []int) ([]model.FriendLink, error) {
+	args := _self.Called(userIDs)
+	r0 := args.Get(0).([]model.FriendLink)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRepo) GetFriendSuggestions(ctx context.Context, userID int, limit int) ([]model.FriendSuggestion, error) {
+	args := _self.Called(userID, limit)
+	r0 := args.Get(0).([]model.FriendSuggestion)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRepo) GetFriendPageByID(ctx context.Context, input *model.FriendListRepoInput) ([]model.FriendListItem, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).([]model.FriendListItem)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRepo) CountFriendsByID(ctx context.Context, userID int) (int, error) {
 	args := _self.Called(userID)
-	r0 := args.Get(0).([]int)
+	r0 := args.Get(0).(int)
 	var r1 error
 	if args.Get(1) != nil {
 		r1 = args.Get(1).(error)
@@ -38,7 +89,7 @@ func (_self mockFriendRepo) GetBlockedListByID(userID int) ([]int, error) {
 	return r0, r1
 }
 
-func (_self mockFriendRepo) GetBlockingListByID(userID int) ([]int, error) {
+func (_self mockFriendRepo) GetBlockedListByID(ctx context.Context, userID int) ([]int, error) {
 	args := _self.Called(userID)
 	r0 := args.Get(0).([]int)
 	var r1 error
@@ -48,9 +99,9 @@ func (_self mockFriendRepo) GetBlockingListByID(userID int) ([]int, error) {
 	return r0, r1
 }
 
-func (_self mockFriendRepo) IsBlockedByOtherEmail(firstUserID int, secondUserID int) (bool, error) {
-	args := _self.Called(firstUserID, secondUserID)
-	r0 := args.Get(0).(bool)
+func (_self mockFriendRepo) GetBlockingListByID(ctx context.Context, userID int) ([]int, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]int)
 	var r1 error
 	if args.Get(1) != nil {
 		r1 = args.Get(1).(error)
@@ -58,7 +109,7 @@ func (_self mockFriendRepo) IsBlockedByOtherEmail(firstUserID int, secondUserID
 	return r0, r1
 }
 
-func (_self mockFriendRepo) IsExistedFriend(firstUserID int, secondUserID int) (bool, error) {
+func (_self mockFriendRepo) IsBlockedByOtherEmail(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
 	args := _self.Called(firstUserID, secondUserID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -68,9 +119,9 @@ func (_self mockFriendRepo) IsExistedFriend(firstUserID int, secondUserID int) (
 	return r0, r1
 }
 
-func (_self mockFriendRepo) GetSubscriberList(userID int) ([]int, error) {
-	args := _self.Called(userID)
-	r0 := args.Get(0).([]int)
+func (_self mockFriendRepo) IsExistedFriend(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	args := _self.Called(firstUserID, secondUserID)
+	r0 := args.Get(0).(bool)
 	var r1 error
 	if args.Get(1) != nil {
 		r1 = args.Get(1).(error)
@@ -78,7 +129,7 @@ func (_self mockFriendRepo) GetSubscriberList(userID int) ([]int, error) {
 	return r0, r1
 }
 
-func (_self mockFriendRepo) GetEmailsFriendOrSubscribedWithNoBlocked(userID int) ([]int, error) {
+func (_self mockFriendRepo) GetEmailsFriendOrSubscribedWithNoBlocked(ctx context.Context, userID int) ([]int, error) {
 	args := _self.Called(userID)
 	r0 := args.Get(0).([]int)
 	var r1 error
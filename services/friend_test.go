@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/require"
@@ -53,7 +55,120 @@ func TestFriendService_CreateFriend(t *testing.T) {
 				IFriendRepo: mockFriendRepo,
 			}
 			//When
-			err := service.CreateFriend(testCase.input)
+			err := service.CreateFriend(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+
+	}
+}
+
+func TestFriendService_CreateFriends(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []model.FriendsServiceInput
+		expectedResult []error
+		expectedErr    error
+		mockRepoInput  []model.FriendsRepoInput
+		mockRepoResult []error
+		mockRepoError  error
+	}{
+		{
+			name:           "Create friends failed with error",
+			input:          []model.FriendsServiceInput{{FirstID: 1, SecondID: 2}},
+			expectedResult: nil,
+			expectedErr:    errors.New("create friends failed with error"),
+			mockRepoInput:  []model.FriendsRepoInput{{FirstID: 1, SecondID: 2}},
+			mockRepoResult: nil,
+			mockRepoError:  errors.New("create friends failed with error"),
+		},
+		{
+			name:           "Create friends success",
+			input:          []model.FriendsServiceInput{{FirstID: 1, SecondID: 2}, {FirstID: 3, SecondID: 4}},
+			expectedResult: []error{nil, model.ErrFriendExisted},
+			expectedErr:    nil,
+			mockRepoInput:  []model.FriendsRepoInput{{FirstID: 1, SecondID: 2}, {FirstID: 3, SecondID: 4}},
+			mockRepoResult: []error{nil, model.ErrFriendExisted},
+			mockRepoError:  nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockFriendRepo := new(mockFriendRepo)
+			mockFriendRepo.On("CreateFriends", testCase.mockRepoInput).
+				Return(testCase.mockRepoResult, testCase.mockRepoError)
+
+			service := FriendService{
+				IFriendRepo: mockFriendRepo,
+			}
+
+			// When
+			result, err := service.CreateFriends(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendService_DeleteFriend(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *model.FriendsServiceInput
+		expectedErr   error
+		mockRepoInput *model.FriendsRepoInput
+		mockRepoErr   error
+	}{
+		{
+			name: "Delete friend connection failed with error",
+			input: &model.FriendsServiceInput{
+				FirstID:  1,
+				SecondID: 2,
+			},
+			expectedErr: errors.New("delete friend connection failed with error"),
+			mockRepoInput: &model.FriendsRepoInput{
+				FirstID:  1,
+				SecondID: 2,
+			},
+			mockRepoErr: errors.New("delete friend connection failed with error"),
+		},
+		{
+			name: "Delete friend connection success",
+			input: &model.FriendsServiceInput{
+				FirstID:  1,
+				SecondID: 2,
+			},
+			expectedErr: nil,
+			mockRepoInput: &model.FriendsRepoInput{
+				FirstID:  1,
+				SecondID: 2,
+			},
+			mockRepoErr: nil,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockFriendRepo := new(mockFriendRepo)
+			mockFriendRepo.On("DeleteFriend", testCase.mockRepoInput).
+				Return(testCase.mockRepoErr)
+			service := FriendService{
+				IFriendRepo: mockFriendRepo,
+			}
+			//When
+			err := service.DeleteFriend(context.Background(), testCase.input)
 
 			//Then
 			if testCase.expectedErr != nil {
@@ -218,7 +333,122 @@ func TestFriendService_GetFriendListByID(t *testing.T) {
 			}
 
 			// When
-			result, err := service.GetFriendListByID(testCase.input)
+			result, err := service.GetFriendListByID(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendService_GetFriendListPageByID(t *testing.T) {
+	type mockGetFriendPageByID struct {
+		input  *model.FriendListRepoInput
+		result []model.FriendListItem
+		err    error
+	}
+	type mockCountFriendsByID struct {
+		input  int
+		result int
+		err    error
+	}
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name                  string
+		input                 *model.FriendListServiceInput
+		expectedResult        *model.FriendListPage
+		expectedErr           error
+		mockGetFriendPageByID mockGetFriendPageByID
+		mockCountFriendsByID  mockCountFriendsByID
+	}{
+		{
+			name:        "Get friend page failed with error",
+			input:       &model.FriendListServiceInput{UserID: 1, Limit: 2, Sort: model.FriendListSortEmail},
+			expectedErr: errors.New("get friend page failed with error"),
+			mockGetFriendPageByID: mockGetFriendPageByID{
+				input:  &model.FriendListRepoInput{UserID: 1, Limit: 3, Sort: model.FriendListSortEmail},
+				result: nil,
+				err:    errors.New("get friend page failed with error"),
+			},
+		},
+		{
+			name:        "Count friends failed with error",
+			input:       &model.FriendListServiceInput{UserID: 1, Limit: 2, Sort: model.FriendListSortEmail},
+			expectedErr: errors.New("count friends failed with error"),
+			mockGetFriendPageByID: mockGetFriendPageByID{
+				input:  &model.FriendListRepoInput{UserID: 1, Limit: 3, Sort: model.FriendListSortEmail},
+				result: []model.FriendListItem{},
+			},
+			mockCountFriendsByID: mockCountFriendsByID{
+				input: 1,
+				err:   errors.New("count friends failed with error"),
+			},
+		},
+		{
+			name:  "Last page has no next cursor",
+			input: &model.FriendListServiceInput{UserID: 1, Limit: 2, Sort: model.FriendListSortEmail},
+			expectedResult: &model.FriendListPage{
+				Friends: []string{"abc@example.com", "xyz@example.com"},
+				Total:   2,
+			},
+			mockGetFriendPageByID: mockGetFriendPageByID{
+				input: &model.FriendListRepoInput{UserID: 1, Limit: 3, Sort: model.FriendListSortEmail},
+				result: []model.FriendListItem{
+					{ID: 2, Email: "abc@example.com", CreatedAt: createdAt},
+					{ID: 3, Email: "xyz@example.com", CreatedAt: createdAt},
+				},
+			},
+			mockCountFriendsByID: mockCountFriendsByID{
+				input:  1,
+				result: 2,
+			},
+		},
+		{
+			name:  "Page with next cursor",
+			input: &model.FriendListServiceInput{UserID: 1, Limit: 1, Sort: model.FriendListSortCreated},
+			expectedResult: &model.FriendListPage{
+				Friends: []string{"xyz@example.com"},
+				NextCursor: model.EncodeFriendListCursor(model.FriendListCursor{
+					Sort:      model.FriendListSortCreated,
+					Email:     "xyz@example.com",
+					CreatedAt: createdAt,
+					ID:        3,
+				}),
+				Total: 2,
+			},
+			mockGetFriendPageByID: mockGetFriendPageByID{
+				input: &model.FriendListRepoInput{UserID: 1, Limit: 2, Sort: model.FriendListSortCreated},
+				result: []model.FriendListItem{
+					{ID: 3, Email: "xyz@example.com", CreatedAt: createdAt},
+					{ID: 2, Email: "abc@example.com", CreatedAt: createdAt.Add(time.Hour)},
+				},
+			},
+			mockCountFriendsByID: mockCountFriendsByID{
+				input:  1,
+				result: 2,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockFriendRepo := new(mockFriendRepo)
+			mockFriendRepo.On("GetFriendPageByID", testCase.mockGetFriendPageByID.input).
+				Return(testCase.mockGetFriendPageByID.result, testCase.mockGetFriendPageByID.err)
+			mockFriendRepo.On("CountFriendsByID", testCase.mockCountFriendsByID.input).
+				Return(testCase.mockCountFriendsByID.result, testCase.mockCountFriendsByID.err)
+
+			service := FriendService{
+				IFriendRepo: mockFriendRepo,
+			}
+
+			// When
+			result, err := service.GetFriendListPageByID(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -265,6 +495,10 @@ func TestFriendService_GetCommonFriendListByID(t *testing.T) {
 		mockGetBlockedListSecondUser  mockGetBlockedListByID
 		mockGetBlockingListSecondUser mockGetBlockingListByID
 		mockGetEmailsBySecondUSerList mockGetEmailListByIDs
+		mockGetFriendsListThirdUser   mockGetFriendListByID
+		mockGetBlockedListThirdUser   mockGetBlockedListByID
+		mockGetBlockingListThirdUser  mockGetBlockingListByID
+		mockGetEmailsByThirdUserList  mockGetEmailListByIDs
 	}{
 		{
 			name:           "Get first user's friend list failed with error",
@@ -354,6 +588,60 @@ func TestFriendService_GetCommonFriendListByID(t *testing.T) {
 				err:    nil,
 			},
 		},
+		{
+			name:           "Get common friend list of three users success",
+			input:          []int{1, 2, 3},
+			expectedResult: []string{"xyz@example.com"},
+			expectedErr:    nil,
+			mockGetFriendsListFirstUser: mockGetFriendListByID{
+				input:  1,
+				result: []int{10, 12},
+			},
+			mockGetBlockedListFirstUser: mockGetBlockedListByID{
+				input:  1,
+				result: []int{},
+			},
+			mockGetBlockingListFirstUser: mockGetBlockingListByID{
+				input:  1,
+				result: []int{},
+			},
+			mockGetEmailsByFirstUSerList: mockGetEmailListByIDs{
+				input:  []int{10, 12},
+				result: []string{"abc@example.com", "xyz@example.com"},
+			},
+			mockGetFriendsListSecondUser: mockGetFriendListByID{
+				input:  2,
+				result: []int{10, 11, 12},
+			},
+			mockGetBlockedListSecondUser: mockGetBlockedListByID{
+				input:  2,
+				result: []int{},
+			},
+			mockGetBlockingListSecondUser: mockGetBlockingListByID{
+				input:  2,
+				result: []int{},
+			},
+			mockGetEmailsBySecondUSerList: mockGetEmailListByIDs{
+				input:  []int{10, 11, 12},
+				result: []string{"abc@example.com", "mno@example.com", "xyz@example.com"},
+			},
+			mockGetFriendsListThirdUser: mockGetFriendListByID{
+				input:  3,
+				result: []int{11, 12},
+			},
+			mockGetBlockedListThirdUser: mockGetBlockedListByID{
+				input:  3,
+				result: []int{},
+			},
+			mockGetBlockingListThirdUser: mockGetBlockingListByID{
+				input:  3,
+				result: []int{},
+			},
+			mockGetEmailsByThirdUserList: mockGetEmailListByIDs{
+				input:  []int{11, 12},
+				result: []string{"mno@example.com", "xyz@example.com"},
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -379,13 +667,22 @@ func TestFriendService_GetCommonFriendListByID(t *testing.T) {
 			mockUserRepo.On("GetEmailListByIDs", testCase.mockGetEmailsBySecondUSerList.input).
 				Return(testCase.mockGetEmailsBySecondUSerList.result, testCase.mockGetEmailsBySecondUSerList.err)
 
+			mockFriendRepo.On("GetFriendListByID", testCase.mockGetFriendsListThirdUser.input).
+				Return(testCase.mockGetFriendsListThirdUser.result, testCase.mockGetFriendsListThirdUser.err)
+			mockFriendRepo.On("GetBlockedListByID", testCase.mockGetBlockedListThirdUser.input).
+				Return(testCase.mockGetBlockedListThirdUser.result, testCase.mockGetBlockedListThirdUser.err)
+			mockFriendRepo.On("GetBlockingListByID", testCase.mockGetBlockingListThirdUser.input).
+				Return(testCase.mockGetBlockingListThirdUser.result, testCase.mockGetBlockingListThirdUser.err)
+			mockUserRepo.On("GetEmailListByIDs", testCase.mockGetEmailsByThirdUserList.input).
+				Return(testCase.mockGetEmailsByThirdUserList.result, testCase.mockGetEmailsByThirdUserList.err)
+
 			services := FriendService{
 				IFriendRepo: mockFriendRepo,
 				IUserRepo:   mockUserRepo,
 			}
 
 			// When
-			result, err := services.GetCommonFriendListByID(testCase.input)
+			result, err := services.GetCommonFriendListByID(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -440,7 +737,7 @@ func TestFriendService_IsBlockedByOtherEmail(t *testing.T) {
 			}
 
 			// When
-			result, err := service.IsBlockedByOtherEmail(testCase.input[0], testCase.input[1])
+			result, err := service.IsBlockedByOtherEmail(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -495,7 +792,7 @@ func TestFriendService_IsExistedFriend(t *testing.T) {
 			}
 
 			// When
-			result, err := service.IsExistedFriend(testCase.input[0], testCase.input[1])
+			result, err := service.IsExistedFriend(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -576,6 +873,24 @@ func TestFriendService_GetEmailsReceiveUpdate(t *testing.T) {
 				err:    nil,
 			},
 		},
+		{
+			name:           "Mentions are normalized before matching recipients",
+			sender:         1,
+			text:           "hello XYZK@gmail.com, Another@Example.com and another@example.com",
+			expectedResult: []string{"xyzk@gmail.com", "mentioned@gmail.com", "another@example.com"},
+			expectedErr:    nil,
+			mockGetFriendsAndSubs: mockGetFriendsAndSubscribersByIDWithNoBlock{
+				input:  1,
+				result: []int{4, 5},
+				err:    nil,
+			},
+			requestorID: 1,
+			mockGetEmailsByIDs: mockGetEmailsByIDs{
+				input:  []int{4, 5},
+				result: []string{"xyzk@gmail.com", "mentioned@gmail.com"},
+				err:    nil,
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -595,7 +910,158 @@ func TestFriendService_GetEmailsReceiveUpdate(t *testing.T) {
 			}
 
 			// When
-			result, err := service.GetEmailsReceiveUpdate(testCase.sender, testCase.text)
+			result, err := service.GetEmailsReceiveUpdate(context.Background(), testCase.sender, testCase.text)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendService_GetFriendSuggestions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []int
+		expectedResult []model.FriendSuggestion
+		expectedErr    error
+		mockRepoResult []model.FriendSuggestion
+		mockRepoError  error
+	}{
+		{
+			name:           "Get suggestions failed with error",
+			input:          []int{1, 10},
+			expectedErr:    errors.New("get suggestions failed with error"),
+			mockRepoResult: nil,
+			mockRepoError:  errors.New("get suggestions failed with error"),
+		},
+		{
+			name:           "Get suggestions success",
+			input:          []int{1, 10},
+			expectedResult: []model.FriendSuggestion{{Email: "four@abc.com", MutualFriends: 2}, {Email: "five@abc.com", MutualFriends: 1}},
+			mockRepoResult: []model.FriendSuggestion{{Email: "four@abc.com", MutualFriends: 2}, {Email: "five@abc.com", MutualFriends: 1}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockFriendRepo := new(mockFriendRepo)
+			mockFriendRepo.On("GetFriendSuggestions", testCase.input[0], testCase.input[1]).
+				Return(testCase.mockRepoResult, testCase.mockRepoError)
+
+			service := FriendService{
+				IFriendRepo: mockFriendRepo,
+			}
+
+			// When
+			result, err := service.GetFriendSuggestions(context.Background(), testCase.input[0], testCase.input[1])
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestFriendService_GetFriendPath(t *testing.T) {
+	type mockGetFriendLinks struct {
+		input  []int
+		result []model.FriendLink
+		err    error
+	}
+	type mockGetEmailMapByIDs struct {
+		input  []int
+		result map[int]string
+		err    error
+	}
+	//1-2, 2-3, 3-4, 1-5, 5-4 where 5 blocks 4, one level per call
+	friendsGraph := []mockGetFriendLinks{
+		{input: []int{1}, result: []model.FriendLink{{UserID: 1, FriendID: 2}, {UserID: 1, FriendID: 5}}},
+		{input: []int{2, 5}, result: []model.FriendLink{{UserID: 2, FriendID: 1}, {UserID: 2, FriendID: 3}, {UserID: 5, FriendID: 1}}},
+		{input: []int{3}, result: []model.FriendLink{{UserID: 3, FriendID: 2}, {UserID: 3, FriendID: 4}}},
+	}
+	testCases := []struct {
+		name                 string
+		input                []int
+		expectedResult       []string
+		expectedErr          error
+		mockGetFriendLinks   []mockGetFriendLinks
+		mockGetEmailMapByIDs mockGetEmailMapByIDs
+	}{
+		{
+			name:        "Get friend links failed with error",
+			input:       []int{1, 4, 6},
+			expectedErr: errors.New("get friend links failed with error"),
+			mockGetFriendLinks: []mockGetFriendLinks{
+				{input: []int{1}, result: nil, err: errors.New("get friend links failed with error")},
+			},
+		},
+		{
+			name:               "Direct friends",
+			input:              []int{1, 2, 6},
+			expectedResult:     []string{"one@abc.com", "two@abc.com"},
+			mockGetFriendLinks: friendsGraph,
+			mockGetEmailMapByIDs: mockGetEmailMapByIDs{
+				input:  []int{1, 2},
+				result: map[int]string{1: "one@abc.com", 2: "two@abc.com"},
+			},
+		},
+		{
+			name:               "Shortest path skips blocked connection",
+			input:              []int{1, 4, 6},
+			expectedResult:     []string{"one@abc.com", "two@abc.com", "three@abc.com", "four@abc.com"},
+			mockGetFriendLinks: friendsGraph,
+			mockGetEmailMapByIDs: mockGetEmailMapByIDs{
+				input:  []int{1, 2, 3, 4},
+				result: map[int]string{1: "one@abc.com", 2: "two@abc.com", 3: "three@abc.com", 4: "four@abc.com"},
+			},
+		},
+		{
+			name:               "No path within max depth",
+			input:              []int{1, 4, 2},
+			expectedResult:     []string{},
+			mockGetFriendLinks: friendsGraph,
+		},
+		{
+			name:        "Get email map failed with error",
+			input:       []int{1, 2, 6},
+			expectedErr: errors.New("get email map failed with error"),
+			mockGetFriendLinks: []mockGetFriendLinks{
+				{input: []int{1}, result: []model.FriendLink{{UserID: 1, FriendID: 2}}},
+			},
+			mockGetEmailMapByIDs: mockGetEmailMapByIDs{
+				input:  []int{1, 2},
+				result: nil,
+				err:    errors.New("get email map failed with error"),
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockFriendRepo := new(mockFriendRepo)
+			mockUserRepo := new(mockUserRepo)
+			for _, m := range testCase.mockGetFriendLinks {
+				mockFriendRepo.On("GetFriendLinksNoBlock", m.input).Return(m.result, m.err)
+			}
+			mockUserRepo.On("GetEmailMapByIDs", testCase.mockGetEmailMapByIDs.input).
+				Return(testCase.mockGetEmailMapByIDs.result, testCase.mockGetEmailMapByIDs.err)
+
+			service := FriendService{
+				IFriendRepo: mockFriendRepo,
+				IUserRepo:   mockUserRepo,
+			}
+
+			// When
+			result, err := service.GetFriendPath(context.Background(), testCase.input[0], testCase.input[1], testCase.input[2])
 
 			// Then
 			if testCase.expectedErr != nil {
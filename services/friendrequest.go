@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
+)
+
+type IFriendRequestService interface {
+	CreateFriendRequest(context.Context, *model.FriendRequestServiceInput) error
+	AcceptFriendRequest(context.Context, *model.FriendRequestServiceInput) error
+	RejectFriendRequest(context.Context, *model.FriendRequestServiceInput) error
+	CancelFriendRequest(context.Context, *model.FriendRequestServiceInput) error
+	IsPendingFriendRequest(context.Context, int, int) (bool, error)
+	IsExistedFriend(context.Context, int, int) (bool, error)
+	IsBlockedByOtherEmail(context.Context, int, int) (bool, error)
+	GetIncomingFriendRequests(context.Context, int) ([]string, error)
+	GetOutgoingFriendRequests(context.Context, int) ([]string, error)
+}
+
+type FriendRequestService struct {
+	IFriendRequestRepo repositories.IFriendRequestRepo
+	IFriendRepo        repositories.IFriendRepo
+	IUserRepo          repositories.IUserRepo
+}
+
+func (_self FriendRequestService) CreateFriendRequest(ctx context.Context, friendRequestServiceInput *model.FriendRequestServiceInput) error {
+	//Create repo input model
+	repoInput := &model.FriendRequestRepoInput{
+		Requestor: friendRequestServiceInput.Requestor,
+		Target:    friendRequestServiceInput.Target,
+		Status:    model.FriendRequestStatusPending,
+	}
+	err := _self.IFriendRequestRepo.CreateFriendRequest(ctx, repoInput)
+	return err
+}
+
+func (_self FriendRequestService) AcceptFriendRequest(ctx context.Context, friendRequestServiceInput *model.FriendRequestServiceInput) error {
+	//Create repo input model
+	repoInput := &model.FriendRequestRepoInput{
+		Requestor: friendRequestServiceInput.Requestor,
+		Target:    friendRequestServiceInput.Target,
+		Status:    model.FriendRequestStatusAccepted,
+	}
+
+	//Close the pending request and create friend connection together
+	err := _self.IFriendRequestRepo.AcceptFriendRequest(ctx, repoInput)
+	return err
+}
+
+func (_self FriendRequestService) RejectFriendRequest(ctx context.Context, friendRequestServiceInput *model.FriendRequestServiceInput) error {
+	return _self.updateFriendRequestStatus(ctx, friendRequestServiceInput, model.FriendRequestStatusRejected)
+}
+
+func (_self FriendRequestService) CancelFriendRequest(ctx context.Context, friendRequestServiceInput *model.FriendRequestServiceInput) error {
+	return _self.updateFriendRequestStatus(ctx, friendRequestServiceInput, model.FriendRequestStatusCancelled)
+}
+
+func (_self FriendRequestService) updateFriendRequestStatus(ctx context.Context, friendRequestServiceInput *model.FriendRequestServiceInput, status string) error {
+	//Create repo input model
+	repoInput := &model.FriendRequestRepoInput{
+		Requestor: friendRequestServiceInput.Requestor,
+		Target:    friendRequestServiceInput.Target,
+		Status:    status,
+	}
+	err := _self.IFriendRequestRepo.UpdateFriendRequestStatus(ctx, repoInput)
+	return err
+}
+
+func (_self FriendRequestService) IsPendingFriendRequest(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	pending, err := _self.IFriendRequestRepo.IsPendingFriendRequest(ctx, requestorID, targetID)
+	return pending, err
+}
+
+func (_self FriendRequestService) IsExistedFriend(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	existed, err := _self.IFriendRepo.IsExistedFriend(ctx, firstUserID, secondUserID)
+	return existed, err
+}
+
+func (_self FriendRequestService) IsBlockedByOtherEmail(ctx context.Context, firstUserID int, secondUserID int) (bool, error) {
+	blocked, err := _self.IFriendRepo.IsBlockedByOtherEmail(ctx, firstUserID, secondUserID)
+	return blocked, err
+}
+
+func (_self FriendRequestService) GetIncomingFriendRequests(ctx context.Context, userID int) ([]string, error) {
+	requestorIDs, err := _self.IFriendRequestRepo.GetIncomingFriendRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := _self.IUserRepo.GetEmailListByIDs(ctx, requestorIDs)
+	return emails, err
+}
+
+func (_self FriendRequestService) GetOutgoingFriendRequests(ctx context.Context, userID int) ([]string, error) {
+	targetIDs, err := _self.IFriendRequestRepo.GetOutgoingFriendRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := _self.IUserRepo.GetEmailListByIDs(ctx, targetIDs)
+	return emails, err
+}
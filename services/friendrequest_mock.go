@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockFriendRequestRepo struct {
+	mock.Mock
+}
+
+func (_self mockFriendRequestRepo) CreateFriendRequest(ctx context.Context, input *model.FriendRequestRepoInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestRepo) UpdateFriendRequestStatus(ctx context.Context, input *model.FriendRequestRepoInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestRepo) AcceptFriendRequest(ctx context.Context, input *model.FriendRequestRepoInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockFriendRequestRepo) IsPendingFriendRequest(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	args := _self.Called(requestorID, targetID)
+	r0 := args.Get(0).(bool)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRequestRepo) GetIncomingFriendRequests(ctx context.Context, userID int) ([]int, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockFriendRequestRepo) GetOutgoingFriendRequests(ctx context.Context, userID int) ([]int, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
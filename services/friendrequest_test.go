@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFriendRequestService_CreateFriendRequest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *model.FriendRequestServiceInput
+		expectedErr   error
+		mockRepoInput *model.FriendRequestRepoInput
+		mockRepoErr   error
+	}{
+		{
+			name: "Create friend request failed with error",
+			input: &model.FriendRequestServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: errors.New("create friend request failed with error"),
+			mockRepoInput: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    2,
+				Status:    model.FriendRequestStatusPending,
+			},
+			mockRepoErr: errors.New("create friend request failed with error"),
+		},
+		{
+			name: "Create friend request success",
+			input: &model.FriendRequestServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: nil,
+			mockRepoInput: &model.FriendRequestRepoInput{
+				Requestor: 1,
+				Target:    2,
+				Status:    model.FriendRequestStatusPending,
+			},
+			mockRepoErr: nil,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockFriendRequestRepo := new(mockFriendRequestRepo)
+			mockFriendRequestRepo.On("CreateFriendRequest", testCase.mockRepoInput).
+				Return(testCase.mockRepoErr)
+			service := FriendRequestService{
+				IFriendRequestRepo: mockFriendRequestRepo,
+			}
+
+			//When
+			err := service.CreateFriendRequest(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFriendRequestService_AcceptFriendRequest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *model.FriendRequestServiceInput
+		expectedErr   error
+		mockAcceptErr error
+	}{
+		{
+			name: "Accept friend request failed with error",
+			input: &model.FriendRequestServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr:   errors.New("accept friend request failed with error"),
+			mockAcceptErr: errors.New("accept friend request failed with error"),
+		},
+		{
+			name: "Blocked after the request was checked",
+			input: &model.FriendRequestServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr:   model.ErrBlockedEachOther,
+			mockAcceptErr: model.ErrBlockedEachOther,
+		},
+		{
+			name: "Accept friend request success",
+			input: &model.FriendRequestServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedErr: nil,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockFriendRequestRepo := new(mockFriendRequestRepo)
+			mockFriendRequestRepo.On("AcceptFriendRequest", &model.FriendRequestRepoInput{
+				Requestor: testCase.input.Requestor,
+				Target:    testCase.input.Target,
+				Status:    model.FriendRequestStatusAccepted,
+			}).Return(testCase.mockAcceptErr)
+			service := FriendRequestService{
+				IFriendRequestRepo: mockFriendRequestRepo,
+			}
+
+			//When
+			err := service.AcceptFriendRequest(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFriendRequestService_RejectFriendRequest(t *testing.T) {
+	//Given
+	mockFriendRequestRepo := new(mockFriendRequestRepo)
+	mockFriendRequestRepo.On("UpdateFriendRequestStatus", &model.FriendRequestRepoInput{
+		Requestor: 1,
+		Target:    2,
+		Status:    model.FriendRequestStatusRejected,
+	}).Return(nil)
+	mockFriendRepo := new(mockFriendRepo)
+	service := FriendRequestService{
+		IFriendRequestRepo: mockFriendRequestRepo,
+		IFriendRepo:        mockFriendRepo,
+	}
+
+	//When
+	err := service.RejectFriendRequest(context.Background(), &model.FriendRequestServiceInput{Requestor: 1, Target: 2})
+
+	//Then
+	require.NoError(t, err)
+}
+
+func TestFriendRequestService_GetIncomingFriendRequests(t *testing.T) {
+	type mockGetIncoming struct {
+		input  int
+		result []int
+		err    error
+	}
+	type mockGetEmailListByIDs struct {
+		input  []int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name             string
+		input            int
+		expectedResult   []string
+		expectedErr      error
+		mockGetIncoming  mockGetIncoming
+		mockGetEmailList mockGetEmailListByIDs
+	}{
+		{
+			name:        "Get incoming friend requests failed with error",
+			input:       1,
+			expectedErr: errors.New("get incoming failed with error"),
+			mockGetIncoming: mockGetIncoming{
+				input:  1,
+				result: nil,
+				err:    errors.New("get incoming failed with error"),
+			},
+		},
+		{
+			name:        "Get email list failed with error",
+			input:       1,
+			expectedErr: errors.New("get email list failed with error"),
+			mockGetIncoming: mockGetIncoming{
+				input:  1,
+				result: []int{2, 3},
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{2, 3},
+				result: nil,
+				err:    errors.New("get email list failed with error"),
+			},
+		},
+		{
+			name:           "Get incoming friend requests success",
+			input:          1,
+			expectedResult: []string{"abc@xyz.com", "xyz@abc.com"},
+			mockGetIncoming: mockGetIncoming{
+				input:  1,
+				result: []int{2, 3},
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{2, 3},
+				result: []string{"abc@xyz.com", "xyz@abc.com"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockFriendRequestRepo := new(mockFriendRequestRepo)
+			mockUserRepo := new(mockUserRepo)
+			mockFriendRequestRepo.On("GetIncomingFriendRequests", testCase.mockGetIncoming.input).
+				Return(testCase.mockGetIncoming.result, testCase.mockGetIncoming.err)
+			mockUserRepo.On("GetEmailListByIDs", testCase.mockGetEmailList.input).
+				Return(testCase.mockGetEmailList.result, testCase.mockGetEmailList.err)
+			service := FriendRequestService{
+				IFriendRequestRepo: mockFriendRequestRepo,
+				IUserRepo:          mockUserRepo,
+			}
+
+			//When
+			result, err := service.GetIncomingFriendRequests(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
+)
+
+type IGraphService interface {
+	ExportGraph(ctx context.Context, writer model.GraphWriter) error
+	ImportGraph(ctx context.Context, graph *model.Graph) error
+}
+
+type GraphService struct {
+	IGraphRepo repositories.IGraphRepo
+}
+
+func (_self GraphService) ExportGraph(ctx context.Context, writer model.GraphWriter) error {
+	return _self.IGraphRepo.ExportGraph(ctx, writer)
+}
+
+// ImportGraph checks the graph against the schema before rebuilding an empty database from it
+func (_self GraphService) ImportGraph(ctx context.Context, graph *model.Graph) error {
+	if err := graph.Validate(); err != nil {
+		return err
+	}
+	return _self.IGraphRepo.ImportGraph(ctx, graph)
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockGraphRepo struct {
+	mock.Mock
+}
+
+func (_self mockGraphRepo) ExportGraph(ctx context.Context, writer model.GraphWriter) error {
+	args := _self.Called(writer)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockGraphRepo) ImportGraph(ctx context.Context, graph *model.Graph) error {
+	args := _self.Called(graph)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/graphio"
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphService_ExportGraph(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectedErr error
+		mockRepoErr error
+	}{
+		{
+			name:        "Export graph failed with error",
+			expectedErr: errors.New("export graph failed with error"),
+			mockRepoErr: errors.New("export graph failed with error"),
+		},
+		{
+			name: "Export graph success",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			writer := graphio.NewJSONWriter(new(bytes.Buffer))
+			mockGraphRepo := new(mockGraphRepo)
+			mockGraphRepo.On("ExportGraph", writer).
+				Return(testCase.mockRepoErr)
+
+			service := GraphService{
+				IGraphRepo: mockGraphRepo,
+			}
+
+			//When
+			err := service.ExportGraph(context.Background(), writer)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGraphService_ImportGraph(t *testing.T) {
+	users := []model.GraphUser{
+		{ID: 1, Email: "abc@email.com", Status: model.UserStatusActive},
+		{ID: 2, Email: "xyz@email.com", Status: model.UserStatusActive},
+	}
+	testCases := []struct {
+		name          string
+		input         *model.Graph
+		expectedErr   error
+		mockRepoInput *model.Graph
+		mockRepoErr   error
+	}{
+		{
+			name:        "Email is repeated",
+			input:       &model.Graph{Users: []model.GraphUser{{ID: 1, Email: "abc@email.com"}, {ID: 2, Email: " ABC@email.com"}}},
+			expectedErr: errors.New("users[1]: email \"abc@email.com\" is already the one of user 1"),
+		},
+		{
+			name:        "Status is not valid",
+			input:       &model.Graph{Users: []model.GraphUser{{ID: 1, Email: "abc@email.com", Status: "gone"}}},
+			expectedErr: errors.New("users[0]: status must be \"active\", \"away\" or \"busy\""),
+		},
+		{
+			name:        "Edge refers to an unknown user",
+			input:       &model.Graph{Users: users, Subscriptions: []model.GraphEdge{{Source: 1, Target: 3}}},
+			expectedErr: errors.New("subscriptions[0]: user 3 does not exist"),
+		},
+		{
+			name:        "Friend connection is repeated in reverse",
+			input:       &model.Graph{Users: users, Friends: []model.GraphEdge{{Source: 1, Target: 2}, {Source: 2, Target: 1}}},
+			expectedErr: errors.New("friends[1]: users 2 and 1 are already linked"),
+		},
+		{
+			name:          "Import graph failed with error",
+			input:         &model.Graph{Users: users, Blocks: []model.GraphEdge{{Source: 1, Target: 2}, {Source: 2, Target: 1}}},
+			expectedErr:   model.ErrGraphDatabaseNotEmpty,
+			mockRepoInput: &model.Graph{Users: users, Blocks: []model.GraphEdge{{Source: 1, Target: 2}, {Source: 2, Target: 1}}},
+			mockRepoErr:   model.ErrGraphDatabaseNotEmpty,
+		},
+		{
+			name:          "Import graph success",
+			input:         &model.Graph{Users: []model.GraphUser{{ID: 1, Email: " ABC@email.com"}}},
+			mockRepoInput: &model.Graph{Users: []model.GraphUser{{ID: 1, Email: "abc@email.com", Status: model.UserStatusActive}}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockGraphRepo := new(mockGraphRepo)
+			mockGraphRepo.On("ImportGraph", testCase.mockRepoInput).
+				Return(testCase.mockRepoErr)
+
+			service := GraphService{
+				IGraphRepo: mockGraphRepo,
+			}
+
+			//When
+			err := service.ImportGraph(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,36 +1,89 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/repositories"
 )
 
 type ISubscriptionService interface {
-	CreateSubscription(*model.SubscriptionServiceInput) error
-	IsExistedSubscription(int, int) (bool, error)
-	IsBlockedByOtherEmail(int, int) (bool, error)
+	CreateSubscription(context.Context, *model.SubscriptionServiceInput) error
+	CreateSubscriptions(context.Context, []model.SubscriptionServiceInput) ([]error, error)
+	DeleteSubscription(context.Context, *model.SubscriptionServiceInput) error
+	IsExistedSubscription(context.Context, int, int) (bool, error)
+	IsBlockedByOtherEmail(context.Context, int, int) (bool, error)
+	GetSubscriberList(context.Context, int) ([]string, error)
+	GetFollowingList(context.Context, int) ([]string, error)
 }
 
 type SubscriptionService struct {
 	ISubscriptionRepo repositories.ISubscriptionRepo
+	IUserRepo         repositories.IUserRepo
+}
+
+func (_self SubscriptionService) CreateSubscription(ctx context.Context, subscriptionServiceInput *model.SubscriptionServiceInput) error {
+	//Create repo input model
+	repoInput := &model.SubscriptionRepoInput{
+		Requestor: subscriptionServiceInput.Requestor,
+		Target:    subscriptionServiceInput.Target,
+	}
+	err := _self.ISubscriptionRepo.CreateSubscription(ctx, repoInput)
+	return err
+}
+
+// CreateSubscriptions creates many subscriptions at once and returns the outcome of each of them
+func (_self SubscriptionService) CreateSubscriptions(ctx context.Context, inputs []model.SubscriptionServiceInput) ([]error, error) {
+	//Create repo input models
+	repoInputs := make([]model.SubscriptionRepoInput, len(inputs))
+	for i, input := range inputs {
+		repoInputs[i] = model.SubscriptionRepoInput{
+			Requestor: input.Requestor,
+			Target:    input.Target,
+		}
+	}
+	results, err := _self.ISubscriptionRepo.CreateSubscriptions(ctx, repoInputs)
+	return results, err
 }
 
-func (_self SubscriptionService) CreateSubscription(subscriptionServiceInput *model.SubscriptionServiceInput) error {
+func (_self SubscriptionService) DeleteSubscription(ctx context.Context, subscriptionServiceInput *model.SubscriptionServiceInput) error {
 	//Create repo input model
 	repoInput := &model.SubscriptionRepoInput{
 		Requestor: subscriptionServiceInput.Requestor,
 		Target:    subscriptionServiceInput.Target,
 	}
-	err := _self.ISubscriptionRepo.CreateSubscription(repoInput)
+	err := _self.ISubscriptionRepo.DeleteSubscription(ctx, repoInput)
 	return err
 }
 
-func (_self SubscriptionService) IsExistedSubscription(requestorID int, targetID int) (bool, error) {
-	exist, err := _self.ISubscriptionRepo.IsExistedSubscription(requestorID, targetID)
+func (_self SubscriptionService) IsExistedSubscription(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	exist, err := _self.ISubscriptionRepo.IsExistedSubscription(ctx, requestorID, targetID)
 	return exist, err
 }
 
-func (_self SubscriptionService) IsBlockedByOtherEmail(requestorID int, targetID int) (bool, error) {
-	blocked, err := _self.ISubscriptionRepo.IsBlockedByOtherEmail(requestorID, targetID)
+func (_self SubscriptionService) IsBlockedByOtherEmail(ctx context.Context, requestorID int, targetID int) (bool, error) {
+	blocked, err := _self.ISubscriptionRepo.IsBlockedByOtherEmail(ctx, requestorID, targetID)
 	return blocked, err
 }
+
+func (_self SubscriptionService) GetSubscriberList(ctx context.Context, userID int) ([]string, error) {
+	//Get UserIDs which subscribe to the user
+	subscriberIDs, err := _self.ISubscriptionRepo.GetSubscriberList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emails, err := _self.IUserRepo.GetEmailListByIDs(ctx, subscriberIDs)
+	return emails, err
+}
+
+func (_self SubscriptionService) GetFollowingList(ctx context.Context, userID int) ([]string, error) {
+	//Get UserIDs which the user subscribes to
+	targetIDs, err := _self.ISubscriptionRepo.GetFollowingList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emails, err := _self.IUserRepo.GetEmailListByIDs(ctx, targetIDs)
+	return emails, err
+}
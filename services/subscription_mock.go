@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockSubscriptionRepo struct {
 	mock.Mock
 }
 
-func (_self mockSubscriptionRepo) CreateSubscription(model *model.SubscriptionRepoInput) error {
+func (_self mockSubscriptionRepo) CreateSubscription(ctx context.Context, model *model.SubscriptionRepoInput) error {
 	args := _self.Called(model)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,17 @@ func (_self mockSubscriptionRepo) CreateSubscription(model *model.SubscriptionRe
 	return r
 }
 
-func (_self mockSubscriptionRepo) IsExistedSubscription(requestorID int, targetID int) (bool, error) {
+func (_self mockSubscriptionRepo) CreateSubscriptions(ctx context.Context, inputs []model.SubscriptionRepoInput) ([]error, error) {
+	args := _self.Called(inputs)
+	r0 := args.Get(0).([]error)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockSubscriptionRepo) IsExistedSubscription(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	args := _self.Called(requestorID, targetID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -28,7 +40,7 @@ func (_self mockSubscriptionRepo) IsExistedSubscription(requestorID int, targetI
 	return r0, r1
 }
 
-func (_self mockSubscriptionRepo) IsBlockedByOtherEmail(requestorID int, targetID int) (bool, error) {
+func (_self mockSubscriptionRepo) IsBlockedByOtherEmail(ctx context.Context, requestorID int, targetID int) (bool, error) {
 	args := _self.Called(requestorID, targetID)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -37,3 +49,32 @@ func (_self mockSubscriptionRepo) IsBlockedByOtherEmail(requestorID int, targetI
 	}
 	return r0, r1
 }
+
+func (_self mockSubscriptionRepo) DeleteSubscription(ctx context.Context, model *model.SubscriptionRepoInput) error {
+	args := _self.Called(model)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockSubscriptionRepo) GetSubscriberList(ctx context.Context, userID int) ([]int, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockSubscriptionRepo) GetFollowingList(ctx context.Context, userID int) ([]int, error) {
+	args := _self.Called(userID)
+	r0 := args.Get(0).([]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -56,7 +57,7 @@ func TestSubscriptionService_CreateSubscription(t *testing.T) {
 			}
 
 			// When
-			err := service.CreateSubscription(testCase.input)
+			err := service.CreateSubscription(context.Background(), testCase.input)
 
 			// Then
 			if testCase.expectedError != nil {
@@ -68,6 +69,61 @@ func TestSubscriptionService_CreateSubscription(t *testing.T) {
 	}
 }
 
+func TestSubscriptionService_CreateSubscriptions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []model.SubscriptionServiceInput
+		expectedResult []error
+		expectedErr    error
+		mockRepoInput  []model.SubscriptionRepoInput
+		mockRepoResult []error
+		mockRepoError  error
+	}{
+		{
+			name:           "Create subscriptions failed with error",
+			input:          []model.SubscriptionServiceInput{{Requestor: 1, Target: 2}},
+			expectedResult: nil,
+			expectedErr:    errors.New("create subscriptions failed with error"),
+			mockRepoInput:  []model.SubscriptionRepoInput{{Requestor: 1, Target: 2}},
+			mockRepoResult: nil,
+			mockRepoError:  errors.New("create subscriptions failed with error"),
+		},
+		{
+			name:           "Create subscriptions success",
+			input:          []model.SubscriptionServiceInput{{Requestor: 1, Target: 2}, {Requestor: 3, Target: 4}},
+			expectedResult: []error{nil, model.ErrSubscriptionExisted},
+			expectedErr:    nil,
+			mockRepoInput:  []model.SubscriptionRepoInput{{Requestor: 1, Target: 2}, {Requestor: 3, Target: 4}},
+			mockRepoResult: []error{nil, model.ErrSubscriptionExisted},
+			mockRepoError:  nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockSubscriptionRepo := new(mockSubscriptionRepo)
+			mockSubscriptionRepo.On("CreateSubscriptions", testCase.mockRepoInput).
+				Return(testCase.mockRepoResult, testCase.mockRepoError)
+
+			service := SubscriptionService{
+				ISubscriptionRepo: mockSubscriptionRepo,
+			}
+
+			// When
+			result, err := service.CreateSubscriptions(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
 func TestSubscriptionService_IsExistedSubscription(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -119,7 +175,7 @@ func TestSubscriptionService_IsExistedSubscription(t *testing.T) {
 			}
 
 			// When
-			result, err := service.IsExistedSubscription(testCase.input[0], testCase.input[1])
+			result, err := service.IsExistedSubscription(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedErr != nil {
@@ -174,7 +230,7 @@ func TestSubscriptionService_IsBlockedByOtherEmail(t *testing.T) {
 			}
 
 			// When
-			result, err := service.IsBlockedByOtherEmail(testCase.input[0], testCase.input[1])
+			result, err := service.IsBlockedByOtherEmail(context.Background(), testCase.input[0], testCase.input[1])
 
 			// Then
 			if testCase.expectedError != nil {
@@ -186,3 +242,223 @@ func TestSubscriptionService_IsBlockedByOtherEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionService_DeleteSubscription(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *model.SubscriptionServiceInput
+		expectedError error
+		mockRepoInput *model.SubscriptionRepoInput
+		mockRepoError error
+	}{
+		{
+			name: "delete subscription failed",
+			input: &model.SubscriptionServiceInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			expectedError: errors.New("delete failed with error"),
+			mockRepoInput: &model.SubscriptionRepoInput{
+				Requestor: 1,
+				Target:    2,
+			},
+			mockRepoError: errors.New("delete failed with error"),
+		},
+		{
+			name: "delete subscription successfully",
+			input: &model.SubscriptionServiceInput{
+				Requestor: 3,
+				Target:    4,
+			},
+			expectedError: nil,
+			mockRepoInput: &model.SubscriptionRepoInput{
+				Requestor: 3,
+				Target:    4,
+			},
+			mockRepoError: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockSubscriptionRepo := new(mockSubscriptionRepo)
+			mockSubscriptionRepo.On("DeleteSubscription", testCase.mockRepoInput).
+				Return(testCase.mockRepoError)
+
+			service := SubscriptionService{
+				ISubscriptionRepo: mockSubscriptionRepo,
+			}
+
+			// When
+			err := service.DeleteSubscription(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSubscriptionService_GetSubscriberList(t *testing.T) {
+	type mockGetIDList struct {
+		input  int
+		result []int
+		err    error
+	}
+	type mockGetEmailListByIDs struct {
+		input  []int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name              string
+		input             int
+		expectedResult    []string
+		expectedError     error
+		mockGetSubscriber mockGetIDList
+		mockGetEmailList  mockGetEmailListByIDs
+	}{
+		{
+			name:          "get subscriber list failed",
+			input:         1,
+			expectedError: errors.New("get subscriber list failed with error"),
+			mockGetSubscriber: mockGetIDList{
+				input:  1,
+				result: nil,
+				err:    errors.New("get subscriber list failed with error"),
+			},
+		},
+		{
+			name:          "get email list failed",
+			input:         1,
+			expectedError: errors.New("get email list failed with error"),
+			mockGetSubscriber: mockGetIDList{
+				input:  1,
+				result: []int{2},
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{2},
+				result: nil,
+				err:    errors.New("get email list failed with error"),
+			},
+		},
+		{
+			name:           "get subscriber list successfully",
+			input:          1,
+			expectedResult: []string{"xyz@abc.com"},
+			mockGetSubscriber: mockGetIDList{
+				input:  1,
+				result: []int{2},
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{2},
+				result: []string{"xyz@abc.com"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockSubscriptionRepo := new(mockSubscriptionRepo)
+			mockUserRepo := new(mockUserRepo)
+			mockSubscriptionRepo.On("GetSubscriberList", testCase.mockGetSubscriber.input).
+				Return(testCase.mockGetSubscriber.result, testCase.mockGetSubscriber.err)
+			mockUserRepo.On("GetEmailListByIDs", testCase.mockGetEmailList.input).
+				Return(testCase.mockGetEmailList.result, testCase.mockGetEmailList.err)
+
+			service := SubscriptionService{
+				ISubscriptionRepo: mockSubscriptionRepo,
+				IUserRepo:         mockUserRepo,
+			}
+
+			// When
+			result, err := service.GetSubscriberList(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestSubscriptionService_GetFollowingList(t *testing.T) {
+	type mockGetIDList struct {
+		input  int
+		result []int
+		err    error
+	}
+	type mockGetEmailListByIDs struct {
+		input  []int
+		result []string
+		err    error
+	}
+	testCases := []struct {
+		name             string
+		input            int
+		expectedResult   []string
+		expectedError    error
+		mockGetFollowing mockGetIDList
+		mockGetEmailList mockGetEmailListByIDs
+	}{
+		{
+			name:          "get following list failed",
+			input:         2,
+			expectedError: errors.New("get following list failed with error"),
+			mockGetFollowing: mockGetIDList{
+				input:  2,
+				result: nil,
+				err:    errors.New("get following list failed with error"),
+			},
+		},
+		{
+			name:           "get following list successfully",
+			input:          2,
+			expectedResult: []string{"abc@xyz.com"},
+			mockGetFollowing: mockGetIDList{
+				input:  2,
+				result: []int{1},
+			},
+			mockGetEmailList: mockGetEmailListByIDs{
+				input:  []int{1},
+				result: []string{"abc@xyz.com"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockSubscriptionRepo := new(mockSubscriptionRepo)
+			mockUserRepo := new(mockUserRepo)
+			mockSubscriptionRepo.On("GetFollowingList", testCase.mockGetFollowing.input).
+				Return(testCase.mockGetFollowing.result, testCase.mockGetFollowing.err)
+			mockUserRepo.On("GetEmailListByIDs", testCase.mockGetEmailList.input).
+				Return(testCase.mockGetEmailList.result, testCase.mockGetEmailList.err)
+
+			service := SubscriptionService{
+				ISubscriptionRepo: mockSubscriptionRepo,
+				IUserRepo:         mockUserRepo,
+			}
+
+			// When
+			result, err := service.GetFollowingList(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedError != nil {
+				require.EqualError(t, err, testCase.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
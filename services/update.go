@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+type IUpdateService interface {
+	CreateUpdate(context.Context, *model.UpdateServiceInput) (int, error)
+	GetFeed(context.Context, *model.FeedServiceInput) (*model.FeedPage, error)
+	MarkUpdatesAsRead(context.Context, int, []int) error
+}
+
+type UpdateService struct {
+	IUpdateRepo repositories.IUpdateRepo
+}
+
+func (_self UpdateService) CreateUpdate(ctx context.Context, update *model.UpdateServiceInput) (int, error) {
+	//Create repo input model
+	updateRepoInput := &model.UpdateRepoInput{
+		SenderID:   update.SenderID,
+		Sender:     update.Sender,
+		Text:       update.Text,
+		Mentions:   utils.FindEmailFromText(update.Text),
+		Recipients: update.Recipients,
+	}
+	updateID, err := _self.IUpdateRepo.CreateUpdate(ctx, updateRepoInput)
+	return updateID, err
+}
+
+// GetFeed returns one page of the user's feed, plus the cursor of the next page if there is one
+func (_self UpdateService) GetFeed(ctx context.Context, input *model.FeedServiceInput) (*model.FeedPage, error) {
+	//Fetch one extra update to know whether there is a next page
+	feed, err := _self.IUpdateRepo.GetFeedByID(ctx, &model.FeedRepoInput{
+		UserID: input.UserID,
+		Limit:  input.Limit + 1,
+		Cursor: input.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.FeedPage{
+		Updates: feed,
+	}
+	if len(feed) > input.Limit {
+		page.Updates = feed[:input.Limit]
+		last := page.Updates[len(page.Updates)-1]
+		page.NextCursor = model.EncodeFeedCursor(model.FeedCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.UpdateID,
+		})
+	}
+	return page, nil
+}
+
+func (_self UpdateService) MarkUpdatesAsRead(ctx context.Context, userID int, updateIDs []int) error {
+	err := _self.IUpdateRepo.MarkUpdatesAsRead(ctx, userID, updateIDs)
+	return err
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUpdateRepo struct {
+	mock.Mock
+}
+
+func (_self mockUpdateRepo) CreateUpdate(ctx context.Context, input *model.UpdateRepoInput) (int, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUpdateRepo) GetFeedByID(ctx context.Context, input *model.FeedRepoInput) ([]model.FeedItem, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).([]model.FeedItem)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUpdateRepo) MarkUpdatesAsRead(ctx context.Context, userID int, updateIDs []int) error {
+	args := _self.Called(userID, updateIDs)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
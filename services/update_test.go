@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateService_CreateUpdate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *model.UpdateServiceInput
+		expectedResult int
+		expectedErr    error
+		mockRepoInput  *model.UpdateRepoInput
+		mockRepoResult int
+		mockRepoError  error
+	}{
+		{
+			name: "Create update failed with error",
+			input: &model.UpdateServiceInput{
+				SenderID:   1,
+				Text:       "hello",
+				Recipients: []string{"xyz@abc.com"},
+			},
+			expectedErr: errors.New("create update failed with error"),
+			mockRepoInput: &model.UpdateRepoInput{
+				SenderID:   1,
+				Text:       "hello",
+				Mentions:   []string{},
+				Recipients: []string{"xyz@abc.com"},
+			},
+			mockRepoResult: 0,
+			mockRepoError:  errors.New("create update failed with error"),
+		},
+		{
+			name: "Create update with mentions success",
+			input: &model.UpdateServiceInput{
+				SenderID:   1,
+				Sender:     "abc@xyz.com",
+				Text:       "hello kate@example.com",
+				Recipients: []string{"xyz@abc.com", "kate@example.com"},
+			},
+			expectedResult: 7,
+			mockRepoInput: &model.UpdateRepoInput{
+				SenderID:   1,
+				Sender:     "abc@xyz.com",
+				Text:       "hello kate@example.com",
+				Mentions:   []string{"kate@example.com"},
+				Recipients: []string{"xyz@abc.com", "kate@example.com"},
+			},
+			mockRepoResult: 7,
+			mockRepoError:  nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUpdateRepo := new(mockUpdateRepo)
+			mockUpdateRepo.On("CreateUpdate", testCase.mockRepoInput).
+				Return(testCase.mockRepoResult, testCase.mockRepoError)
+
+			service := UpdateService{
+				IUpdateRepo: mockUpdateRepo,
+			}
+
+			// When
+			result, err := service.CreateUpdate(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUpdateService_GetFeed(t *testing.T) {
+	type mockGetFeedByID struct {
+		input  *model.FeedRepoInput
+		result []model.FeedItem
+		err    error
+	}
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name            string
+		input           *model.FeedServiceInput
+		expectedResult  *model.FeedPage
+		expectedErr     error
+		mockGetFeedByID mockGetFeedByID
+	}{
+		{
+			name:        "Get feed failed with error",
+			input:       &model.FeedServiceInput{UserID: 1, Limit: 2},
+			expectedErr: errors.New("get feed failed with error"),
+			mockGetFeedByID: mockGetFeedByID{
+				input:  &model.FeedRepoInput{UserID: 1, Limit: 3},
+				result: nil,
+				err:    errors.New("get feed failed with error"),
+			},
+		},
+		{
+			name:  "Last page has no next cursor",
+			input: &model.FeedServiceInput{UserID: 1, Limit: 2},
+			expectedResult: &model.FeedPage{
+				Updates: []model.FeedItem{
+					{UpdateID: 2, Sender: "xyz@abc.com", Text: "second", CreatedAt: createdAt.Add(time.Hour)},
+				},
+			},
+			mockGetFeedByID: mockGetFeedByID{
+				input: &model.FeedRepoInput{UserID: 1, Limit: 3},
+				result: []model.FeedItem{
+					{UpdateID: 2, Sender: "xyz@abc.com", Text: "second", CreatedAt: createdAt.Add(time.Hour)},
+				},
+			},
+		},
+		{
+			name: "Page with next cursor",
+			input: &model.FeedServiceInput{
+				UserID: 1,
+				Limit:  1,
+				Cursor: &model.FeedCursor{CreatedAt: createdAt.Add(2 * time.Hour), ID: 3},
+			},
+			expectedResult: &model.FeedPage{
+				Updates: []model.FeedItem{
+					{UpdateID: 2, Sender: "xyz@abc.com", Text: "second", CreatedAt: createdAt.Add(time.Hour), Read: true},
+				},
+				NextCursor: model.EncodeFeedCursor(model.FeedCursor{CreatedAt: createdAt.Add(time.Hour), ID: 2}),
+			},
+			mockGetFeedByID: mockGetFeedByID{
+				input: &model.FeedRepoInput{
+					UserID: 1,
+					Limit:  2,
+					Cursor: &model.FeedCursor{CreatedAt: createdAt.Add(2 * time.Hour), ID: 3},
+				},
+				result: []model.FeedItem{
+					{UpdateID: 2, Sender: "xyz@abc.com", Text: "second", CreatedAt: createdAt.Add(time.Hour), Read: true},
+					{UpdateID: 1, Sender: "xyz@abc.com", Text: "first", CreatedAt: createdAt},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUpdateRepo := new(mockUpdateRepo)
+			mockUpdateRepo.On("GetFeedByID", testCase.mockGetFeedByID.input).
+				Return(testCase.mockGetFeedByID.result, testCase.mockGetFeedByID.err)
+
+			service := UpdateService{
+				IUpdateRepo: mockUpdateRepo,
+			}
+
+			// When
+			result, err := service.GetFeed(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUpdateService_MarkUpdatesAsRead(t *testing.T) {
+	testCases := []struct {
+		name          string
+		userID        int
+		updateIDs     []int
+		expectedErr   error
+		mockRepoError error
+	}{
+		{
+			name:          "Mark as read failed with error",
+			userID:        1,
+			updateIDs:     []int{1, 2},
+			expectedErr:   errors.New("mark as read failed with error"),
+			mockRepoError: errors.New("mark as read failed with error"),
+		},
+		{
+			name:          "Mark as read success",
+			userID:        1,
+			updateIDs:     []int{1, 2},
+			expectedErr:   nil,
+			mockRepoError: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockUpdateRepo := new(mockUpdateRepo)
+			mockUpdateRepo.On("MarkUpdatesAsRead", testCase.userID, testCase.updateIDs).
+				Return(testCase.mockRepoError)
+
+			service := UpdateService{
+				IUpdateRepo: mockUpdateRepo,
+			}
+
+			// When
+			err := service.MarkUpdatesAsRead(context.Background(), testCase.userID, testCase.updateIDs)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
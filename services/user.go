@@ -1,43 +1,116 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"S3_FriendManagement_ThinhNguyen/repositories"
 )
 
 type IUserService interface {
-	CreateUser(*model.UserServiceInput) error
-	IsExistedUser(string) (bool, error)
-	GetUserIDByEmail(string) (int, error)
-	CheckInvalidEmails([]string) ([]string, error)
+	CreateUser(context.Context, *model.UserServiceInput) error
+	CreateUsers(ctx context.Context, emails []string) (int, error)
+	IsExistedUser(context.Context, string) (bool, error)
+	GetUserIDByEmail(context.Context, string) (int, error)
+	GetUserIDMapByEmails(ctx context.Context, emails []string) (map[string]int, error)
+	CheckInvalidEmails(context.Context, []string) ([]string, error)
+	GetUserProfile(ctx context.Context, email string) (*model.UserProfile, error)
+	GetUserProfilesByEmails(ctx context.Context, emails []string) ([]model.UserProfile, error)
+	UpdateUserProfile(ctx context.Context, input *model.UserProfileServiceInput) (*model.UserProfile, error)
+	SearchUsers(ctx context.Context, search string, limit int) ([]model.UserProfile, error)
+	ChangeEmail(ctx context.Context, input *model.ChangeEmailServiceInput) error
+	DeleteUser(ctx context.Context, email string) (bool, error)
+	ExportUser(ctx context.Context, email string) (*model.UserExport, error)
 }
 
 type UserService struct {
 	IUserRepo repositories.IUserRepo
 }
 
-func (_self UserService) CreateUser(userServiceInput *model.UserServiceInput) error {
+func (_self UserService) CreateUser(ctx context.Context, userServiceInput *model.UserServiceInput) error {
 	//Convert to repo input
 	userRepoInput := &model.UserRepoInput{
 		Email: userServiceInput.Email,
 	}
 
-	err := _self.IUserRepo.CreateUser(userRepoInput)
+	err := _self.IUserRepo.CreateUser(ctx, userRepoInput)
 	return err
 }
 
-func (_self UserService) GetUserIDByEmail(email string) (int, error) {
-	result, err := _self.IUserRepo.GetUserIDByEmail(email)
+// CreateUsers creates the users of many emails at once and returns how many were created, the existing ones are skipped
+func (_self UserService) CreateUsers(ctx context.Context, emails []string) (int, error) {
+	created, err := _self.IUserRepo.CreateUsers(ctx, emails)
+	return created, err
+}
+
+func (_self UserService) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
+	result, err := _self.IUserRepo.GetUserIDByEmail(ctx, email)
 	return result, err
 }
 
-func (_self UserService) IsExistedUser(email string) (bool, error) {
+// GetUserIDMapByEmails resolves many emails with one query, the emails which do not exist are left out
+func (_self UserService) GetUserIDMapByEmails(ctx context.Context, emails []string) (map[string]int, error) {
+	IDMap, err := _self.IUserRepo.GetUserIDMapByEmails(ctx, emails)
+	return IDMap, err
+}
+
+func (_self UserService) IsExistedUser(ctx context.Context, email string) (bool, error) {
 	//call repo
-	existed, err := _self.IUserRepo.IsExistedUser(email)
+	existed, err := _self.IUserRepo.IsExistedUser(ctx, email)
 	return existed, err
 }
 
-func (_self UserService) CheckInvalidEmails(emails []string) ([]string, error) {
-	results, err := _self.IUserRepo.CheckInvalidEmails(emails)
+func (_self UserService) CheckInvalidEmails(ctx context.Context, emails []string) ([]string, error) {
+	results, err := _self.IUserRepo.CheckInvalidEmails(ctx, emails)
 	return results, err
 }
+
+func (_self UserService) GetUserProfile(ctx context.Context, email string) (*model.UserProfile, error) {
+	profile, err := _self.IUserRepo.GetUserProfile(ctx, email)
+	return profile, err
+}
+
+func (_self UserService) GetUserProfilesByEmails(ctx context.Context, emails []string) ([]model.UserProfile, error) {
+	profiles, err := _self.IUserRepo.GetUserProfilesByEmails(ctx, emails)
+	return profiles, err
+}
+
+func (_self UserService) UpdateUserProfile(ctx context.Context, input *model.UserProfileServiceInput) (*model.UserProfile, error) {
+	//Convert to repo input
+	repoInput := &model.UserProfileRepoInput{
+		Email:       input.Email,
+		DisplayName: input.DisplayName,
+		AvatarURL:   input.AvatarURL,
+		Bio:         input.Bio,
+		Status:      input.Status,
+	}
+
+	profile, err := _self.IUserRepo.UpdateUserProfile(ctx, repoInput)
+	return profile, err
+}
+
+func (_self UserService) SearchUsers(ctx context.Context, search string, limit int) ([]model.UserProfile, error) {
+	profiles, err := _self.IUserRepo.SearchUsers(ctx, search, limit)
+	return profiles, err
+}
+
+func (_self UserService) ChangeEmail(ctx context.Context, input *model.ChangeEmailServiceInput) error {
+	//Convert to repo input
+	repoInput := &model.ChangeEmailRepoInput{
+		OldEmail: input.OldEmail,
+		NewEmail: input.NewEmail,
+	}
+
+	err := _self.IUserRepo.ChangeEmail(ctx, repoInput)
+	return err
+}
+
+func (_self UserService) DeleteUser(ctx context.Context, email string) (bool, error) {
+	deleted, err := _self.IUserRepo.DeleteUser(ctx, email)
+	return deleted, err
+}
+
+func (_self UserService) ExportUser(ctx context.Context, email string) (*model.UserExport, error) {
+	export, err := _self.IUserRepo.ExportUser(ctx, email)
+	return export, err
+}
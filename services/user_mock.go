@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"S3_FriendManagement_ThinhNguyen/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,7 +11,7 @@ type mockUserRepo struct {
 	mock.Mock
 }
 
-func (_self mockUserRepo) CreateUser(userRepoInput *model.UserRepoInput) error {
+func (_self mockUserRepo) CreateUser(ctx context.Context, userRepoInput *model.UserRepoInput) error {
 	args := _self.Called(userRepoInput)
 	var r error
 	if args.Get(0) != nil {
@@ -18,7 +20,17 @@ func (_self mockUserRepo) CreateUser(userRepoInput *model.UserRepoInput) error {
 	return r
 }
 
-func (_self mockUserRepo) GetUserIDByEmail(email string) (int, error) {
+func (_self mockUserRepo) CreateUsers(ctx context.Context, emails []string) (int, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).(int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) GetUserIDByEmail(ctx context.Context, email string) (int, error) {
 	args := _self.Called(email)
 	r0 := args.Get(0).(int)
 	var r1 error
@@ -28,7 +40,7 @@ func (_self mockUserRepo) GetUserIDByEmail(email string) (int, error) {
 	return r0, r1
 }
 
-func (_self mockUserRepo) IsExistedUser(email string) (bool, error) {
+func (_self mockUserRepo) IsExistedUser(ctx context.Context, email string) (bool, error) {
 	args := _self.Called(email)
 	r0 := args.Get(0).(bool)
 	var r1 error
@@ -38,7 +50,7 @@ func (_self mockUserRepo) IsExistedUser(email string) (bool, error) {
 	return r0, r1
 }
 
-func (_self mockUserRepo) GetEmailListByIDs(userIDs []int) ([]string, error) {
+func (_self mockUserRepo) GetEmailListByIDs(ctx context.Context, userIDs []int) ([]string, error) {
 	args := _self.Called(userIDs)
 	r0 := args.Get(0).([]string)
 	var r1 error
@@ -48,7 +60,17 @@ func (_self mockUserRepo) GetEmailListByIDs(userIDs []int) ([]string, error) {
 	return r0, r1
 }
 
-func (_self mockUserRepo) GetUserIDsByEmails(emails []string) ([]int, error) {
+func (_self mockUserRepo) GetEmailMapByIDs(ctx context.Context, userIDs []int) (map[int]string, error) {
+	args := _self.Called(userIDs)
+	r0 := args.Get(0).(map[int]string)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) GetUserIDsByEmails(ctx context.Context, emails []string) ([]int, error) {
 	args := _self.Called(emails)
 	r0 := args.Get(0).([]int)
 	var r1 error
@@ -58,7 +80,17 @@ func (_self mockUserRepo) GetUserIDsByEmails(emails []string) ([]int, error) {
 	return r0, r1
 }
 
-func (_self mockUserRepo) CheckInvalidEmails(emails []string) ([]string, error) {
+func (_self mockUserRepo) GetUserIDMapByEmails(ctx context.Context, emails []string) (map[string]int, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).(map[string]int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) CheckInvalidEmails(ctx context.Context, emails []string) ([]string, error) {
 	args := _self.Called(emails)
 	r0 := args.Get(0).([]string)
 	var r1 error
@@ -67,3 +99,72 @@ func (_self mockUserRepo) CheckInvalidEmails(emails []string) ([]string, error)
 	}
 	return r0, r1
 }
+
+func (_self mockUserRepo) GetUserProfile(ctx context.Context, email string) (*model.UserProfile, error) {
+	args := _self.Called(email)
+	r0 := args.Get(0).(*model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) GetUserProfilesByEmails(ctx context.Context, emails []string) ([]model.UserProfile, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).([]model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) UpdateUserProfile(ctx context.Context, input *model.UserProfileRepoInput) (*model.UserProfile, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(*model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) SearchUsers(ctx context.Context, search string, limit int) ([]model.UserProfile, error) {
+	args := _self.Called(search, limit)
+	r0 := args.Get(0).([]model.UserProfile)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) DeleteUser(ctx context.Context, email string) (bool, error) {
+	args := _self.Called(email)
+	r0 := args.Get(0).(bool)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) ExportUser(ctx context.Context, email string) (*model.UserExport, error) {
+	args := _self.Called(email)
+	r0 := args.Get(0).(*model.UserExport)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockUserRepo) ChangeEmail(ctx context.Context, input *model.ChangeEmailRepoInput) error {
+	args := _self.Called(input)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -51,7 +52,7 @@ func TestUserService_CreateUser(t *testing.T) {
 			}
 
 			//When
-			err := service.CreateUser(testCase.input)
+			err := service.CreateUser(context.Background(), testCase.input)
 
 			//Then
 			if testCase.expectedErr != nil {
@@ -63,6 +64,53 @@ func TestUserService_CreateUser(t *testing.T) {
 	}
 }
 
+func TestUserService_CreateUsers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedResult int
+		expectedErr    error
+		mockRepoResult int
+		mockRepoErr    error
+	}{
+		{
+			name:        "Create failed with error",
+			input:       []string{"abc@xyz.com"},
+			expectedErr: errors.New("create failed with error"),
+			mockRepoErr: errors.New("create failed with error"),
+		},
+		{
+			name:           "Create success",
+			input:          []string{"abc@xyz.com", "xyz@abc.com"},
+			expectedResult: 1,
+			mockRepoResult: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("CreateUsers", testCase.input).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			created, err := service.CreateUsers(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, created)
+			}
+		})
+	}
+}
+
 func TestUserService_IsExistedUser(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -104,7 +152,7 @@ func TestUserService_IsExistedUser(t *testing.T) {
 			}
 
 			//When
-			existed, err := service.IsExistedUser(testCase.input)
+			existed, err := service.IsExistedUser(context.Background(), testCase.input)
 
 			//Then
 			if testCase.expectedErr != nil {
@@ -158,7 +206,7 @@ func TestUserService_GetUserIDByEmail(t *testing.T) {
 			}
 
 			//When
-			existed, err := service.GetUserIDByEmail(testCase.input)
+			existed, err := service.GetUserIDByEmail(context.Background(), testCase.input)
 
 			//Then
 			if testCase.expectedErr != nil {
@@ -171,6 +219,56 @@ func TestUserService_GetUserIDByEmail(t *testing.T) {
 	}
 }
 
+func TestUserService_GetUserIDMapByEmails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedErr    error
+		expectedResult map[string]int
+		mockRepoResult map[string]int
+		mockRepoErr    error
+	}{
+		{
+			name:           "Get failed with error",
+			input:          []string{"abc@email.com"},
+			expectedErr:    errors.New("get failed with error"),
+			mockRepoResult: nil,
+			mockRepoErr:    errors.New("get failed with error"),
+		},
+		{
+			name:           "Get success",
+			input:          []string{"abc@email.com", "xyz@email.com"},
+			expectedErr:    nil,
+			expectedResult: map[string]int{"xyz@email.com": 2},
+			mockRepoResult: map[string]int{"xyz@email.com": 2},
+			mockRepoErr:    nil,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("GetUserIDMapByEmails", testCase.input).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			IDMap, err := service.GetUserIDMapByEmails(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, IDMap)
+			}
+		})
+	}
+}
+
 func TestUserService_CheckInvalidEmails(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -212,7 +310,7 @@ func TestUserService_CheckInvalidEmails(t *testing.T) {
 			}
 
 			//When
-			existed, err := service.CheckInvalidEmails(testCase.input)
+			existed, err := service.CheckInvalidEmails(context.Background(), testCase.input)
 
 			//Then
 			if testCase.expectedErr != nil {
@@ -224,3 +322,337 @@ func TestUserService_CheckInvalidEmails(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_GetUserProfile(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedErr    error
+		expectedResult *model.UserProfile
+		mockRepoResult *model.UserProfile
+		mockRepoErr    error
+	}{
+		{
+			name:        "Get profile failed with error",
+			input:       "abc@email.com",
+			expectedErr: errors.New("get profile failed with error"),
+			mockRepoErr: errors.New("get profile failed with error"),
+		},
+		{
+			name:           "Get profile success",
+			input:          "abc@email.com",
+			expectedResult: &model.UserProfile{Email: "abc@email.com", DisplayName: "Abc"},
+			mockRepoResult: &model.UserProfile{Email: "abc@email.com", DisplayName: "Abc"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("GetUserProfile", testCase.input).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			result, err := service.GetUserProfile(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserService_GetUserProfilesByEmails(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          []string
+		expectedErr    error
+		expectedResult []model.UserProfile
+		mockRepoResult []model.UserProfile
+		mockRepoErr    error
+	}{
+		{
+			name:        "Get profiles failed with error",
+			input:       []string{"abc@email.com"},
+			expectedErr: errors.New("get profiles failed with error"),
+			mockRepoErr: errors.New("get profiles failed with error"),
+		},
+		{
+			name:           "Get profiles success",
+			input:          []string{"abc@email.com", "xyz@email.com"},
+			expectedResult: []model.UserProfile{{Email: "abc@email.com"}, {Email: "xyz@email.com"}},
+			mockRepoResult: []model.UserProfile{{Email: "abc@email.com"}, {Email: "xyz@email.com"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("GetUserProfilesByEmails", testCase.input).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			result, err := service.GetUserProfilesByEmails(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserService_UpdateUserProfile(t *testing.T) {
+	bio := "Hello"
+	testCases := []struct {
+		name           string
+		input          *model.UserProfileServiceInput
+		expectedErr    error
+		expectedResult *model.UserProfile
+		mockRepoInput  *model.UserProfileRepoInput
+		mockRepoResult *model.UserProfile
+		mockRepoErr    error
+	}{
+		{
+			name:          "Update profile failed with error",
+			input:         &model.UserProfileServiceInput{Email: "abc@email.com", Bio: &bio},
+			expectedErr:   errors.New("update profile failed with error"),
+			mockRepoInput: &model.UserProfileRepoInput{Email: "abc@email.com", Bio: &bio},
+			mockRepoErr:   errors.New("update profile failed with error"),
+		},
+		{
+			name:           "Update profile success",
+			input:          &model.UserProfileServiceInput{Email: "abc@email.com", Bio: &bio},
+			expectedResult: &model.UserProfile{Email: "abc@email.com", Bio: "Hello"},
+			mockRepoInput:  &model.UserProfileRepoInput{Email: "abc@email.com", Bio: &bio},
+			mockRepoResult: &model.UserProfile{Email: "abc@email.com", Bio: "Hello"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("UpdateUserProfile", testCase.mockRepoInput).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			result, err := service.UpdateUserProfile(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserService_SearchUsers(t *testing.T) {
+	testCases := []struct {
+		name           string
+		search         string
+		limit          int
+		expectedErr    error
+		expectedResult []model.UserProfile
+		mockRepoResult []model.UserProfile
+		mockRepoErr    error
+	}{
+		{
+			name:        "Search failed with error",
+			search:      "ab",
+			limit:       20,
+			expectedErr: errors.New("search failed with error"),
+			mockRepoErr: errors.New("search failed with error"),
+		},
+		{
+			name:           "Search success",
+			search:         "ab",
+			limit:          20,
+			expectedResult: []model.UserProfile{{Email: "abc@email.com"}},
+			mockRepoResult: []model.UserProfile{{Email: "abc@email.com"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("SearchUsers", testCase.search, testCase.limit).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			result, err := service.SearchUsers(context.Background(), testCase.search, testCase.limit)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedErr    error
+		expectedResult bool
+		mockRepoResult bool
+		mockRepoErr    error
+	}{
+		{
+			name:        "Delete user failed with error",
+			input:       "abc@email.com",
+			expectedErr: errors.New("delete user failed with error"),
+			mockRepoErr: errors.New("delete user failed with error"),
+		},
+		{
+			name:           "Delete user success",
+			input:          "abc@email.com",
+			expectedResult: true,
+			mockRepoResult: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("DeleteUser", testCase.input).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			result, err := service.DeleteUser(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserService_ExportUser(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedErr    error
+		expectedResult *model.UserExport
+		mockRepoResult *model.UserExport
+		mockRepoErr    error
+	}{
+		{
+			name:        "Export user failed with error",
+			input:       "abc@email.com",
+			expectedErr: errors.New("export user failed with error"),
+			mockRepoErr: errors.New("export user failed with error"),
+		},
+		{
+			name:           "Export user success",
+			input:          "abc@email.com",
+			expectedResult: &model.UserExport{Profile: model.UserProfile{Email: "abc@email.com"}},
+			mockRepoResult: &model.UserExport{Profile: model.UserProfile{Email: "abc@email.com"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("ExportUser", testCase.input).
+				Return(testCase.mockRepoResult, testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			result, err := service.ExportUser(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestUserService_ChangeEmail(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *model.ChangeEmailServiceInput
+		expectedErr   error
+		mockRepoInput *model.ChangeEmailRepoInput
+		mockRepoErr   error
+	}{
+		{
+			name:          "Change email failed with error",
+			input:         &model.ChangeEmailServiceInput{OldEmail: "abc@email.com", NewEmail: "new@email.com"},
+			expectedErr:   errors.New("change email failed with error"),
+			mockRepoInput: &model.ChangeEmailRepoInput{OldEmail: "abc@email.com", NewEmail: "new@email.com"},
+			mockRepoErr:   errors.New("change email failed with error"),
+		},
+		{
+			name:          "Change email success",
+			input:         &model.ChangeEmailServiceInput{OldEmail: "abc@email.com", NewEmail: "new@email.com"},
+			mockRepoInput: &model.ChangeEmailRepoInput{OldEmail: "abc@email.com", NewEmail: "new@email.com"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//Given
+			mockUserRepo := new(mockUserRepo)
+			mockUserRepo.On("ChangeEmail", testCase.mockRepoInput).
+				Return(testCase.mockRepoErr)
+
+			service := UserService{
+				IUserRepo: mockUserRepo,
+			}
+
+			//When
+			err := service.ChangeEmail(context.Background(), testCase.input)
+
+			//Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
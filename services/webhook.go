@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
+)
+
+type IWebhookService interface {
+	CreateWebhook(context.Context, *model.WebhookServiceInput) (int, error)
+	GetDeadLetters(ctx context.Context) ([]model.WebhookDeadLetter, error)
+}
+
+type WebhookService struct {
+	IWebhookRepo repositories.IWebhookRepo
+}
+
+func (_self WebhookService) CreateWebhook(ctx context.Context, webhook *model.WebhookServiceInput) (int, error) {
+	//Create repo input model
+	webhookRepoInput := &model.WebhookRepoInput{
+		UserID: webhook.UserID,
+		URL:    webhook.URL,
+		Secret: webhook.Secret,
+	}
+	id, err := _self.IWebhookRepo.CreateWebhook(ctx, webhookRepoInput)
+	return id, err
+}
+
+func (_self WebhookService) GetDeadLetters(ctx context.Context) ([]model.WebhookDeadLetter, error) {
+	deadLetters, err := _self.IWebhookRepo.GetDeadLetters(ctx)
+	return deadLetters, err
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockWebhookRepo struct {
+	mock.Mock
+}
+
+func (_self mockWebhookRepo) CreateWebhook(ctx context.Context, input *model.WebhookRepoInput) (int, error) {
+	args := _self.Called(input)
+	r0 := args.Get(0).(int)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockWebhookRepo) GetWebhooksForRecipients(ctx context.Context, emails []string) ([]model.Webhook, error) {
+	args := _self.Called(emails)
+	r0 := args.Get(0).([]model.Webhook)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockWebhookRepo) ClaimDueOutboxEntries(ctx context.Context, limit int, claimTimeout time.Duration) ([]model.WebhookOutboxEntry, error) {
+	args := _self.Called(limit, claimTimeout)
+	r0 := args.Get(0).([]model.WebhookOutboxEntry)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
+
+func (_self mockWebhookRepo) MarkOutboxDelivered(ctx context.Context, id int) error {
+	args := _self.Called(id)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockWebhookRepo) MarkOutboxFailed(ctx context.Context, failure *model.WebhookOutboxFailure) error {
+	args := _self.Called(failure)
+	var r error
+	if args.Get(0) != nil {
+		r = args.Get(0).(error)
+	}
+	return r
+}
+
+func (_self mockWebhookRepo) GetDeadLetters(ctx context.Context) ([]model.WebhookDeadLetter, error) {
+	args := _self.Called()
+	r0 := args.Get(0).([]model.WebhookDeadLetter)
+	var r1 error
+	if args.Get(1) != nil {
+		r1 = args.Get(1).(error)
+	}
+	return r0, r1
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *model.WebhookServiceInput
+		expectedResult int
+		expectedErr    error
+		mockRepoInput  *model.WebhookRepoInput
+		mockRepoResult int
+		mockRepoError  error
+	}{
+		{
+			name:           "Create webhook failed with error",
+			input:          &model.WebhookServiceInput{URL: "https://example.com/hook", Secret: "s3cr3t"},
+			expectedErr:    errors.New("create webhook failed with error"),
+			mockRepoInput:  &model.WebhookRepoInput{URL: "https://example.com/hook", Secret: "s3cr3t"},
+			mockRepoResult: 0,
+			mockRepoError:  errors.New("create webhook failed with error"),
+		},
+		{
+			name:           "Create webhook success",
+			input:          &model.WebhookServiceInput{UserID: 1, URL: "https://example.com/hook", Secret: "s3cr3t"},
+			expectedResult: 2,
+			mockRepoInput:  &model.WebhookRepoInput{UserID: 1, URL: "https://example.com/hook", Secret: "s3cr3t"},
+			mockRepoResult: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			mockWebhookRepo := new(mockWebhookRepo)
+			mockWebhookRepo.On("CreateWebhook", testCase.mockRepoInput).
+				Return(testCase.mockRepoResult, testCase.mockRepoError)
+
+			service := WebhookService{
+				IWebhookRepo: mockWebhookRepo,
+			}
+
+			// When
+			result, err := service.CreateWebhook(context.Background(), testCase.input)
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/repositories"
+	"S3_FriendManagement_ThinhNguyen/utils"
+)
+
+const (
+	DefaultWebhookBatchSize   = 50
+	DefaultWebhookMaxAttempts = 8
+	DefaultWebhookBaseBackoff = 30 * time.Second
+	DefaultWebhookMaxBackoff  = 6 * time.Hour
+	//DefaultWebhookClaimTimeout is how long a claimed entry waits for its result before another dispatcher takes it
+	DefaultWebhookClaimTimeout = 15 * time.Minute
+)
+
+const (
+	WebhookSignatureHeader = "X-Signature"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// WebhookDispatcher POSTs the due outbox entries to their webhooks.
+// Zero values fall back to the Default* constants and http.DefaultClient.
+type WebhookDispatcher struct {
+	IWebhookRepo repositories.IWebhookRepo
+	Client       *http.Client
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	ClaimTimeout time.Duration
+	Now          func() time.Time
+}
+
+// Run dispatches the outbox every interval until ctx is done
+func (_self WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := _self.DispatchPending(ctx); err != nil {
+			log.Println("webhook dispatch failed:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending sends one batch of due outbox entries and returns how many were delivered
+func (_self WebhookDispatcher) DispatchPending(ctx context.Context) (int, error) {
+	entries, err := _self.IWebhookRepo.ClaimDueOutboxEntries(ctx, _self.batchSize(), _self.claimTimeout())
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, entry := range entries {
+		if sendErr := _self.send(ctx, entry); sendErr != nil {
+			attempts := entry.Attempts + 1
+			failure := &model.WebhookOutboxFailure{
+				ID:            entry.ID,
+				Attempts:      attempts,
+				NextAttemptAt: _self.now().Add(_self.backoff(attempts)),
+				LastError:     sendErr.Error(),
+				Dead:          attempts >= _self.maxAttempts(),
+			}
+			if err := _self.IWebhookRepo.MarkOutboxFailed(ctx, failure); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := _self.IWebhookRepo.MarkOutboxDelivered(ctx, entry.ID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (_self WebhookDispatcher) send(ctx context.Context, entry model.WebhookOutboxEntry) error {
+	payload := []byte(entry.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, entry.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, "sha256="+utils.SignPayload(entry.Secret, payload))
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(entry.ID))
+
+	client := _self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// backoff doubles the wait after every failed attempt, up to MaxBackoff
+func (_self WebhookDispatcher) backoff(attempts int) time.Duration {
+	base := _self.BaseBackoff
+	if base == 0 {
+		base = DefaultWebhookBaseBackoff
+	}
+	maxBackoff := _self.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = DefaultWebhookMaxBackoff
+	}
+	delay := base
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+func (_self WebhookDispatcher) batchSize() int {
+	if _self.BatchSize == 0 {
+		return DefaultWebhookBatchSize
+	}
+	return _self.BatchSize
+}
+
+func (_self WebhookDispatcher) claimTimeout() time.Duration {
+	if _self.ClaimTimeout == 0 {
+		return DefaultWebhookClaimTimeout
+	}
+	return _self.ClaimTimeout
+}
+
+func (_self WebhookDispatcher) maxAttempts() int {
+	if _self.MaxAttempts == 0 {
+		return DefaultWebhookMaxAttempts
+	}
+	return _self.MaxAttempts
+}
+
+func (_self WebhookDispatcher) now() time.Time {
+	if _self.Now == nil {
+		return time.Now()
+	}
+	return _self.Now()
+}
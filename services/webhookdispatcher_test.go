@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"S3_FriendManagement_ThinhNguyen/model"
+	"S3_FriendManagement_ThinhNguyen/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDispatcher_DispatchPending(t *testing.T) {
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	type receivedRequest struct {
+		body      string
+		signature string
+		delivery  string
+	}
+	testCases := []struct {
+		name              string
+		responseStatus    int
+		entries           []model.WebhookOutboxEntry
+		mockGetDueErr     error
+		mockFailure       *model.WebhookOutboxFailure
+		expectedDelivered int
+		expectedErr       error
+		expectedRequests  []receivedRequest
+	}{
+		{
+			name:          "Get due entries failed with error",
+			mockGetDueErr: errors.New("get due entries failed with error"),
+			expectedErr:   errors.New("get due entries failed with error"),
+		},
+		{
+			name:           "Deliver signed payload",
+			responseStatus: http.StatusOK,
+			entries: []model.WebhookOutboxEntry{
+				{ID: 5, WebhookID: 1, Secret: "s3cr3t", Payload: `{"update_id":1}`},
+			},
+			expectedDelivered: 1,
+			expectedRequests: []receivedRequest{
+				{
+					body:      `{"update_id":1}`,
+					signature: "sha256=" + utils.SignPayload("s3cr3t", []byte(`{"update_id":1}`)),
+					delivery:  "5",
+				},
+			},
+		},
+		{
+			name:           "Failed delivery is retried with backoff",
+			responseStatus: http.StatusInternalServerError,
+			entries: []model.WebhookOutboxEntry{
+				{ID: 5, WebhookID: 1, Secret: "s3cr3t", Payload: `{"update_id":1}`, Attempts: 2},
+			},
+			mockFailure: &model.WebhookOutboxFailure{
+				ID:            5,
+				Attempts:      3,
+				NextAttemptAt: now.Add(4 * time.Second),
+				LastError:     "webhook responded with status 500",
+			},
+			expectedRequests: []receivedRequest{
+				{
+					body:      `{"update_id":1}`,
+					signature: "sha256=" + utils.SignPayload("s3cr3t", []byte(`{"update_id":1}`)),
+					delivery:  "5",
+				},
+			},
+		},
+		{
+			name:           "Last failed attempt goes to dead letters",
+			responseStatus: http.StatusBadGateway,
+			entries: []model.WebhookOutboxEntry{
+				{ID: 6, WebhookID: 1, Secret: "s3cr3t", Payload: `{"update_id":2}`, Attempts: 4},
+			},
+			mockFailure: &model.WebhookOutboxFailure{
+				ID:            6,
+				Attempts:      5,
+				NextAttemptAt: now.Add(10 * time.Second),
+				LastError:     "webhook responded with status 502",
+				Dead:          true,
+			},
+			expectedRequests: []receivedRequest{
+				{
+					body:      `{"update_id":2}`,
+					signature: "sha256=" + utils.SignPayload("s3cr3t", []byte(`{"update_id":2}`)),
+					delivery:  "6",
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			received := make([]receivedRequest, 0)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				received = append(received, receivedRequest{
+					body:      string(body),
+					signature: r.Header.Get(WebhookSignatureHeader),
+					delivery:  r.Header.Get(WebhookDeliveryHeader),
+				})
+				w.WriteHeader(testCase.responseStatus)
+			}))
+			defer server.Close()
+
+			entries := make([]model.WebhookOutboxEntry, len(testCase.entries))
+			for i, entry := range testCase.entries {
+				entry.URL = server.URL
+				entries[i] = entry
+			}
+
+			mockWebhookRepo := new(mockWebhookRepo)
+			mockWebhookRepo.On("ClaimDueOutboxEntries", 10, DefaultWebhookClaimTimeout).Return(entries, testCase.mockGetDueErr)
+			for _, entry := range entries {
+				mockWebhookRepo.On("MarkOutboxDelivered", entry.ID).Return(nil)
+			}
+			mockWebhookRepo.On("MarkOutboxFailed", testCase.mockFailure).Return(nil)
+
+			dispatcher := WebhookDispatcher{
+				IWebhookRepo: mockWebhookRepo,
+				Client:       server.Client(),
+				BatchSize:    10,
+				MaxAttempts:  5,
+				BaseBackoff:  time.Second,
+				MaxBackoff:   10 * time.Second,
+				Now: func() time.Time {
+					return now
+				},
+			}
+
+			// When
+			delivered, err := dispatcher.DispatchPending(context.Background())
+
+			// Then
+			if testCase.expectedErr != nil {
+				require.EqualError(t, err, testCase.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedDelivered, delivered)
+				require.Equal(t, testCase.expectedRequests, received)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatcher_Backoff(t *testing.T) {
+	dispatcher := WebhookDispatcher{
+		BaseBackoff: time.Second,
+		MaxBackoff:  10 * time.Second,
+	}
+
+	require.Equal(t, time.Second, dispatcher.backoff(1))
+	require.Equal(t, 2*time.Second, dispatcher.backoff(2))
+	require.Equal(t, 8*time.Second, dispatcher.backoff(4))
+	require.Equal(t, 10*time.Second, dispatcher.backoff(5))
+	require.Equal(t, 10*time.Second, dispatcher.backoff(30))
+}
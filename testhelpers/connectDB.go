@@ -2,30 +2,60 @@ package testhelpers
 
 import (
 	"database/sql"
+	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 
+	"S3_FriendManagement_ThinhNguyen/config"
 	_ "github.com/lib/pq"
 )
 
+// ConnectDB opens the test database with the same settings as the server, ex: POSTGRES_PASSWORD=... go test ./...
+// The tests run in the directory of their package, so the file is the .env of the module root unless CONFIG_FILE names one.
 func ConnectDB() *sql.DB {
-	var (
-		host     = "localhost"
-		port     = 5432
-		user     = "postgres"
-		password = "456456123a"
-		dbname   = "FriendManagement"
-	)
-	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+"password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+	var args []string
+	if file, _ := os.LookupEnv("CONFIG_FILE"); file == "" {
+		if file = moduleConfigFile(); file != "" {
+			args = []string{"-config", file}
+		}
+	}
+	settings, err := config.Load(flag.NewFlagSet("testhelpers", flag.ContinueOnError), args, os.LookupEnv)
+	if err != nil {
+		panic(err)
+	}
 
 	//open db connection
-	db, err := sql.Open("postgres", psqlInfo)
+	db, err := sql.Open("postgres", settings.Database.DSN())
 	if err != nil {
 		panic(err)
 	}
 	return db
 }
 
+// moduleConfigFile returns the config.DefaultFile next to go.mod, found from the working directory up,
+// or "" when there is none
+func moduleConfigFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			file := filepath.Join(dir, config.DefaultFile)
+			if _, err := os.Stat(file); err != nil {
+				return ""
+			}
+			return file
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
 func ConnectDBFailed() *sql.DB {
 	var (
 		host     = "localhost"
@@ -3,7 +3,8 @@ package testhelpers
 import (
 	"database/sql"
 	"io/ioutil"
-	"strings"
+
+	"S3_FriendManagement_ThinhNguyen/utils"
 )
 
 // Prepare for test read .sql file and execute it
@@ -15,7 +16,7 @@ func PrepareDBForTest(db *sql.DB, path string) error {
 	}
 
 	// Split statements in .sql file
-	requests := strings.Split(string(file), ";")
+	requests := utils.SplitSQL(string(file))
 
 	// Execute sql statements
 	for _, request := range requests {
@@ -0,0 +1,135 @@
+package utils
+
+import "strings"
+
+// SplitSQL splits a script into its statements on the semicolons which are outside of string literals,
+// quoted identifiers, dollar-quoted bodies and comments. Statements holding only comments are left out.
+func SplitSQL(script string) []string {
+	statements := []string{}
+	start := 0
+	for i := 0; i < len(script); {
+		switch {
+		case script[i] == '\'':
+			i = endOfQuoted(script, i+1, '\'', isEscapeString(script, i))
+		case script[i] == '"':
+			i = endOfQuoted(script, i+1, '"', false)
+		case strings.HasPrefix(script[i:], "--"):
+			i = endOfLineComment(script, i)
+		case strings.HasPrefix(script[i:], "/*"):
+			i = endOfBlockComment(script, i)
+		case script[i] == '$':
+			tag := dollarTag(script, i)
+			if tag == "" {
+				i++
+				break
+			}
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				i = len(script)
+				break
+			}
+			i += len(tag) + end + len(tag)
+		case script[i] == ';':
+			statements = appendStatement(statements, script[start:i])
+			i++
+			start = i
+		default:
+			i++
+		}
+	}
+	return appendStatement(statements, script[start:])
+}
+
+func appendStatement(statements []string, statement string) []string {
+	statement = strings.TrimSpace(statement)
+	for i := 0; i < len(statement); {
+		switch {
+		case strings.HasPrefix(statement[i:], "--"):
+			i = endOfLineComment(statement, i)
+		case strings.HasPrefix(statement[i:], "/*"):
+			i = endOfBlockComment(statement, i)
+		case strings.ContainsRune(" \t\r\n", rune(statement[i])):
+			i++
+		default:
+			return append(statements, statement)
+		}
+	}
+	return statements
+}
+
+// endOfQuoted returns the index after the closing quote, a doubled quote is an escaped one.
+// backslash tells a backslash escapes the next character, as in E'...' strings.
+func endOfQuoted(script string, i int, quote byte, backslash bool) int {
+	for i < len(script) {
+		switch {
+		case backslash && script[i] == '\\':
+			i += 2
+		case script[i] == quote && i+1 < len(script) && script[i+1] == quote:
+			i += 2
+		case script[i] == quote:
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(script)
+}
+
+func isEscapeString(script string, i int) bool {
+	if i == 0 || (script[i-1] != 'E' && script[i-1] != 'e') {
+		return false
+	}
+	return i == 1 || !isIdentifierChar(script[i-2])
+}
+
+func endOfLineComment(script string, i int) int {
+	end := strings.IndexByte(script[i:], '\n')
+	if end < 0 {
+		return len(script)
+	}
+	return i + end + 1
+}
+
+// endOfBlockComment returns the index after the comment starting at i, block comments nest
+func endOfBlockComment(script string, i int) int {
+	depth := 0
+	for i < len(script) {
+		switch {
+		case strings.HasPrefix(script[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(script[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(script)
+}
+
+// dollarTag returns the tag of a dollar quote starting at i, ex: "$$" or "$body$", or "" when there is none.
+// "$1" is a parameter and "a$b" is an identifier.
+func dollarTag(script string, i int) string {
+	if i > 0 && isIdentifierChar(script[i-1]) {
+		return ""
+	}
+	j := i + 1
+	for j < len(script) && script[j] != '$' && isIdentifierChar(script[j]) {
+		if j == i+1 && script[j] >= '0' && script[j] <= '9' {
+			return ""
+		}
+		j++
+	}
+	if j < len(script) && script[j] == '$' {
+		return script[i : j+1]
+	}
+	return ""
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
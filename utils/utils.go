@@ -1,27 +1,111 @@
 package utils
 
-import "regexp"
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
 
 const EmailValidationRegex = "[_A-Za-z0-9-\\+]+(\\.[_A-Za-z0-9-]+)*@[A-Za-z0-9-]+(\\.[A-Za-z0-9]+)*(\\.[A-Za-z]{2,})"
 
+// emailRegex matches a whole string, so nothing can follow or precede the address
+var emailRegex = regexp.MustCompile("^" + EmailValidationRegex + "$")
+
 func IsValidEmail(email string) (bool, error) {
-	re, err := regexp.Compile(EmailValidationRegex)
-	if err != nil {
-		return false, err
-	}
-	if re.MatchString(email) {
+	if emailRegex.MatchString(email) {
 		return true, nil
 	}
 	return false, nil
 }
 
+// FindEmailFromText returns the normalized addresses mentioned in text, each one once
 func FindEmailFromText(text string) []string {
 	regex := regexp.MustCompile(`[_A-Za-z0-9-\+]+(\.[_A-Za-z0-9-]+)*@[A-Za-z0-9-]+(\.[A-Za-z0-9]+)*(\.[A-Za-z]{2,})`)
 
 	emailChain := regex.FindAllString(text, -1)
-	email := make([]string, len(emailChain))
-	for index, emailCharacter := range emailChain {
-		email[index] = emailCharacter
+	email := make([]string, 0, len(emailChain))
+	existedEmails := make(map[string]bool)
+	for _, emailCharacter := range emailChain {
+		normalized := NormalizeEmail(emailCharacter)
+		if existedEmails[normalized] {
+			continue
+		}
+		existedEmails[normalized] = true
+		email = append(email, normalized)
 	}
 	return email
 }
+
+// EmailDomainRule rewrites the local part and the domain of a lowercased address
+type EmailDomainRule func(local string, domain string) (string, string)
+
+// EmailDomainRules holds the optional per-domain rules of NormalizeEmail, keyed by domain.
+// It is empty by default, EnableGmailRules fills in the Gmail ones.
+var EmailDomainRules = map[string]EmailDomainRule{}
+
+// NormalizeEmail trims and lowercases email, then applies the rule of its domain if there is one.
+// Two addresses reaching the same mailbox end up with the same normalized form.
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+	if rule, ok := EmailDomainRules[domain]; ok {
+		local, domain = rule(local, domain)
+	}
+	return local + "@" + domain
+}
+
+// NormalizeEmails applies NormalizeEmail to each of emails
+func NormalizeEmails(emails []string) []string {
+	if emails == nil {
+		return nil
+	}
+	normalized := make([]string, len(emails))
+	for index, email := range emails {
+		normalized[index] = NormalizeEmail(email)
+	}
+	return normalized
+}
+
+// GmailRule drops the dots and the "+tag" of a Gmail local part and folds googlemail.com into gmail.com
+func GmailRule(local string, domain string) (string, string) {
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	return strings.ReplaceAll(local, ".", ""), "gmail.com"
+}
+
+// EnableGmailRules registers GmailRule for gmail.com and googlemail.com
+func EnableGmailRules() {
+	EmailDomainRules["gmail.com"] = GmailRule
+	EmailDomainRules["googlemail.com"] = GmailRule
+}
+
+// EncodeCursor turns the position of the last item of a page into an opaque string for clients
+func EncodeCursor(position interface{}) string {
+	data, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a string built by EncodeCursor back into position
+func DecodeCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, position)
+}
+
+// SignPayload returns the hex encoded HMAC-SHA256 of payload keyed with secret
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		gmailRules     bool
+		expectedResult string
+	}{
+		{
+			name:           "Trim and lowercase",
+			input:          "  Andy@ABC.xyz\t",
+			expectedResult: "andy@abc.xyz",
+		},
+		{
+			name:           "Gmail address without the Gmail rules",
+			input:          "John.Doe+news@GoogleMail.com",
+			expectedResult: "john.doe+news@googlemail.com",
+		},
+		{
+			name:           "Gmail address with the Gmail rules",
+			input:          "John.Doe+news@GoogleMail.com",
+			gmailRules:     true,
+			expectedResult: "johndoe@gmail.com",
+		},
+		{
+			name:           "Other domains keep their dots and tags with the Gmail rules",
+			input:          "John.Doe+news@abc.xyz",
+			gmailRules:     true,
+			expectedResult: "john.doe+news@abc.xyz",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			EmailDomainRules = map[string]EmailDomainRule{}
+			if testCase.gmailRules {
+				EnableGmailRules()
+			}
+			defer func() { EmailDomainRules = map[string]EmailDomainRule{} }()
+
+			// When
+			result := NormalizeEmail(testCase.input)
+
+			// Then
+			require.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}
+
+func TestFindEmailFromText(t *testing.T) {
+	// When
+	result := FindEmailFromText("hello Andy@ABC.xyz, andy@abc.xyz and kate@abc.xyz")
+
+	// Then
+	require.Equal(t, []string{"andy@abc.xyz", "kate@abc.xyz"}, result)
+}
+
+func TestSplitSQL(t *testing.T) {
+	testCases := []struct {
+		name           string
+		script         string
+		expectedResult []string
+	}{
+		{
+			name:           "Statements and empty ones",
+			script:         "select 1;\n\n select 2 ;;\n",
+			expectedResult: []string{"select 1", "select 2"},
+		},
+		{
+			name:           "Semicolons in strings and identifiers",
+			script:         "insert into \"a;b\" values ('x;''y', E'z\\';w');select 3",
+			expectedResult: []string{"insert into \"a;b\" values ('x;''y', E'z\\';w')", "select 3"},
+		},
+		{
+			name:           "Semicolons in comments",
+			script:         "-- first; comment\nselect 1; /* block /* nested; */ still; */ select 2;\n--drop table a;\n",
+			expectedResult: []string{"-- first; comment\nselect 1", "/* block /* nested; */ still; */ select 2"},
+		},
+		{
+			name: "Dollar-quoted bodies",
+			script: "do $$ begin raise notice 'a;b'; end $$;\n" +
+				"create function f() returns int as $body$ select $1; $body$ language sql;select a$b from t",
+			expectedResult: []string{
+				"do $$ begin raise notice 'a;b'; end $$",
+				"create function f() returns int as $body$ select $1; $body$ language sql",
+				"select a$b from t",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			result := SplitSQL(testCase.script)
+
+			// Then
+			require.Equal(t, testCase.expectedResult, result)
+		})
+	}
+}